@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/cache"
+)
+
+// NewCacheCommand creates the cache command
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the on-disk message preview cache",
+		Long: `The TUI keeps message previews in a cache file so that reopening it
+doesn't re-query every session. Entries are discarded automatically when
+their session file changes.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove the message preview cache",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheClear,
+	})
+
+	return cmd
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	path, err := cache.Path()
+	if err != nil {
+		return err
+	}
+	if err := cache.Clear(path); err != nil {
+		return err
+	}
+	fmt.Printf("Cleared %s\n", path)
+	return nil
+}
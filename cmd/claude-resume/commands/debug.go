@@ -2,33 +2,64 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/strrl/claude-resume/internal/sessions"
 )
 
+var (
+	debugRole         string
+	debugIncludeTools bool
+)
+
 // NewDebugCommand creates the debug-session command
 func NewDebugCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "debug-session <session-id>",
 		Short: "Debug a specific session to see raw data",
 		Args:  cobra.ExactArgs(1),
 		RunE:  runDebugSession,
 	}
+
+	cmd.Flags().StringVar(&debugRole, "role", sessions.DebugRoleUser, "Role of the messages to show: user, assistant or all")
+	cmd.Flags().BoolVar(&debugIncludeTools, "include-tools", false, "Show the input of tool calls and the output of tool results")
+
+	return cmd
 }
 
 func runDebugSession(cmd *cobra.Command, args []string) error {
 	sessionID := args[0]
+	switch debugRole {
+	case sessions.DebugRoleUser, sessions.DebugRoleAssistant, sessions.DebugRoleAll:
+	default:
+		return fmt.Errorf("invalid --role '%s': must be user, assistant or all", debugRole)
+	}
 	
 	fmt.Printf("Debugging session: %s\n", sessionID)
 	fmt.Println("==========================================")
 	
 	// Try to fetch raw data about this session
-	debugInfo, err := sessions.DebugSessionMessages(sessionID)
+	debugInfo, err := sessions.DebugSessionMessages(sessionID, sessions.DebugOptions{
+		Role:         debugRole,
+		IncludeTools: debugIncludeTools,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to debug session: %w", err)
 	}
 	
+	// Display the backing files so the raw JSONL can be inspected
+	if len(debugInfo.Files) == 0 {
+		fmt.Println("\nSource files: none found")
+	} else {
+		fmt.Printf("\nSource files: %s\n", strings.Join(debugInfo.Files, ", "))
+	}
+	
+	// Report the fields each file records, so that incompatibilities can be told apart
+	for _, file := range debugInfo.Files {
+		printSchema(file)
+	}
+
 	// Display summary if available
 	if debugInfo.Summary != "" {
 		fmt.Println("\n=== SESSION SUMMARY ===")
@@ -50,4 +81,27 @@ func runDebugSession(cmd *cobra.Command, args []string) error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// printSchema prints the schema sessions.DetectSchemaVersion detects in a session file
+func printSchema(path string) {
+	fmt.Printf("\n=== SCHEMA: %s ===\n", path)
+	schema, err := sessions.DetectSchemaVersion(path)
+	if err != nil {
+		fmt.Printf("Failed to detect schema: %v\n", err)
+		return
+	}
+	fmt.Printf("Sampled events: %d", schema.Events)
+	if schema.Malformed > 0 {
+		fmt.Printf(" (%d malformed lines)", schema.Malformed)
+	}
+	fmt.Println()
+	fmt.Printf("Claude Code version: %s\n", schema.Version())
+	fmt.Printf("Fields: %s\n", strings.Join(schema.Present(), ", "))
+	if missing := schema.Missing(); len(missing) > 0 {
+		fmt.Printf("Missing fields: %s\n", strings.Join(missing, ", "))
+	}
+	for _, note := range schema.Notes() {
+		fmt.Printf("Note: %s\n", note)
+	}
+}
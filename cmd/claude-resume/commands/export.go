@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+var (
+	exportOutput string
+	exportFormat string
+)
+
+// NewExportCommand creates the export command
+func NewExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <project> <session-id>",
+		Short: "Export a full session transcript as Markdown or JSON",
+		Long: `Export the complete, untruncated conversation of a session.
+The Markdown format renders user and assistant turns as sections and
+tool calls as collapsible details blocks, suitable for sharing.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runExport,
+	}
+
+	cmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the transcript to a file instead of stdout")
+	cmd.Flags().StringVar(&exportFormat, "format", "markdown", "Output format: markdown or json")
+
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	projectName, sessionID := args[0], args[1]
+
+	if exportFormat != "markdown" && exportFormat != "json" {
+		return fmt.Errorf("unsupported format '%s': must be markdown or json", exportFormat)
+	}
+
+	ctx, cancel := listingContext(cmd)
+	defer cancel()
+
+	targetProject, targetSession, err := findSession(ctx, projectName, sessionID)
+	if err != nil {
+		return err
+	}
+
+	messages, err := sessions.FetchMessagesContext(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch messages: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if exportFormat == "json" {
+		return writeTranscriptJSON(out, messages)
+	}
+	return writeTranscriptMarkdown(out, targetProject, targetSession, messages)
+}
+
+// findSession looks up a session among the sessions of the named project
+func findSession(ctx context.Context, projectName, sessionID string) (*models.Project, *models.Session, error) {
+	targetProject, err := findProject(ctx, projectName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	projectSessions, _, err := sessions.FetchSessionsPageContext(ctx, targetProject.Path, sessions.PageLimit(), 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch sessions: %w", err)
+	}
+
+	for _, session := range projectSessions {
+		if session.SessionID == sessionID {
+			return targetProject, &session, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("session '%s' not found in project '%s'", sessionID, projectName)
+}
+
+func writeTranscriptJSON(w io.Writer, messages []models.Message) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(toJSONMessages(messages))
+}
+
+func writeTranscriptMarkdown(w io.Writer, project *models.Project, session *models.Session, messages []models.Message) error {
+	var b strings.Builder
+
+	title := session.Summary
+	if title == "" {
+		title = "Session " + session.SessionID
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "- **Project:** %s (`%s`)\n", project.Name, project.Path)
+	fmt.Fprintf(&b, "- **Session ID:** `%s`\n", session.SessionID)
+	fmt.Fprintf(&b, "- **Last Activity:** %s\n", formatAbsoluteTime(session.LastActivity))
+
+	lastRole := ""
+	for _, msg := range messages {
+		// Messages that carry only tool traffic are attached to the current section
+		if msg.Content != "" || msg.Role != lastRole {
+			fmt.Fprintf(&b, "\n## %s\n\n", roleTitle(msg.Role))
+			if !msg.Timestamp.IsZero() {
+				fmt.Fprintf(&b, "_%s_\n\n", formatAbsoluteTime(msg.Timestamp))
+			}
+			lastRole = msg.Role
+		}
+
+		if msg.Content != "" {
+			b.WriteString(msg.Content + "\n\n")
+		}
+
+		for _, call := range msg.ToolCalls {
+			writeDetails(&b, "🔧 "+call.Name, call.Input, "json")
+		}
+
+		for _, result := range msg.ToolResults {
+			writeDetails(&b, "↩ Tool result", result, "")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeDetails renders a collapsible HTML details block with a fenced body
+func writeDetails(b *strings.Builder, summary, body, lang string) {
+	fmt.Fprintf(b, "<details>\n<summary>%s</summary>\n\n", summary)
+	if body != "" {
+		fence := codeFence(body)
+		fmt.Fprintf(b, "%s%s\n%s\n%s\n\n", fence, lang, strings.TrimRight(body, "\n"), fence)
+	}
+	b.WriteString("</details>\n\n")
+}
+
+// codeFence returns a backtick fence longer than any backtick run inside s,
+// so fenced blocks embedded in tool output cannot terminate the block early
+func codeFence(s string) string {
+	longest, current := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			current++
+			if current > longest {
+				longest = current
+			}
+		} else {
+			current = 0
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+func roleTitle(role string) string {
+	switch role {
+	case "user":
+		return "User"
+	case "assistant":
+		return "Assistant"
+	case "summary":
+		return "Summary"
+	default:
+		return role
+	}
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+var (
+	grepIgnoreCase bool
+	grepContext    int
+)
+
+// NewGrepCommand creates the grep command
+func NewGrepCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grep <project> <session-id> <pattern>",
+		Short: "Search a session's messages with a regular expression",
+		Long: `Search the full text of a session's messages, including tool calls and
+results, with a regular expression (Go RE2 syntax). Each matching line is
+printed with the position of its message and the message's role, to locate
+an exchange in a long session before resuming it.`,
+		Args: cobra.ExactArgs(3),
+		RunE: runGrep,
+	}
+
+	cmd.Flags().BoolVarP(&grepIgnoreCase, "ignore-case", "i", false, "Match case-insensitively")
+	cmd.Flags().IntVarP(&grepContext, "context", "C", 0, "Print this many lines of context around each match")
+
+	return cmd
+}
+
+func runGrep(cmd *cobra.Command, args []string) error {
+	projectName, sessionID, pattern := args[0], args[1], args[2]
+
+	if grepContext < 0 {
+		return fmt.Errorf("invalid --context %d: must not be negative", grepContext)
+	}
+	if grepIgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	ctx, cancel := listingContext(cmd)
+	defer cancel()
+
+	if _, _, err := findSession(ctx, projectName, sessionID); err != nil {
+		return err
+	}
+	messages, err := sessions.FetchMessagesContext(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch messages: %w", err)
+	}
+
+	lines := sessions.GrepMessages(messages, re, grepContext)
+	if len(lines) == 0 {
+		return fmt.Errorf("no matches for '%s' in session %s", args[2], sessionID)
+	}
+	for i, line := range lines {
+		if grepContext > 0 && i > 0 && !line.Follows(lines[i-1]) {
+			fmt.Println("--")
+		}
+		// Like grep, a colon marks a matching line and a dash a line of context
+		separator := "-"
+		if line.Match {
+			separator = ":"
+		}
+		fmt.Printf("#%d %s%s%d%s %s\n", line.Message, line.Role, separator, line.Line, separator, line.Text)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+var removeIndex bool
+
+// NewIndexCommand creates the index command
+func NewIndexCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Build a session index to speed up listings",
+		Long: `Build a compact index of the session files, with their projects, session
+IDs, timestamps and titles, under the cache directory. Listings then read it
+instead of parsing every session file, and read only the files changed since
+it was built directly. Run the command again to fold those changes in: only
+the new and changed files are read.
+
+Message previews, transcripts, grep and stats always read the session files.`,
+		Args: cobra.NoArgs,
+		RunE: runIndex,
+	}
+
+	cmd.Flags().BoolVar(&removeIndex, "remove", false, "Delete the index, so that listings read the session files only")
+
+	return cmd
+}
+
+func runIndex(cmd *cobra.Command, args []string) error {
+	if removeIndex {
+		dir, err := sessions.RemoveIndex()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s\n", dir)
+		return nil
+	}
+
+	stats, err := sessions.BuildIndex(cmd.Context())
+	if err != nil {
+		return err
+	}
+	fmt.Println(stats.Message())
+	return nil
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+var lastMode bool
+
+// NewLastCommand creates the last command
+func NewLastCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "last",
+		Short: "Resume the most recent session without any interaction",
+		Long: `Resume the most recently active session across all projects right away,
+without opening the TUI. The session and project are printed before claude
+starts, so a wrong pick can still be interrupted with ctrl+c.
+Arguments after a "--" separator are forwarded to claude.`,
+		RunE: runLast,
+	}
+
+	cmd.Flags().StringVar(&resumeCwd, "cwd", "", "Resume in this directory instead of the session's recorded project directory")
+
+	return cmd
+}
+
+func runLast(cmd *cobra.Command, args []string) error {
+	return resumeLastSession(passthroughArgs(cmd, args))
+}
+
+// resumeLastSession resumes the most recently active session across all projects
+func resumeLastSession(extraArgs []string) error {
+	recent, err := sessions.FetchRecentSessionsGlobal(1)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the most recent session: %w", err)
+	}
+	if len(recent) == 0 {
+		fmt.Println(sessions.EmptyGuidance())
+		return nil
+	}
+	return resumeDirectly(recent[0], extraArgs)
+}
+
+// resumeDirectly resumes a session without any interaction, printing which one
+// first so that a wrong pick can still be interrupted
+func resumeDirectly(session models.Session, extraArgs []string) error {
+	projectPath := session.ProjectPath
+	if resumeCwd != "" {
+		if info, err := os.Stat(resumeCwd); err != nil || !info.IsDir() {
+			return fmt.Errorf("invalid --cwd %s: not a directory", resumeCwd)
+		}
+		projectPath = resumeCwd
+	} else if !sessions.ProjectDirExists(projectPath) {
+		return fmt.Errorf("project directory %s no longer exists; pass --cwd to resume elsewhere", projectPath)
+	}
+
+	summary := session.Summary
+	if summary == "" {
+		summary = "No Summary"
+	}
+	fmt.Printf("Resuming %s\n", summary)
+	fmt.Printf("Project: %s (%s)\n", sessions.ProjectName(session.ProjectPath), session.ProjectPath)
+	fmt.Printf("Session: %s, last active %s\n", session.SessionID, formatTime(session.LastActivity))
+	warnActiveSession(session)
+
+	return sessions.ExecuteClaudeResume(session.SessionID, projectPath, extraArgs...)
+}
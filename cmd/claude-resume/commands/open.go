@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+// NewOpenCommand creates the open command
+func NewOpenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open <project>",
+		Short: "Resume the most recent session of a project",
+		Long: `Resume the most recently active session of a project right away, skipping
+both project and session selection. The project is given by its name, part of it,
+or its full path; when several projects match, their paths are listed and nothing
+is resumed.
+Arguments after a "--" separator are forwarded to claude.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if positional := len(args) - len(passthroughArgs(cmd, args)); positional != 1 {
+				return fmt.Errorf("expected one project, got %d arguments", positional)
+			}
+			return nil
+		},
+		RunE: runOpen,
+	}
+
+	cmd.Flags().StringVar(&resumeCwd, "cwd", "", "Resume in this directory instead of the session's recorded project directory")
+
+	return cmd
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	project, err := findProject(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	latest, _, err := sessions.FetchSessionsPage(project.Path, 1, 0)
+	if err != nil {
+		return fmt.Errorf("failed to fetch sessions: %w", err)
+	}
+	if len(latest) == 0 {
+		return fmt.Errorf("project '%s' has no sessions", project.Name)
+	}
+	return resumeDirectly(latest[0], passthroughArgs(cmd, args))
+}
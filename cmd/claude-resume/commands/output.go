@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/internal/timefmt"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// jsonProject is the machine-readable representation of a project
+type jsonProject struct {
+	Name          string `json:"name"`
+	Path          string `json:"path"`
+	SessionCount  int    `json:"sessionCount"`
+	MessageCount  int    `json:"messageCount"`
+	LastActivity  string `json:"lastActivity"`
+	LatestSummary string `json:"latestSummary,omitempty"` // Only loaded with --verbose
+}
+
+// jsonSession is the machine-readable representation of a session
+type jsonSession struct {
+	SessionID      string     `json:"sessionId"`
+	ProjectName    string     `json:"projectName"`
+	ProjectPath    string     `json:"projectPath"`
+	LastActivity   string     `json:"lastActivity"`
+	Summary        string     `json:"summary"`
+	IsResumed      bool       `json:"isResumed"`
+	GitBranch      string     `json:"gitBranch,omitempty"`
+	MultipleDirs   bool       `json:"multipleDirs,omitempty"`
+	RecentMessages []string   `json:"recentMessages,omitempty"`
+	Usage          *jsonUsage `json:"usage,omitempty"`
+	FinalReply     string     `json:"finalReply,omitempty"`
+}
+
+// jsonProjectCount is the machine-readable project listing of show --count
+type jsonProjectCount struct {
+	Count        int           `json:"count"`
+	SessionCount int           `json:"sessionCount"`
+	Projects     []jsonProject `json:"projects"`
+}
+
+// jsonSessionCount is the machine-readable session listing of show --count
+type jsonSessionCount struct {
+	Count    int           `json:"count"`
+	Sessions []jsonSession `json:"sessions"`
+}
+
+// jsonMessage is the machine-readable representation of a transcript message
+type jsonMessage struct {
+	Role        string         `json:"role"`
+	Content     string         `json:"content"`
+	ToolCalls   []jsonToolCall `json:"toolCalls,omitempty"`
+	ToolResults []string       `json:"toolResults,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+// jsonToolCall is the machine-readable representation of a tool invocation
+type jsonToolCall struct {
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+// jsonUsage is the machine-readable representation of session token usage
+type jsonUsage struct {
+	InputTokens   int64    `json:"inputTokens"`
+	OutputTokens  int64    `json:"outputTokens"`
+	TotalTokens   int64    `json:"totalTokens"`
+	EstimatedCost float64  `json:"estimatedCostUsd"`
+	Models        []string `json:"models"`
+}
+
+// jsonStats is the machine-readable representation of usage statistics
+type jsonStats struct {
+	Project                     string           `json:"project,omitempty"`
+	Sessions                    int              `json:"sessions"`
+	Messages                    int              `json:"messages"`
+	MostActiveProject           string           `json:"mostActiveProject"`
+	BusiestDay                  string           `json:"busiestDay"`
+	AverageSessionLengthSeconds float64          `json:"averageSessionLengthSeconds"`
+	TotalTokens                 int64            `json:"totalTokens"`
+	Models                      []jsonModelStats `json:"models"`
+}
+
+// jsonModelStats is the machine-readable representation of one model's usage
+type jsonModelStats struct {
+	Model    string `json:"model"`
+	Sessions int    `json:"sessions"`
+	Messages int    `json:"messages"`
+}
+
+// formatTime formats a timestamp for human-readable output using --date-format
+// and --time-format, or "unknown" for the zero time
+func formatTime(t time.Time) string {
+	return timefmt.Format(t, dateFormat, timeFormat)
+}
+
+// formatAbsoluteTime formats a timestamp using --date-format only, for output
+// that is kept around and would make relative times misleading
+func formatAbsoluteTime(t time.Time) string {
+	return timefmt.Format(t, dateFormat, "absolute")
+}
+
+// formatJSONTime formats a timestamp as RFC3339 in UTC, or "" for the zero time
+func formatJSONTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func toJSONProjects(projects []models.Project) []jsonProject {
+	result := make([]jsonProject, 0, len(projects))
+	for _, project := range projects {
+		result = append(result, jsonProject{
+			Name:          project.Name,
+			Path:          project.Path,
+			SessionCount:  project.SessionCount,
+			MessageCount:  project.MessageCount,
+			LastActivity:  formatJSONTime(project.LastActivity),
+			LatestSummary: project.LatestSummary,
+		})
+	}
+	return result
+}
+
+func toJSONSession(session models.Session) jsonSession {
+	return jsonSession{
+		SessionID:    session.SessionID,
+		ProjectName:  sessions.ProjectName(session.ProjectPath),
+		ProjectPath:  session.ProjectPath,
+		LastActivity: formatJSONTime(session.LastActivity),
+		Summary:      session.Summary,
+		IsResumed:    session.IsResumed,
+		GitBranch:    session.GitBranch,
+		MultipleDirs: session.MultipleDirs,
+	}
+}
+
+func toJSONStats(stats *sessions.GlobalStats, projectPath string) jsonStats {
+	models := make([]jsonModelStats, 0, len(stats.Models))
+	for _, model := range stats.Models {
+		models = append(models, jsonModelStats{Model: model.Model, Sessions: model.Sessions, Messages: model.Messages})
+	}
+	return jsonStats{
+		Project:                     projectPath,
+		Sessions:                    stats.Sessions,
+		Messages:                    stats.Messages,
+		MostActiveProject:           stats.MostActiveProject,
+		BusiestDay:                  stats.BusiestDay,
+		AverageSessionLengthSeconds: stats.AverageSessionLength.Seconds(),
+		TotalTokens:                 stats.TotalTokens,
+		Models:                      models,
+	}
+}
+
+// toJSONUsage converts usage to its JSON form, returning nil when unavailable
+func toJSONUsage(usage *sessions.SessionUsage) *jsonUsage {
+	if usage == nil || !usage.Available {
+		return nil
+	}
+	return &jsonUsage{
+		InputTokens:   usage.InputTokens,
+		OutputTokens:  usage.OutputTokens,
+		TotalTokens:   usage.TotalTokens(),
+		EstimatedCost: usage.EstimatedCost(),
+		Models:        usage.Models(),
+	}
+}
+
+func toJSONMessages(messages []models.Message) []jsonMessage {
+	result := make([]jsonMessage, 0, len(messages))
+	for _, msg := range messages {
+		jm := jsonMessage{
+			Role:        msg.Role,
+			Content:     msg.Content,
+			ToolResults: msg.ToolResults,
+			Timestamp:   formatJSONTime(msg.Timestamp),
+		}
+		for _, call := range msg.ToolCalls {
+			tc := jsonToolCall{Name: call.Name}
+			if call.Input != "" {
+				tc.Input = json.RawMessage(call.Input)
+			}
+			jm.ToolCalls = append(jm.ToolCalls, tc)
+		}
+		result = append(result, jm)
+	}
+	return result
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
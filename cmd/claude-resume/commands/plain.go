@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// errMenuBack is returned by prompt when the user asks to go back
+var errMenuBack = errors.New("back")
+
+// plainMenu is a numbered, line-based replacement for the TUI on terminals
+// that cannot handle the alternate screen or ANSI escapes
+type plainMenu struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+func newPlainMenu(in io.Reader, out io.Writer) *plainMenu {
+	return &plainMenu{in: bufio.NewScanner(in), out: out}
+}
+
+// selectSession lets the user pick a project and then one of its sessions,
+// or one of the recent sessions if recent is set. It returns nil when the
+// user quits or input ends.
+func (p *plainMenu) selectSession(recent bool) (*models.Session, error) {
+	if recent {
+		return p.selectRecentSession()
+	}
+
+	projects, err := sessions.FetchProjectsWithStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+	if len(projects) == 0 {
+		fmt.Fprintln(p.out, emptyProjectsNotice(0))
+		return nil, nil
+	}
+
+	for {
+		fmt.Fprintln(p.out, "Projects:")
+		for i, project := range projects {
+			fmt.Fprintf(p.out, "%3d. %s (%d sessions) - Last Active: %s\n",
+				i+1, project.Name, project.SessionCount, formatTime(project.LastActivity))
+		}
+		index, err := p.prompt("Select a project", len(projects), false)
+		if err != nil || index < 0 {
+			return nil, err
+		}
+
+		project := projects[index]
+		projectSessions, err := sessions.FetchSessionsForProject(project.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch sessions: %w", err)
+		}
+		if len(projectSessions) == 0 {
+			fmt.Fprintf(p.out, "No sessions found for project '%s'\n\n", project.Name)
+			continue
+		}
+
+		fmt.Fprintf(p.out, "\nSessions for project '%s':\n", project.Name)
+		p.printSessions(projectSessions, false)
+		index, err = p.prompt("Select a session", len(projectSessions), true)
+		if errors.Is(err, errMenuBack) {
+			fmt.Fprintln(p.out)
+			continue
+		}
+		if err != nil || index < 0 {
+			return nil, err
+		}
+		return &projectSessions[index], nil
+	}
+}
+
+// selectRecentSession lets the user pick one of the most recent sessions across all projects
+func (p *plainMenu) selectRecentSession() (*models.Session, error) {
+	recent, err := sessions.FetchRecentSessionsGlobal(sessions.PageLimit())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recent sessions: %w", err)
+	}
+	if len(recent) == 0 {
+		fmt.Fprintln(p.out, "No sessions found")
+		return nil, nil
+	}
+
+	fmt.Fprintln(p.out, "Recent sessions:")
+	p.printSessions(recent, true)
+	index, err := p.prompt("Select a session", len(recent), false)
+	if err != nil || index < 0 {
+		return nil, err
+	}
+	return &recent[index], nil
+}
+
+// printSessions prints a numbered session list, labelling each session with its project if withProject is set
+func (p *plainMenu) printSessions(list []models.Session, withProject bool) {
+	for i, session := range list {
+		summary := session.Summary
+		if summary == "" {
+			summary = "No Summary"
+		}
+		if withProject {
+			summary = fmt.Sprintf("[%s] %s", sessions.ProjectName(session.ProjectPath), summary)
+		}
+		fmt.Fprintf(p.out, "%3d. %s\n", i+1, summary)
+		fmt.Fprintf(p.out, "     Last Active: %s  ID: %s\n", formatTime(session.LastActivity), session.SessionID)
+	}
+}
+
+// prompt reads a selection between 1 and count, re-prompting on invalid input.
+// It returns the zero-based index, -1 when the user quits or input ends, or
+// errMenuBack when back is allowed and the user enters b.
+func (p *plainMenu) prompt(label string, count int, back bool) (int, error) {
+	hint := "q to quit"
+	if back {
+		hint = "b to go back, " + hint
+	}
+	for {
+		fmt.Fprintf(p.out, "%s [1-%d] (%s): ", label, count, hint)
+		if !p.in.Scan() {
+			fmt.Fprintln(p.out)
+			if err := p.in.Err(); err != nil {
+				return -1, fmt.Errorf("failed to read selection: %w", err)
+			}
+			return -1, nil
+		}
+
+		input := strings.TrimSpace(p.in.Text())
+		switch {
+		case input == "q":
+			return -1, nil
+		case input == "b" && back:
+			return -1, errMenuBack
+		}
+		index, err := parseSelection(input, count)
+		if err != nil {
+			fmt.Fprintln(p.out, err)
+			continue
+		}
+		return index, nil
+	}
+}
+
+// parseSelection parses a 1-based menu choice into a zero-based index
+func parseSelection(input string, count int) (int, error) {
+	n, err := strconv.Atoi(input)
+	if err != nil || n < 1 || n > count {
+		return 0, fmt.Errorf("invalid selection '%s': enter a number between 1 and %d", input, count)
+	}
+	return n - 1, nil
+}
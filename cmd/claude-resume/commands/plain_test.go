@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestPlainMenuPrompt tests that invalid selections are reported and prompted
+// for again, and that b and q leave the menu
+func TestPlainMenuPrompt(t *testing.T) {
+	tests := []struct {
+		input   string
+		back    bool
+		want    int
+		wantErr error
+		prompts int
+	}{
+		{"0\nabc\n2\n", false, 1, nil, 3},
+		{"1\n", false, 0, nil, 1},
+		{"q\n", true, -1, nil, 1},
+		{"b\n", true, -1, errMenuBack, 1},
+		{"b\n3\n", false, 2, nil, 2}, // b is invalid with nowhere to go back to
+		{"", false, -1, nil, 1},      // End of input quits
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		menu := newPlainMenu(strings.NewReader(tt.input), &out)
+		index, err := menu.prompt("Select a session", 3, tt.back)
+		if index != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("%q: got %d, %v, want %d, %v", tt.input, index, err, tt.want, tt.wantErr)
+		}
+		if prompts := strings.Count(out.String(), "Select a session [1-3]"); prompts != tt.prompts {
+			t.Errorf("%q: prompted %d times, want %d:\n%s", tt.input, prompts, tt.prompts, out.String())
+		}
+	}
+
+	var out bytes.Buffer
+	menu := newPlainMenu(strings.NewReader("0\nabc\n2\n"), &out)
+	if _, err := menu.prompt("Select a session", 3, false); err != nil {
+		t.Fatalf("prompt failed: %v", err)
+	}
+	for _, want := range []string{"invalid selection '0'", "invalid selection 'abc'"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output doesn't report %s:\n%s", want, out.String())
+		}
+	}
+}
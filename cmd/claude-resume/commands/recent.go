@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+// NewRecentCommand creates the recent command
+func NewRecentCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recent",
+		Short: "Browse the most recent sessions across all projects",
+		Long: `Open the TUI on a flat list of the most recently active sessions across
+all projects, newest first. Selecting a session resumes it directly.
+Use --limit to change how many sessions are listed. With --json or --debug
+the list is printed instead.`,
+		RunE: runRecent,
+	}
+
+	addTUIFlags(cmd)
+
+	return cmd
+}
+
+func runRecent(cmd *cobra.Command, args []string) error {
+	if !jsonOutput && !debugMode {
+		return showTUIAndResume(cmd, args, true)
+	}
+
+	recent, err := sessions.FetchRecentSessionsGlobal(pageLimit)
+	if err != nil {
+		return fmt.Errorf("failed to fetch recent sessions: %w", err)
+	}
+
+	if jsonOutput {
+		result := make([]jsonSession, 0, len(recent))
+		for _, session := range recent {
+			result = append(result, toJSONSession(session))
+		}
+		return printJSON(result)
+	}
+
+	if len(recent) == 0 {
+		fmt.Println("No sessions found")
+		return nil
+	}
+
+	fmt.Println("Recent sessions:")
+	fmt.Println("================")
+	for i, session := range recent {
+		summary := session.Summary
+		if summary == "" {
+			summary = "No Summary"
+		}
+		fmt.Printf("%d. [%s] %s\n", i+1, sessions.ProjectName(session.ProjectPath), summary)
+		fmt.Printf("   Session ID: %s\n", session.SessionID)
+		fmt.Printf("   Path: %s\n", session.ProjectPath)
+		fmt.Printf("   Last Activity: %s\n", formatTime(session.LastActivity))
+		fmt.Println()
+	}
+
+	return nil
+}
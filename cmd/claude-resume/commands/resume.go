@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+// NewResumeCommand creates the resume command
+func NewResumeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume <session-id-prefix|label>",
+		Short: "Resume a session by its ID, a unique prefix of it or its label",
+		Long: `Resume the session whose ID starts with the given prefix, searching all
+projects, like a git short hash. The full ID works too, and so does a label
+given to a single session with n in the TUI. When the prefix matches several
+sessions, the candidates are listed and nothing is resumed.
+Arguments after a "--" separator are forwarded to claude.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if positional := len(args) - len(passthroughArgs(cmd, args)); positional != 1 {
+				return fmt.Errorf("expected one session ID prefix, got %d arguments", positional)
+			}
+			return nil
+		},
+		RunE: runResume,
+	}
+
+	cmd.Flags().StringVar(&resumeCwd, "cwd", "", "Resume in this directory instead of the session's recorded project directory")
+
+	return cmd
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	session, err := sessions.ResolveSessionByPrefix(args[0])
+	var ambiguous *sessions.AmbiguousPrefixError
+	if errors.As(err, &ambiguous) {
+		fmt.Fprintf(os.Stderr, "Sessions starting with '%s':\n", ambiguous.Prefix)
+		for _, candidate := range ambiguous.Candidates {
+			fmt.Fprintf(os.Stderr, "  %s  [%s] %s\n", candidate.SessionID, sessions.ProjectName(candidate.ProjectPath), formatTime(candidate.LastActivity))
+		}
+		return fmt.Errorf("%w; type more of the session ID", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve session: %w", err)
+	}
+
+	return resumeDirectly(*session, passthroughArgs(cmd, args))
+}
@@ -1,42 +1,354 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/config"
+	"github.com/strrl/claude-resume/internal/db"
+	"github.com/strrl/claude-resume/internal/paths"
 	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/internal/timefmt"
 	"github.com/strrl/claude-resume/internal/tui"
+	"github.com/strrl/claude-resume/internal/version"
 	"github.com/strrl/claude-resume/pkg/models"
 )
 
-var debugMode bool
+var (
+	debugMode    bool
+	jsonOutput   bool
+	modelRates   []string
+	noConfirm    bool
+	sortOrder    string
+	pageLimit    int
+	previewCount int
+	dateFormat   string
+	timeFormat   string
+	themeName    string
+	claudePath   string
+	projectDir   string
+	watchMode    bool
+	noMarkdown   bool
+	noCache      bool
+	colorMode    string
+	resumeCwd    string
+	messagesOnly bool
+	sidechains   bool
+	plainMode    bool
+	verbose      bool
+	useColor     bool
+	dryRun       bool
+	printCommand bool
+	strategy     string
+	noResumePos  bool
+	queryTimeout time.Duration
+	maxFiles     int
+	maxScanMB    int
+	terminal     string
+	freshAge     time.Duration
+	recentAge    time.Duration
+	projFilter   string
+	openCmd      string
+	groupByDay   bool
+	fullIDs      bool
+	newestFirst  bool
+	messageLines int
+	idleTimeout  time.Duration
+	messageTypes []string
+)
+
+// defaultQueryTimeout bounds non-interactive listings, so that a huge corpus can't hang them forever
+const defaultQueryTimeout = 5 * time.Minute
 
 // NewRootCommand creates the root command
 func NewRootCommand() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "claude-resume",
 		Short: "Browse and resume recent Claude Code sessions",
-		Long:  `claude-resume is a TUI application for browsing and resuming recent Claude Code sessions.`,
-		RunE:  runTUI,
+		Long: `claude-resume is a TUI application for browsing and resuming recent Claude Code sessions.
+
+Arguments after a "--" separator are forwarded to claude when resuming, e.g.
+  claude-resume -- --model opus`,
+		RunE:              runTUI,
+		PersistentPreRunE: applySettings,
+		Version:           version.Version,
+		// Execute reports errors, rephrased where that helps
+		SilenceErrors: true,
 	}
+	// --version prints the same build info as the version command
+	rootCmd.SetVersionTemplate(version.Get().String())
+
+	// The config file is read once a command runs, see applyConfig
+	cfg := config.Default()
 
 	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "Run in debug mode (list sessions without TUI)")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Emit machine-readable JSON from non-interactive listing commands")
+	addTUIFlags(rootCmd)
+	rootCmd.Flags().BoolVar(&lastMode, "last", false, "Resume the most recent session across all projects without opening the TUI")
+	rootCmd.PersistentFlags().StringArrayVar(&modelRates, "model-rate", nil, "Override cost estimate rate as family=input:output USD per million tokens (e.g. opus=15:75)")
+	rootCmd.PersistentFlags().StringVar(&sortOrder, "sort", cfg.SortOrder, "Project sort order: "+strings.Join(sessions.SortOrders, ", "))
+	rootCmd.PersistentFlags().IntVar(&pageLimit, "limit", cfg.PageLimit, "Maximum number of projects or sessions to list")
+	rootCmd.PersistentFlags().IntVar(&previewCount, "preview-count", cfg.PreviewCount, "Number of messages previewed from the start and end of a session")
+	rootCmd.PersistentFlags().BoolVar(&messagesOnly, "messages-only", false, "Leave tool calls and tool results out of message previews")
+	rootCmd.PersistentFlags().BoolVar(&sidechains, "include-sidechains", false, "Include the sidechain events of subagents in message previews and conversations")
+	rootCmd.PersistentFlags().StringSliceVar(&messageTypes, "message-types", cfg.MessageTypes, "Comma-separated content counted and previewed as messages: "+strings.Join(sessions.MessageTypes, ", "))
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Show the summary of each project's most recent session in project listings")
+	rootCmd.PersistentFlags().StringVar(&dateFormat, "date-format", cfg.DateFormat, "Go time layout used to display timestamps")
+	rootCmd.PersistentFlags().StringVar(&timeFormat, "time-format", cfg.TimeFormat, "Timestamp display: "+strings.Join(timefmt.Modes, ", "))
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "Colorize output: auto, always or never (auto honors NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&themeName, "theme", cfg.Theme, "TUI color theme: "+strings.Join(tui.ThemeNames(), ", "))
+	rootCmd.PersistentFlags().DurationVar(&freshAge, "fresh-age", cfg.FreshAge, "In the TUI, show sessions active within this long in green")
+	rootCmd.PersistentFlags().DurationVar(&recentAge, "recent-age", cfg.RecentAge, "In the TUI, show sessions active within this long in yellow, and older ones dimmed")
+	rootCmd.PersistentFlags().IntVar(&messageLines, "message-lines", cfg.MessageLines, "In the TUI conversation, show at most this many lines of each message until expanded with e (0 for all)")
+	rootCmd.PersistentFlags().StringVar(&claudePath, "claude-path", cfg.ClaudePath, "Path to the claude binary (auto-detected when empty)")
+	rootCmd.PersistentFlags().IntVar(&maxFiles, "max-files", cfg.MaxFiles, "Read only the newest session files of a history with more than this many (0 for no limit)")
+	rootCmd.PersistentFlags().IntVar(&maxScanMB, "max-scan-mb", cfg.MaxScanMB, "Read only the newest session files of a history larger than this many megabytes (0 for no limit)")
+	rootCmd.PersistentFlags().StringVar(&terminal, "terminal", cfg.Terminal, "Resume in a new terminal window: a preset ("+strings.Join(sessions.TerminalPresets(), ", ")+") or a command such as 'wezterm start --cwd {dir} --'")
+	rootCmd.PersistentFlags().DurationVar(&queryTimeout, "timeout", defaultQueryTimeout, "Give up on non-interactive listings that take longer than this (0 for no limit)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the claude binary, arguments and directory a resume would use instead of running it")
+	rootCmd.PersistentFlags().StringVar(&strategy, "strategy", "resume", "How claude is told the session: resume (--resume <id>) or continue (--continue when it is the project's latest session, else --resume <id>)")
+	rootCmd.PersistentFlags().BoolVar(&printCommand, "print-command", false, "Print the shell command resuming the session (cd <project> && claude --resume <id>) instead of running it")
+	rootCmd.PersistentFlags().StringVar(&projFilter, "project-filter", cfg.ProjectFilter, "Only list projects whose path matches this glob (e.g. '~/work/*'), or regular expression prefixed with re:")
+	rootCmd.PersistentFlags().StringVar(&projectDir, "project-dir", cfg.ProjectDir, "Claude Code projects directory (defaults to $CLAUDE_CONFIG_DIR/projects or ~/.claude/projects)")
 	rootCmd.AddCommand(NewShowCommand())
 	rootCmd.AddCommand(NewDebugCommand())
+	rootCmd.AddCommand(NewExportCommand())
+	rootCmd.AddCommand(NewGrepCommand())
+	rootCmd.AddCommand(NewCacheCommand())
+	rootCmd.AddCommand(NewIndexCommand())
+	rootCmd.AddCommand(NewTrashCommand())
+	rootCmd.AddCommand(NewStatsCommand())
+	rootCmd.AddCommand(NewRecentCommand())
+	rootCmd.AddCommand(NewLastCommand())
+	rootCmd.AddCommand(NewResumeCommand())
+	rootCmd.AddCommand(NewOpenCommand())
+	rootCmd.AddCommand(NewVersionCommand())
 
 	return rootCmd
 }
 
+// addTUIFlags registers the flags of commands that open the TUI
+func addTUIFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&noConfirm, "no-confirm", false, "Resume immediately on enter without a confirmation screen")
+	cmd.Flags().BoolVar(&watchMode, "watch", false, "Refresh the project and session lists when session files change")
+	cmd.Flags().BoolVar(&noMarkdown, "no-markdown", false, "Show conversations as plain text instead of rendering Markdown")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Don't read or write the on-disk message preview cache")
+	cmd.Flags().StringVar(&resumeCwd, "cwd", "", "Resume in this directory instead of the session's recorded project directory")
+	cmd.Flags().BoolVar(&noResumePos, "no-resume-position", false, "Start at the top of the lists instead of on the project and session selected last time")
+	cmd.Flags().StringVar(&openCmd, "open-cmd", "", "Command the o key opens a project directory with, {dir} standing for it (default $EDITOR, else the file manager)")
+	cmd.Flags().DurationVar(&idleTimeout, "idle-timeout", 0, "Quit the TUI without resuming after this long without a keypress, e.g. 30m (0 to never quit)")
+	cmd.Flags().BoolVar(&groupByDay, "group-by-day", false, "Separate the session list into days with date headers (toggle with D)")
+	cmd.Flags().BoolVar(&fullIDs, "full-ids", false, "Show whole session IDs in the session list when they fit (toggle with I)")
+	cmd.Flags().BoolVar(&newestFirst, "newest-first", false, "List the full conversation newest message first (toggle with O)")
+	cmd.Flags().BoolVar(&plainMode, "plain", false, "Pick a session from numbered menus instead of the TUI (default when stdout is not a terminal)")
+}
+
 // Execute runs the root command
 func Execute() {
 	rootCmd := NewRootCommand()
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	db.Close() // os.Exit skips deferred calls
+	if err != nil {
+		// Listings interrupted with ctrl+c, see listingContext
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "Interrupted")
+			os.Exit(130)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("%w (raise --timeout, or pass --timeout 0 for no limit)", err)
+		}
+		// Reported alone, however deep the lookup of a default directory failed
+		if errors.Is(err, paths.ErrNoHomeDir) {
+			err = fmt.Errorf("%w: set HOME, CLAUDE_CONFIG_DIR or --project-dir", paths.ErrNoHomeDir)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// listingContext returns the context of a non-interactive listing, limited to
+// --timeout. ctrl+c cancels it, so that its queries are abandoned and the
+// listing returns instead of the process being killed mid-query.
+func listingContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	if queryTimeout <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// applySettings validates the persistent flags and applies them to the sessions package
+func applySettings(cmd *cobra.Command, args []string) error {
+	// Completions are requested on every tab, so they don't warn about the config file
+	if cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+		return nil
+	}
+	applyConfig(cmd)
+
+	if err := sessions.SetSortOrder(sortOrder); err != nil {
+		return err
+	}
+	if pageLimit <= 0 {
+		return fmt.Errorf("invalid --limit %d: must be positive", pageLimit)
+	}
+	sessions.SetPageLimit(pageLimit)
+	if previewCount <= 0 {
+		return fmt.Errorf("invalid --preview-count %d: must be positive", previewCount)
+	}
+	sessions.SetPreviewCount(previewCount)
+	sessions.SetMessagesOnly(messagesOnly)
+	sessions.SetIncludeSidechains(sidechains)
+	if err := sessions.SetMessageTypes(messageTypes); err != nil {
+		return err
+	}
+	sessions.SetProjectSummaries(verbose)
+	sessions.SetClaudeBinary(claudePath)
+	sessions.SetTerminal(terminal)
+	sessions.SetDryRun(dryRun)
+	sessions.SetPrintCommand(printCommand)
+	if err := sessions.SetResumeStrategy(strategy); err != nil {
+		return err
+	}
+	sessions.SetProjectsDir(projectDir)
+	if err := sessions.SetProjectFilter(projFilter); err != nil {
+		return err
+	}
+	if maxFiles < 0 {
+		return fmt.Errorf("invalid --max-files %d: must not be negative", maxFiles)
+	}
+	if maxScanMB < 0 {
+		return fmt.Errorf("invalid --max-scan-mb %d: must not be negative", maxScanMB)
+	}
+	sessions.SetScanBudget(sessions.ScanBudget{MaxFiles: maxFiles, MaxBytes: int64(maxScanMB) << 20})
+
+	var err error
+	if useColor, err = resolveColor(colorMode); err != nil {
+		return err
+	}
+
+	if !timefmt.Valid(timeFormat) {
+		return fmt.Errorf("invalid --time-format '%s': must be one of %s", timeFormat, strings.Join(timefmt.Modes, ", "))
+	}
+
+	if freshAge <= 0 {
+		return fmt.Errorf("invalid --fresh-age %s: must be positive", freshAge)
+	}
+	if recentAge < freshAge {
+		return fmt.Errorf("invalid --recent-age %s: must not be shorter than --fresh-age %s", recentAge, freshAge)
+	}
+
+	validTheme := false
+	for _, name := range tui.ThemeNames() {
+		if name == themeName {
+			validTheme = true
+			break
+		}
+	}
+	if !validTheme {
+		return fmt.Errorf("invalid theme '%s': must be one of %s", themeName, strings.Join(tui.ThemeNames(), ", "))
+	}
+
+	if err := applyModelRates(modelRates); err != nil {
+		return err
+	}
+	// The arguments and flags are valid, so later errors aren't about usage
+	cmd.SilenceUsage = true
+	return nil
+}
+
+// applyConfig reads the config file into the config-backed flags the command
+// line leaves unset, so flags always win. It runs once a command does rather
+// than when the commands are built, so --help doesn't warn about the file.
+func applyConfig(cmd *cobra.Command) {
+	cfg := config.Load()
+	flags := cmd.Flags()
+	unset := func(name string) bool { return !flags.Changed(name) }
+
+	if unset("sort") {
+		sortOrder = cfg.SortOrder
+	}
+	if unset("limit") {
+		pageLimit = cfg.PageLimit
+	}
+	if unset("preview-count") {
+		previewCount = cfg.PreviewCount
+	}
+	if unset("message-types") {
+		messageTypes = cfg.MessageTypes
+	}
+	if unset("date-format") {
+		dateFormat = cfg.DateFormat
+	}
+	if unset("time-format") {
+		timeFormat = cfg.TimeFormat
+	}
+	if unset("theme") {
+		themeName = cfg.Theme
+	}
+	if unset("fresh-age") {
+		freshAge = cfg.FreshAge
+	}
+	if unset("recent-age") {
+		recentAge = cfg.RecentAge
+	}
+	if unset("message-lines") {
+		messageLines = cfg.MessageLines
+	}
+	if unset("claude-path") {
+		claudePath = cfg.ClaudePath
+	}
+	if unset("max-files") {
+		maxFiles = cfg.MaxFiles
+	}
+	if unset("max-scan-mb") {
+		maxScanMB = cfg.MaxScanMB
+	}
+	if unset("terminal") {
+		terminal = cfg.Terminal
+	}
+	if unset("project-filter") {
+		projFilter = cfg.ProjectFilter
+	}
+	if unset("project-dir") {
+		projectDir = cfg.ProjectDir
+	}
+}
+
+// applyModelRates parses --model-rate values and registers them for cost estimates
+func applyModelRates(rates []string) error {
+	for _, rate := range rates {
+		family, prices, ok := strings.Cut(rate, "=")
+		if !ok || family == "" {
+			return fmt.Errorf("invalid --model-rate '%s': expected family=input:output", rate)
+		}
+		inputStr, outputStr, ok := strings.Cut(prices, ":")
+		if !ok {
+			return fmt.Errorf("invalid --model-rate '%s': expected family=input:output", rate)
+		}
+		input, err := strconv.ParseFloat(inputStr, 64)
+		if err != nil {
+			return fmt.Errorf("invalid input rate in --model-rate '%s': %w", rate, err)
+		}
+		output, err := strconv.ParseFloat(outputStr, 64)
+		if err != nil {
+			return fmt.Errorf("invalid output rate in --model-rate '%s': %w", rate, err)
+		}
+		sessions.SetModelRate(family, sessions.ModelRate{InputPerMTok: input, OutputPerMTok: output})
+	}
+	return nil
+}
+
 func runTUI(cmd *cobra.Command, args []string) error {
 	// In debug mode, we need to fetch projects synchronously
 	if debugMode {
@@ -44,15 +356,64 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to fetch projects: %w", err)
 		}
+		if jsonOutput {
+			return printJSON(toJSONProjects(projects))
+		}
+		warnMalformedLines()
+		warnScanLimit()
 		if len(projects) == 0 {
-			fmt.Println("No projects found")
+			fmt.Println(emptyProjectsNotice(0))
 			return nil
 		}
 		return runDebugMode(projects)
 	}
 
+	if lastMode {
+		return resumeLastSession(passthroughArgs(cmd, args))
+	}
+
+	return showTUIAndResume(cmd, args, false)
+}
+
+// showTUIAndResume runs the TUI, starting in the recent sessions view if recent
+// is set, and resumes the session selected in it
+func showTUIAndResume(cmd *cobra.Command, args []string, recent bool) error {
+	if resumeCwd != "" {
+		if info, err := os.Stat(resumeCwd); err != nil || !info.IsDir() {
+			return fmt.Errorf("invalid --cwd %s: not a directory", resumeCwd)
+		}
+	}
+
+	extraArgs := passthroughArgs(cmd, args)
+	if plainMode || !stdoutIsTerminal() {
+		return plainMenuAndResume(recent, extraArgs)
+	}
+
 	// For normal TUI mode, start with empty projects and load async
-	selectedSession, err := tui.ShowTUI(nil) // Pass nil to indicate async loading
+	selectedSession, err := tui.ShowTUI(nil, tui.Options{ // Pass nil to indicate async loading
+		NoConfirm:  noConfirm,
+		ExtraArgs:  extraArgs,
+		DateFormat: dateFormat,
+		TimeFormat: timeFormat,
+		Theme:      themeName,
+		Watch:      watchMode,
+		NoMarkdown: noMarkdown,
+		NoCache:    noCache,
+		NoColor:    !useColor,
+		ResumeDir:  resumeCwd,
+		Recent:     recent,
+		Verbose:    verbose,
+
+		NoResumePosition: noResumePos,
+		OpenCmd:          openCmd,
+		FreshAge:         freshAge,
+		RecentAge:        recentAge,
+		GroupByDay:       groupByDay,
+		FullIDs:          fullIDs,
+		NewestFirst:      newestFirst,
+		MessageLines:     messageLines,
+		IdleTimeout:      idleTimeout,
+	})
 	if err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
@@ -61,16 +422,76 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	return sessions.ExecuteClaudeResume(selectedSession.SessionID, selectedSession.ProjectPath)
+	return sessions.ExecuteClaudeResume(selectedSession.SessionID, selectedSession.ProjectPath, extraArgs...)
+}
+
+// plainMenuAndResume picks a session from numbered menus on stdin and resumes it
+func plainMenuAndResume(recent bool, extraArgs []string) error {
+	selectedSession, err := newPlainMenu(os.Stdin, os.Stdout).selectSession(recent)
+	warnScanLimit()
+	if err != nil {
+		return err
+	}
+	if selectedSession == nil {
+		return nil
+	}
+
+	projectPath := selectedSession.ProjectPath
+	if resumeCwd != "" {
+		projectPath = resumeCwd
+	}
+	warnActiveSession(*selectedSession)
+	return sessions.ExecuteClaudeResume(selectedSession.SessionID, projectPath, extraArgs...)
+}
+
+// warnMalformedLines reports session files with lines that are not valid JSON,
+// which the queries skip, on stderr
+func warnMalformedLines() {
+	malformed, err := sessions.ScanMalformedLines()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to scan session files: %v\n", err)
+		return
+	}
+	for _, file := range malformed {
+		fmt.Fprintf(os.Stderr, "Warning: skipped %d malformed line(s) in %s\n", file.Lines, file.Path)
+	}
+}
+
+// warnScanLimit reports on stderr that only the newest session files were read,
+// when the history is over --max-files or --max-scan-mb
+func warnScanLimit() {
+	if limit, ok := sessions.ScanLimited(); ok {
+		fmt.Fprintf(os.Stderr, "Note: %s\n", limit.Notice())
+	}
+}
+
+// warnActiveSession warns on stderr before resuming a session that is still
+// being written, most likely by claude running in another terminal
+func warnActiveSession(session models.Session) {
+	probe := []models.Session{session}
+	if err := sessions.MarkActive(probe); err == nil && probe[0].Active {
+		fmt.Fprintf(os.Stderr, "Warning: session %s is still being written, probably by claude in another terminal; resuming it as well may conflict\n", session.SessionID)
+	}
+}
+
+// passthroughArgs returns the arguments given after a "--" separator
+func passthroughArgs(cmd *cobra.Command, args []string) []string {
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		return args[dash:]
+	}
+	return nil
 }
 
 func runDebugMode(projects []models.Project) error {
 	fmt.Println("=== Debug Mode: Projects and Sessions ===")
+	if total, err := sessions.CountProjects(); err == nil && total > len(projects) {
+		fmt.Printf("Showing %d of %d projects (raise --limit to see more)\n", len(projects), total)
+	}
 	for i, project := range projects {
 		fmt.Printf("\n%d. Project: %s\n", i+1, project.Name)
 		fmt.Printf("   Path: %s\n", project.Path)
 		fmt.Printf("   Sessions: %d\n", project.SessionCount)
-		fmt.Printf("   Last Activity: %s\n", project.LastActivity.Format("Jan 02 15:04 MST"))
+		fmt.Printf("   Last Activity: %s\n", formatTime(project.LastActivity))
 		
 		if i == 0 {
 			// Load sessions for the first project as an example
@@ -86,7 +507,7 @@ func runDebugMode(projects []models.Project) error {
 					break
 				}
 				fmt.Printf("   - %s (Session: %s)\n", 
-					session.LastActivity.Format("Jan 02 15:04 MST"),
+					formatTime(session.LastActivity),
 					session.SessionID)
 			}
 		}
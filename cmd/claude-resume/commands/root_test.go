@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestPassthroughArgs tests that the arguments after "--" are forwarded as
+// given, spaces and quotes included, and that without "--" there are none
+func TestPassthroughArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"--plain"}, nil},
+		{[]string{"--plain", "--"}, []string{}},
+		{[]string{"--plain", "--", "--model", "opus"}, []string{"--model", "opus"}},
+		{[]string{"--", "-p", "fix the tests in pkg/a b", `say "hi" and 'bye'`},
+			[]string{"-p", "fix the tests in pkg/a b", `say "hi" and 'bye'`}},
+	}
+	for _, tt := range tests {
+		var got []string
+		cmd := &cobra.Command{
+			Use: "claude-resume",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				got = passthroughArgs(cmd, args)
+				return nil
+			},
+		}
+		cmd.Flags().Bool("plain", false, "")
+		cmd.SetArgs(tt.args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%q: %v", tt.args, err)
+		}
+		if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("passthroughArgs of %q = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -1,158 +1,437 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 	"github.com/strrl/claude-resume/internal/sessions"
 	"github.com/strrl/claude-resume/pkg/models"
 )
 
+var (
+	showOffset   int
+	showRaw      bool
+	showFull     bool
+	showBranch   string
+	showResumed  bool
+	showTemplate string
+	noMessages   bool
+	finalReply   bool
+	showCount    bool
+	showFormat   string
+	noHeader     bool
+)
+
 // NewShowCommand creates the show command
 func NewShowCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "show [project] [session-id]",
 		Short: "Show projects, sessions, or messages without TUI",
 		Long: `Show projects, sessions, or messages in a non-interactive format.
 Without arguments: lists all projects
 With project name: lists all sessions in that project
-With project name and session ID: shows recent messages for that session`,
+With project name and session ID: shows recent messages for that session
+
+Use --limit and --offset to page through long listings.
+Use --branch with a project to list only the sessions on a git branch.
+Use --since-last-resume with a project to list only the sessions with activity
+since they were last resumed. Like --branch, it only filters show; the TUI lists
+every session.
+Use --no-messages with a project to list just session IDs and timestamps, which is
+much faster than loading every session's token usage and recent messages.
+Use --final-reply with a project to also show each session's last assistant reply,
+which usually tells how the session ended.
+Use --count to end a listing with the total number of projects and sessions; with
+--json the listing becomes an object holding the totals and the listed items.
+Use --raw with a session ID to print the session's original .jsonl lines unmodified.
+Use --full with a session ID to print its complete transcript instead of recent
+messages: Markdown as export writes it, or structured messages with --json.
+Use --template to print each project or session with a Go text/template instead,
+e.g. --template '{{.Name}} {{.SessionCount}} {{.LastActivity}}'.
+Use --format csv or --format tsv with a project to print its sessions as a table
+for spreadsheets, with the columns project, session_id, last_activity,
+message_count and summary, after a header row unless --no-header is given. The
+table holds every session after --offset, regardless of --limit.
+
+` + templateFieldsHelp,
 		RunE: runShow,
 	}
+
+	cmd.Flags().IntVar(&showOffset, "offset", 0, "Number of projects or sessions to skip before listing")
+	cmd.Flags().BoolVar(&showRaw, "raw", false, "Print the untouched .jsonl lines of the session, ordered by timestamp")
+	cmd.Flags().BoolVar(&showFull, "full", false, "Print the complete transcript of the session instead of its recent messages")
+	cmd.Flags().StringVar(&showBranch, "branch", "", "List only the sessions whose most recent git branch is this one")
+	cmd.Flags().BoolVar(&showResumed, "since-last-resume", false, "List only the sessions with activity since they were last resumed, leaving out dormant and never resumed ones")
+	cmd.Flags().StringVar(&showTemplate, "template", "", "Print each listed project or session with this Go text/template")
+	cmd.Flags().BoolVar(&noMessages, "no-messages", false, "List sessions without their token usage and recent messages")
+	cmd.Flags().BoolVar(&finalReply, "final-reply", false, "Show the last assistant reply of each listed session")
+	cmd.Flags().BoolVar(&showCount, "count", false, "End the listing with the total number of projects and sessions")
+	cmd.Flags().StringVar(&showFormat, "format", "text", "Output format of a project's sessions: text, csv or tsv (csv and tsv list every session)")
+	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Leave out the header row of --format csv and tsv")
+
+	return cmd
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
+	if showOffset < 0 {
+		return fmt.Errorf("invalid --offset %d: must not be negative", showOffset)
+	}
+	if finalReply && noMessages {
+		return fmt.Errorf("--final-reply cannot be combined with --no-messages")
+	}
+	if _, ok := tableFormats[showFormat]; ok {
+		if len(args) != 1 {
+			return fmt.Errorf("--format %s applies to a project's sessions. Usage: claude-resume show <project> --format %s", showFormat, showFormat)
+		}
+		if jsonOutput || showTemplate != "" || showCount || showRaw {
+			return fmt.Errorf("--format %s cannot be combined with --json, --template, --count or --raw", showFormat)
+		}
+	} else if showFormat != "text" {
+		return fmt.Errorf("unsupported format '%s': must be text, csv or tsv", showFormat)
+	}
+
+	if showRaw {
+		if len(args) != 2 {
+			return fmt.Errorf("--raw requires a project and a session ID. Usage: claude-resume show <project> <session-id> --raw")
+		}
+		if jsonOutput {
+			return fmt.Errorf("--raw cannot be combined with --json")
+		}
+		return showRawSession(cmd.Context(), args[0], args[1])
+	}
+	if showFull && len(args) != 2 {
+		return fmt.Errorf("--full requires a project and a session ID. Usage: claude-resume show <project> <session-id> --full")
+	}
+	sessions.SetBranchFilter(showBranch)
+	sessions.SetSinceLastResume(showResumed)
+
+	ctx, cancel := listingContext(cmd)
+	defer cancel()
+
+	var tmpl *template.Template
+	if showTemplate != "" {
+		if jsonOutput {
+			return fmt.Errorf("--template cannot be combined with --json")
+		}
+		if len(args) == 2 {
+			return fmt.Errorf("--template applies to project and session listings, not to a session's messages")
+		}
+		var err error
+		if tmpl, err = parseTemplate(showTemplate); err != nil {
+			return err
+		}
+	}
+
+	var err error
 	switch len(args) {
 	case 0:
 		// Show all projects
-		return showProjects()
+		err = showProjects(ctx, tmpl)
 	case 1:
 		// Show sessions for a specific project
-		return showSessions(args[0])
+		err = showSessions(ctx, args[0], tmpl)
 	case 2:
 		// Show messages for a specific session
-		return showMessages(args[0], args[1])
+		err = showMessages(ctx, args[0], args[1])
 	default:
 		return fmt.Errorf("too many arguments. Usage: claude-resume show [project] [session-id]")
 	}
+	// Report the interrupt or timeout rather than the query it cut short
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	warnScanLimit()
+	return err
 }
 
-func showProjects() error {
-	projects, err := sessions.FetchProjectsWithStats()
+func showProjects(ctx context.Context, tmpl *template.Template) error {
+	projects, total, err := sessions.FetchProjectsPageContext(ctx, pageLimit, showOffset)
 	if err != nil {
 		return fmt.Errorf("failed to fetch projects: %w", err)
 	}
 
+	// Both totals come from one query, so that they agree
+	sessionTotal := 0
+	if showCount {
+		if total, sessionTotal, err = sessions.CountTotals(ctx); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput {
+		if showCount {
+			return printJSON(jsonProjectCount{Count: total, SessionCount: sessionTotal, Projects: toJSONProjects(projects)})
+		}
+		return printJSON(toJSONProjects(projects))
+	}
+
+	if tmpl != nil {
+		for _, project := range projects {
+			if err := printTemplate(tmpl, toTemplateProject(project)); err != nil {
+				return err
+			}
+		}
+		if showCount {
+			fmt.Printf("%s, %d total sessions\n", sessions.Pluralize(total, "project"), sessionTotal)
+		}
+		return nil
+	}
+
 	if len(projects) == 0 {
-		fmt.Println("No projects found")
+		fmt.Println(emptyProjectsNotice(showOffset))
 		return nil
 	}
 
-	fmt.Println("Projects:")
+	fmt.Printf("Projects (%s):\n", sessions.FormatPageRange(showOffset, len(projects), total))
 	fmt.Println("=========")
 	for i, project := range projects {
-		fmt.Printf("%d. %s\n", i+1, project.Name)
+		fmt.Printf("%d. %s\n", showOffset+i+1, project.Name)
 		fmt.Printf("   Path: %s\n", project.Path)
 		fmt.Printf("   Sessions: %d\n", project.SessionCount)
-		fmt.Printf("   Last Activity: %s\n", project.LastActivity.Format("Jan 02 15:04 MST"))
+		fmt.Printf("   Messages: %d\n", project.MessageCount)
+		fmt.Printf("   Last Activity: %s\n", formatTime(project.LastActivity))
+		if verbose && project.LatestSummary != "" {
+			fmt.Printf("   Latest Session: %s\n", project.LatestSummary)
+		}
 		fmt.Println()
 	}
+	if showCount {
+		fmt.Printf("%s, %d total sessions\n", sessions.Pluralize(total, "project"), sessionTotal)
+	}
 	
 	return nil
 }
 
-func showSessions(projectName string) error {
-	// First, find the project by name
-	projects, err := sessions.FetchProjectsWithStats()
+// emptyProjectsNotice explains an empty page of projects starting at offset.
+// Only a listing leaving nothing out gets the guidance for having no projects.
+func emptyProjectsNotice(offset int) string {
+	switch {
+	case offset > 0:
+		return fmt.Sprintf("No projects past --offset %d", offset)
+	case projFilter != "":
+		return fmt.Sprintf("No projects match --project-filter '%s'", projFilter)
+	default:
+		return sessions.EmptyGuidance()
+	}
+}
+
+// fetchPreviews returns the recent messages of each session, or nil when they
+// can't be loaded; previews are best-effort like the rest of a session's details
+func fetchPreviews(ctx context.Context, list []models.Session) map[string][]string {
+	previews, err := sessions.FetchRecentMessagesForSessions(ctx, sessionIDsOf(list))
 	if err != nil {
-		return fmt.Errorf("failed to fetch projects: %w", err)
+		return nil
+	}
+	lines := make(map[string][]string, len(previews))
+	for id, preview := range previews {
+		lines[id] = sessions.PreviewStrings(preview)
 	}
+	return lines
+}
 
-	var targetProject *models.Project
-	for _, project := range projects {
-		if project.Name == projectName || project.Path == projectName {
-			p := project
-			targetProject = &p
-			break
-		}
+// fetchUsages returns the token usage of each session from a single scan, or
+// nil when it can't be loaded, like fetchPreviews
+func fetchUsages(ctx context.Context, list []models.Session) map[string]*sessions.SessionUsage {
+	usages, err := sessions.FetchUsageForSessions(ctx, sessionIDsOf(list))
+	if err != nil {
+		return nil
+	}
+	return usages
+}
+
+// fetchFinalReplies returns the final reply of each session from a single scan,
+// or nil when they can't be loaded, like fetchPreviews
+func fetchFinalReplies(ctx context.Context, list []models.Session) map[string]string {
+	replies, err := sessions.FetchFinalRepliesForSessions(ctx, sessionIDsOf(list))
+	if err != nil {
+		return nil
 	}
+	return replies
+}
+
+// sessionIDsOf returns the IDs of the sessions in list
+func sessionIDsOf(list []models.Session) []string {
+	ids := make([]string, 0, len(list))
+	for _, session := range list {
+		ids = append(ids, session.SessionID)
+	}
+	return ids
+}
 
-	if targetProject == nil {
-		return fmt.Errorf("project '%s' not found", projectName)
+func showSessions(ctx context.Context, projectName string, tmpl *template.Template) error {
+	// First, find the project by name
+	targetProject, err := findProject(ctx, projectName)
+	if err != nil {
+		return err
 	}
 
 	// Fetch sessions for the project
-	projectSessions, err := sessions.FetchSessionsForProject(targetProject.Path)
+	projectSessions, total, err := sessions.FetchSessionsPageContext(ctx, targetProject.Path, pageLimit, showOffset)
 	if err != nil {
 		return fmt.Errorf("failed to fetch sessions: %w", err)
 	}
 
+	if showFormat != "text" {
+		// A spreadsheet gets every session rather than a page, in one more query
+		if rest := total - showOffset - len(projectSessions); rest > 0 {
+			more, _, err := sessions.FetchSessionsPageContext(ctx, targetProject.Path, rest, showOffset+len(projectSessions))
+			if err != nil {
+				return fmt.Errorf("failed to fetch sessions: %w", err)
+			}
+			projectSessions = append(projectSessions, more...)
+		}
+		return printSessionsTable(ctx, os.Stdout, showFormat, targetProject, projectSessions, !noHeader)
+	}
+
+	// The details of every listed session come from a single scan of the history each
+	var previews map[string][]string
+	var usages map[string]*sessions.SessionUsage
+	var replies map[string]string
+	if !noMessages && tmpl == nil {
+		previews = fetchPreviews(ctx, projectSessions)
+		usages = fetchUsages(ctx, projectSessions)
+		if finalReply {
+			replies = fetchFinalReplies(ctx, projectSessions)
+		}
+		// The details are best-effort, but an interrupt stops the listing
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput {
+		result := make([]jsonSession, 0, len(projectSessions))
+		for _, session := range projectSessions {
+			js := toJSONSession(session)
+			if noMessages {
+				result = append(result, js)
+				continue
+			}
+			if usage, ok := usages[session.SessionID]; ok {
+				js.Usage = toJSONUsage(usage)
+			}
+			if messages := previews[session.SessionID]; len(messages) > 0 {
+				if len(messages) > 5 {
+					messages = messages[:5]
+				}
+				js.RecentMessages = messages
+			}
+			if finalReply {
+				js.FinalReply = replies[session.SessionID]
+			}
+			result = append(result, js)
+		}
+		if showCount {
+			return printJSON(jsonSessionCount{Count: total, Sessions: result})
+		}
+		return printJSON(result)
+	}
+
+	if tmpl != nil {
+		for _, session := range projectSessions {
+			if err := printTemplate(tmpl, toTemplateSession(session)); err != nil {
+				return err
+			}
+		}
+		if showCount {
+			fmt.Printf("%s for project '%s'\n", sessions.Pluralize(total, "session"), targetProject.Name)
+		}
+		return nil
+	}
+
 	if len(projectSessions) == 0 {
+		if showResumed {
+			fmt.Printf("No sessions of project '%s' have activity since they were last resumed\n", projectName)
+			return nil
+		}
+		if showBranch != "" {
+			fmt.Printf("No sessions found for project '%s' on branch '%s'\n", projectName, showBranch)
+			return nil
+		}
 		fmt.Printf("No sessions found for project '%s'\n", projectName)
 		return nil
 	}
 
 	fmt.Printf("Sessions for project '%s':\n", targetProject.Name)
 	fmt.Printf("Path: %s\n", targetProject.Path)
+	fmt.Println(sessions.FormatPageRange(showOffset, len(projectSessions), total))
 	fmt.Println("===================================")
 	
 	for i, session := range projectSessions {
-		fmt.Printf("%d. Session ID: %s\n", i+1, session.SessionID)
-		fmt.Printf("   Last Activity: %s\n", session.LastActivity.Format("Jan 02 15:04 MST"))
+		fmt.Printf("%d. Session ID: %s\n", showOffset+i+1, session.SessionID)
+		fmt.Printf("   Last Activity: %s\n", formatTime(session.LastActivity))
+		if session.GitBranch != "" {
+			fmt.Printf("   Branch: %s\n", session.GitBranch)
+		}
+		if session.MultipleDirs {
+			fmt.Printf("   ↗ multiple dirs, resumes in %s\n", session.ProjectPath)
+		}
+		if noMessages {
+			continue
+		}
+		if usage, ok := usages[session.SessionID]; ok {
+			fmt.Printf("   Tokens: %s\n", sessions.FormatUsage(usage))
+			fmt.Printf("   Models: %s\n", sessions.FormatModels(usage))
+		}
 		
 		// Fetch and show recent messages
-		messages, err := sessions.FetchRecentMessagesForSession(session.SessionID)
-		if err == nil && len(messages) > 0 {
+		messages := previews[session.SessionID]
+		if len(messages) > 0 {
 			fmt.Println("   Recent Messages:")
 			for j, msg := range messages {
 				if j >= 5 {
 					break
 				}
-				truncatedMsg := truncateString(msg, 50)
+				truncatedMsg := truncateString(msg, 65) // Room for the time prefix and 50 characters of text
 				fmt.Printf("     %d. %s\n", j+1, truncatedMsg)
 			}
 		}
+		if finalReply {
+			if reply := replies[session.SessionID]; reply != "" {
+				fmt.Println("   Final Reply:")
+				fmt.Printf("     >> %s\n", truncateString(strings.Join(strings.Fields(reply), " "), 65))
+			}
+		}
 		fmt.Println()
 	}
+	if showCount {
+		fmt.Printf("%s for project '%s'\n", sessions.Pluralize(total, "session"), targetProject.Name)
+	}
 	
 	return nil
 }
 
-func showMessages(projectName, sessionID string) error {
+func showMessages(ctx context.Context, projectName, sessionID string) error {
 	// First, verify the project exists
-	projects, err := sessions.FetchProjectsWithStats()
+	targetProject, err := findProject(ctx, projectName)
 	if err != nil {
-		return fmt.Errorf("failed to fetch projects: %w", err)
-	}
-
-	var targetProject *models.Project
-	for _, project := range projects {
-		if project.Name == projectName || project.Path == projectName {
-			p := project
-			targetProject = &p
-			break
-		}
-	}
-
-	if targetProject == nil {
-		return fmt.Errorf("project '%s' not found", projectName)
+		return err
 	}
 
 	// First check if the session exists for this project
-	projectSessions, err := sessions.FetchSessionsForProject(targetProject.Path)
+	projectSessions, _, err := sessions.FetchSessionsPageContext(ctx, targetProject.Path, sessions.PageLimit(), 0)
 	if err != nil {
 		return fmt.Errorf("failed to fetch sessions: %w", err)
 	}
 
-	sessionFound := false
-	for _, session := range projectSessions {
-		if session.SessionID == sessionID {
-			sessionFound = true
+	var targetSession *models.Session
+	for i := range projectSessions {
+		if projectSessions[i].SessionID == sessionID {
+			targetSession = &projectSessions[i]
 			break
 		}
 	}
 
-	if !sessionFound {
+	if targetSession == nil {
+		if jsonOutput {
+			return fmt.Errorf("session '%s' not found in project '%s'", sessionID, projectName)
+		}
 		fmt.Printf("Session '%s' not found in project '%s'\n", sessionID, projectName)
 		fmt.Printf("\nAvailable sessions in this project:\n")
 		for i, session := range projectSessions {
@@ -160,16 +439,25 @@ func showMessages(projectName, sessionID string) error {
 				fmt.Printf("... and %d more sessions\n", len(projectSessions)-10)
 				break
 			}
-			fmt.Printf("  - %s (Last activity: %s)\n", session.SessionID, session.LastActivity.Format("Jan 02 15:04 MST"))
+			fmt.Printf("  - %s (Last activity: %s)\n", session.SessionID, formatTime(session.LastActivity))
 		}
 		return nil
 	}
 
+	if showFull {
+		return showTranscript(ctx, targetProject, targetSession)
+	}
+
 	// Fetch messages for the session
-	messages, err := sessions.FetchRecentMessagesForSession(sessionID)
+	preview, err := sessions.FetchRecentMessagesForSessionContext(ctx, sessionID)
 	if err != nil {
 		return fmt.Errorf("failed to fetch messages: %w", err)
 	}
+	messages := sessions.PreviewStrings(preview)
+
+	if jsonOutput {
+		return printJSON(messages)
+	}
 
 	if len(messages) == 0 {
 		fmt.Printf("No messages found for session '%s' in project '%s'\n", sessionID, projectName)
@@ -178,11 +466,15 @@ func showMessages(projectName, sessionID string) error {
 	}
 
 	fmt.Printf("Recent messages for session '%s' in project '%s':\n", sessionID, targetProject.Name)
+	if usage, err := sessions.FetchSessionUsage(sessionID); err == nil {
+		fmt.Printf("Tokens: %s\n", sessions.FormatUsage(usage))
+		fmt.Printf("Models: %s\n", sessions.FormatModels(usage))
+	}
 	fmt.Println("================================================")
 	
 	for i, msg := range messages {
 		if i >= 5 {
-			fmt.Println("\n(showing first 5 messages only)")
+			fmt.Println("\n(showing first 5 messages only, --full for the complete transcript)")
 			break
 		}
 		fmt.Printf("\n%d. %s\n", i+1, msg)
@@ -191,9 +483,61 @@ func showMessages(projectName, sessionID string) error {
 	return nil
 }
 
+// showTranscript prints every message of a session, as structured messages with
+// --json and otherwise as the Markdown transcript export writes
+func showTranscript(ctx context.Context, project *models.Project, session *models.Session) error {
+	messages, err := sessions.FetchMessagesContext(ctx, session.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch messages: %w", err)
+	}
+	if jsonOutput {
+		return printJSON(toJSONMessages(messages))
+	}
+	return writeTranscriptMarkdown(os.Stdout, project, session, messages)
+}
+
+// showRawSession streams the original .jsonl lines of a session to stdout
+func showRawSession(ctx context.Context, projectName, sessionID string) error {
+	project, err := findProject(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	// The dump is read by session ID alone, so check the project it belongs to
+	detail, err := sessions.FetchSessionDetail(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to look up session: %w", err)
+	}
+	if detail.ProjectPath != project.Path {
+		return fmt.Errorf("session '%s' not found in project '%s'", sessionID, projectName)
+	}
+
+	if err := sessions.WriteRawSession(os.Stdout, sessionID); err != nil {
+		return fmt.Errorf("failed to dump session: %w", err)
+	}
+	return nil
+}
+
+// findProject looks up a project by name or path, forgiving typos in case and
+// partial names. When several projects match, their paths are listed on stderr
+// for the user to pick from.
+func findProject(ctx context.Context, projectName string) (*models.Project, error) {
+	project, err := sessions.ResolveProjectContext(ctx, projectName)
+	var ambiguous *sessions.AmbiguousProjectError
+	if errors.As(err, &ambiguous) {
+		fmt.Fprintf(os.Stderr, "Projects matching '%s':\n", ambiguous.Name)
+		for _, candidate := range ambiguous.Candidates {
+			fmt.Fprintf(os.Stderr, "  %s (%d sessions, last active %s)\n", candidate.Path, candidate.SessionCount, formatTime(candidate.LastActivity))
+		}
+		return nil, fmt.Errorf("%w; pass the project's full path instead", err)
+	}
+	return project, err
+}
+
+// truncateString truncates s to maxLen characters, counted in runes so that
+// multibyte characters are never split
 func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if utf8.RuneCountInString(s) <= maxLen {
 		return s
 	}
-	return s[:maxLen] + "..."
+	return string([]rune(s)[:maxLen]) + "..."
 }
\ No newline at end of file
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+var statsProject string
+
+// NewStatsCommand creates the stats command
+func NewStatsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show aggregate usage statistics",
+		Long: `Show totals across all projects: sessions, messages, the most active
+project, the busiest day of the week, the average session length, the
+tokens recorded on assistant messages and the sessions and messages per model.`,
+		Args: cobra.NoArgs,
+		RunE: runStats,
+	}
+
+	cmd.Flags().StringVar(&statsProject, "project", "", "Limit statistics to one project (name or path)")
+
+	return cmd
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	ctx, cancel := listingContext(cmd)
+	defer cancel()
+
+	projectPath := ""
+	if statsProject != "" {
+		project, err := findProject(ctx, statsProject)
+		if err != nil {
+			return err
+		}
+		projectPath = project.Path
+	}
+
+	stats, err := sessions.ComputeProjectStatsContext(ctx, projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to compute stats: %w", err)
+	}
+	warnScanLimit()
+
+	if jsonOutput {
+		return printJSON(toJSONStats(stats, projectPath))
+	}
+
+	scope := "all projects"
+	if projectPath != "" {
+		scope = projectPath
+	}
+	fmt.Printf("Statistics for %s\n\n", scope)
+
+	rows := [][2]string{
+		{"Sessions", fmt.Sprintf("%d", stats.Sessions)},
+		{"Messages", fmt.Sprintf("%d", stats.Messages)},
+		{"Most active project", valueOrNone(stats.MostActiveProject)},
+		{"Busiest day", valueOrNone(stats.BusiestDay)},
+		{"Average session", stats.AverageSessionLength.String()},
+		{"Total tokens", fmt.Sprintf("%d", stats.TotalTokens)},
+	}
+	for _, row := range rows {
+		fmt.Printf("%-21s %s\n", row[0]+":", row[1])
+	}
+
+	if len(stats.Models) > 0 {
+		fmt.Printf("\nModels\n")
+		for _, model := range stats.Models {
+			fmt.Printf("  %-30s %d sessions, %d messages\n", model.Model, model.Sessions, model.Messages)
+		}
+	}
+	return nil
+}
+
+// valueOrNone returns s, or "none" when it is empty
+func valueOrNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// tableFormats are the values of show --format that print a sessions table for spreadsheets
+var tableFormats = map[string]rune{
+	"csv": ',',
+	"tsv": '\t',
+}
+
+// tableHeader names the columns of a sessions table
+var tableHeader = []string{"project", "session_id", "last_activity", "message_count", "summary"}
+
+// printSessionsTable prints to out one row per session, separated by the
+// delimiter of format. Fields holding the delimiter, quotes or newlines, as
+// summaries often do, are quoted by encoding/csv. Message counts are left empty
+// when they can't be loaded.
+func printSessionsTable(ctx context.Context, out io.Writer, format string, project *models.Project, list []models.Session, header bool) error {
+	counts, _ := sessions.FetchMessageCounts(ctx, sessionIDsOf(list)) // Best-effort like the rest of a session's details
+
+	w := csv.NewWriter(out)
+	w.Comma = tableFormats[format]
+	if header {
+		if err := w.Write(tableHeader); err != nil {
+			return fmt.Errorf("failed to write %s: %w", format, err)
+		}
+	}
+	for _, session := range list {
+		count := ""
+		if n, ok := counts[session.SessionID]; ok {
+			count = strconv.Itoa(n)
+		}
+		row := []string{project.Path, session.SessionID, formatJSONTime(session.LastActivity), count, session.Summary}
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("failed to write %s: %w", format, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", format, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// TestPrintSessionsTable tests that fields holding the delimiter, quotes or
+// newlines are quoted, and that --no-header leaves the header out
+func TestPrintSessionsTable(t *testing.T) {
+	// No session files, so that the message counts are left empty
+	sessions.SetProjectsDir(t.TempDir())
+	sessions.ClearQueryCache()
+	t.Cleanup(func() {
+		sessions.SetProjectsDir("")
+		sessions.ClearQueryCache()
+	})
+
+	project := &models.Project{Name: "app", Path: "/work/app"}
+	lastActivity := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		format  string
+		summary string
+		header  bool
+		want    string
+	}{
+		{"csv", "plain", true, "project,session_id,last_activity,message_count,summary\n" +
+			"/work/app,s1,2025-01-02T03:04:05Z,,plain\n"},
+		{"csv", "plain", false, "/work/app,s1,2025-01-02T03:04:05Z,,plain\n"},
+		{"csv", "fix a, b", false, "/work/app,s1,2025-01-02T03:04:05Z,,\"fix a, b\"\n"},
+		{"csv", `say "hi"`, false, "/work/app,s1,2025-01-02T03:04:05Z,,\"say \"\"hi\"\"\"\n"},
+		{"csv", "two\nlines", false, "/work/app,s1,2025-01-02T03:04:05Z,,\"two\nlines\"\n"},
+		{"csv", "tab\there", false, "/work/app,s1,2025-01-02T03:04:05Z,,tab\there\n"},
+		{"tsv", "plain", true, "project\tsession_id\tlast_activity\tmessage_count\tsummary\n" +
+			"/work/app\ts1\t2025-01-02T03:04:05Z\t\tplain\n"},
+		{"tsv", "tab\there", false, "/work/app\ts1\t2025-01-02T03:04:05Z\t\t\"tab\there\"\n"},
+		{"tsv", "fix a, b", false, "/work/app\ts1\t2025-01-02T03:04:05Z\t\tfix a, b\n"},
+		{"tsv", "two\nlines", false, "/work/app\ts1\t2025-01-02T03:04:05Z\t\t\"two\nlines\"\n"},
+	}
+	for _, tt := range tests {
+		list := []models.Session{{SessionID: "s1", LastActivity: lastActivity, Summary: tt.summary}}
+		var out bytes.Buffer
+		if err := printSessionsTable(context.Background(), &out, tt.format, project, list, tt.header); err != nil {
+			t.Fatalf("%s %q: printSessionsTable failed: %v", tt.format, tt.summary, err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s %q, header %v: got %q, want %q", tt.format, tt.summary, tt.header, out.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// templateFieldsHelp documents the fields available to show --template
+const templateFieldsHelp = `Fields available to --template:
+  Projects: {{.Name}} {{.Path}} {{.SessionCount}} {{.MessageCount}} {{.LastActivity}}
+            {{.LatestSessionID}} {{.LatestSummary}} (summary only with --verbose)
+  Sessions: {{.SessionID}} {{.ProjectName}} {{.ProjectPath}} {{.LastActivity}}
+            {{.Summary}} {{.GitBranch}} {{.IsResumed}} {{.MultipleDirs}}
+LastActivity is formatted with --date-format and --time-format.`
+
+// templateProject is the data a --template is executed with for each project
+type templateProject struct {
+	Name            string
+	Path            string
+	SessionCount    int
+	MessageCount    int
+	LastActivity    string
+	LatestSessionID string
+	LatestSummary   string
+}
+
+// templateSession is the data a --template is executed with for each session
+type templateSession struct {
+	SessionID    string
+	ProjectName  string
+	ProjectPath  string
+	LastActivity string
+	Summary      string
+	GitBranch    string
+	IsResumed    bool
+	MultipleDirs bool
+}
+
+// parseTemplate parses the text of --template. Referencing a field that
+// doesn't exist is reported when the template is executed.
+func parseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("show").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// printTemplate executes tmpl with data and ends the output with a newline
+func printTemplate(tmpl *template.Template, data interface{}) error {
+	if err := tmpl.Execute(os.Stdout, data); err != nil {
+		return fmt.Errorf("failed to execute --template: %w", err)
+	}
+	fmt.Println()
+	return nil
+}
+
+func toTemplateProject(project models.Project) templateProject {
+	return templateProject{
+		Name:            project.Name,
+		Path:            project.Path,
+		SessionCount:    project.SessionCount,
+		MessageCount:    project.MessageCount,
+		LastActivity:    formatTime(project.LastActivity),
+		LatestSessionID: project.LatestSessionID,
+		LatestSummary:   project.LatestSummary,
+	}
+}
+
+func toTemplateSession(session models.Session) templateSession {
+	return templateSession{
+		SessionID:    session.SessionID,
+		ProjectName:  sessions.ProjectName(session.ProjectPath),
+		ProjectPath:  session.ProjectPath,
+		LastActivity: formatTime(session.LastActivity),
+		Summary:      session.Summary,
+		GitBranch:    session.GitBranch,
+		IsResumed:    session.IsResumed,
+		MultipleDirs: session.MultipleDirs,
+	}
+}
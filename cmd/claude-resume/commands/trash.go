@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+// NewTrashCommand creates the trash command
+func NewTrashCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List, restore or erase deleted sessions",
+		Long: `Sessions deleted from the TUI are moved to a trash under the data directory
+($XDG_DATA_HOME/claude-resume, or ~/.local/share/claude-resume) rather than
+erased. They stay there until restored into their session files, or until
+the trash is emptied.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the sessions in the trash, most recently deleted first",
+		Args:  cobra.NoArgs,
+		RunE:  runTrashList,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "restore <session-id>",
+		Short: "Move a session from the trash back into its session files",
+		Long: `Move a session from the trash back into its session files. A unique prefix
+of the session ID is enough, like a git short hash.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runTrashRestore,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "empty",
+		Short: "Erase the sessions in the trash for good",
+		Args:  cobra.NoArgs,
+		RunE:  runTrashEmpty,
+	})
+
+	return cmd
+}
+
+func runTrashList(cmd *cobra.Command, args []string) error {
+	trashed, err := sessions.ListTrash()
+	if err != nil {
+		return err
+	}
+	if len(trashed) == 0 {
+		fmt.Println("The trash is empty")
+		return nil
+	}
+
+	for _, session := range trashed {
+		project := session.ProjectPath
+		if project == "" {
+			project = "Unknown"
+		}
+		fmt.Printf("%s\n", session.SessionID)
+		fmt.Printf("   Path: %s\n", project)
+		fmt.Printf("   Deleted: %s (%d lines)\n", formatTime(session.Deleted), session.Lines())
+	}
+	return nil
+}
+
+func runTrashRestore(cmd *cobra.Command, args []string) error {
+	session, err := sessions.RestoreSession(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Restored %s\n", session.SessionID)
+	return nil
+}
+
+func runTrashEmpty(cmd *cobra.Command, args []string) error {
+	count, err := sessions.EmptyTrash()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Erased %d deleted sessions\n", count)
+	return nil
+}
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/strrl/claude-resume/internal/version"
+)
+
+// NewVersionCommand creates the version command
+func NewVersionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the version, commit, build date, Go and DuckDB driver versions",
+		Args:  cobra.NoArgs,
+		RunE:  runVersion,
+	}
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	fmt.Print(version.Get())
+	return nil
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/paths"
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// Entry is the cached message preview of a session
+type Entry struct {
+	ModTime      time.Time              `json:"modTime"`  // Session file modification time the preview reflects
+	Messages     []sessions.PreviewLine `json:"previews"` // Keyed apart from the undated previews of older versions
+	MessageCount int                    `json:"messageCount"`
+	Usage        *sessions.SessionUsage `json:"usage,omitempty"`
+	Detail       *models.SessionDetail  `json:"detail,omitempty"`
+	MessagesOnly bool                   `json:"messagesOnly,omitempty"` // Preview leaves out tool calls and results
+	MessageTypes string                 `json:"messageTypes,omitempty"` // Message types of the preview, see sessions.MessageTypesKey
+}
+
+// Store is an on-disk cache of message previews keyed by session ID
+type Store struct {
+	path    string
+	entries map[string]Entry
+	dirty   bool
+}
+
+// Path returns the location of the message cache file, see paths.CacheDir
+func Path() (string, error) {
+	cacheDir, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "messages.json"), nil
+}
+
+// Load reads the cache file at path. A missing file yields an empty store; a
+// malformed one yields an empty store along with the error.
+func Load(path string) (*Store, error) {
+	store := &Store{path: path, entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return store, fmt.Errorf("failed to read cache %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &store.entries); err != nil {
+		store.entries = make(map[string]Entry)
+		// Rewrite the broken file on the next save
+		store.dirty = true
+		return store, fmt.Errorf("malformed cache %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// Entries returns the cached entries by session ID
+func (s *Store) Entries() map[string]Entry {
+	return s.entries
+}
+
+// Put stores the preview of a session
+func (s *Store) Put(sessionID string, entry Entry) {
+	s.entries[sessionID] = entry
+	s.dirty = true
+}
+
+// Delete removes the preview of a session
+func (s *Store) Delete(sessionID string) {
+	if _, ok := s.entries[sessionID]; ok {
+		delete(s.entries, sessionID)
+		s.dirty = true
+	}
+}
+
+// Prune drops entries whose session file is gone or has changed since they were stored
+func (s *Store) Prune(modTimes map[string]time.Time) {
+	for sessionID, entry := range s.entries {
+		if modTime, ok := modTimes[sessionID]; !ok || !modTime.Equal(entry.ModTime) {
+			s.Delete(sessionID)
+		}
+	}
+}
+
+// Save atomically writes the cache file if anything changed since it was loaded
+func (s *Store) Save() error {
+	if !s.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	if err := paths.MkdirFor(s.path); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".messages-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", s.path, err)
+	}
+
+	s.dirty = false
+	return nil
+}
+
+// Clear removes the cache file at path. A missing file is not an error.
+func Clear(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove cache %s: %w", path, err)
+	}
+	return nil
+}
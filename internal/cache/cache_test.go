@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+// TestStoreRoundTrip tests that saved entries are loaded back
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claude-resume", "messages.json")
+
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of a missing file failed: %v", err)
+	}
+	if len(store.Entries()) != 0 {
+		t.Fatalf("expected an empty store, got %d entries", len(store.Entries()))
+	}
+
+	modTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	store.Put("abc", Entry{ModTime: modTime, Messages: []sessions.PreviewLine{{Text: "User: hi"}}, MessageCount: 1})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	entry, ok := loaded.Entries()["abc"]
+	if !ok {
+		t.Fatal("expected the saved entry to be loaded")
+	}
+	if !entry.ModTime.Equal(modTime) || entry.MessageCount != 1 || len(entry.Messages) != 1 || entry.Messages[0].Text != "User: hi" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+}
+
+// TestStorePrune tests that entries whose session file changed or disappeared are dropped
+func TestStorePrune(t *testing.T) {
+	store, _ := Load(filepath.Join(t.TempDir(), "messages.json"))
+
+	modTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	store.Put("unchanged", Entry{ModTime: modTime})
+	store.Put("changed", Entry{ModTime: modTime})
+	store.Put("removed", Entry{ModTime: modTime})
+
+	store.Prune(map[string]time.Time{
+		"unchanged": modTime.Local(), // Same instant in another location
+		"changed":   modTime.Add(time.Second),
+	})
+
+	if _, ok := store.Entries()["unchanged"]; !ok {
+		t.Error("expected the unchanged entry to be kept")
+	}
+	for _, sessionID := range []string{"changed", "removed"} {
+		if _, ok := store.Entries()[sessionID]; ok {
+			t.Errorf("expected %s to be pruned", sessionID)
+		}
+	}
+}
+
+// TestLoadMalformed tests that a corrupt cache file yields an empty store and an error
+func TestLoadMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Load(path)
+	if err == nil {
+		t.Error("expected an error for a malformed cache")
+	}
+	if store == nil || len(store.Entries()) != 0 {
+		t.Fatal("expected an empty store")
+	}
+
+	// Saving replaces the broken file
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Errorf("expected the rewritten cache to load, got %v", err)
+	}
+}
+
+// TestClear tests that clearing removes the file and tolerates a missing one
+func TestClear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.json")
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Clear(path); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the cache file to be removed, got %v", err)
+	}
+	if err := Clear(path); err != nil {
+		t.Errorf("expected clearing a missing cache to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds user defaults read from the config file.
+// Command line flags take precedence over these values.
+type Config struct {
+	SortOrder     string        `yaml:"sort_order"`     // recent, name or sessions
+	PageLimit     int           `yaml:"page_limit"`     // Maximum number of projects/sessions listed
+	PreviewCount  int           `yaml:"preview_count"`  // Messages shown from the start and end of a session
+	DateFormat    string        `yaml:"date_format"`    // Go time layout used to display timestamps
+	TimeFormat    string        `yaml:"time_format"`    // absolute, relative or both
+	Theme         string        `yaml:"theme"`          // TUI color theme
+	ClaudePath    string        `yaml:"claude_path"`    // Path to the claude binary, empty to auto-detect
+	ProjectDir    string        `yaml:"project_dir"`    // Claude Code projects directory, empty for the default
+	Terminal      string        `yaml:"terminal"`       // Terminal preset or command to resume in a new window, empty for in place
+	ProjectFilter string        `yaml:"project_filter"` // Glob or re:regex scoping the tool to matching project paths
+	FreshAge      time.Duration `yaml:"fresh_age"`      // Sessions active more recently are shown green
+	RecentAge     time.Duration `yaml:"recent_age"`     // Sessions active more recently are shown yellow, older ones dim
+	MaxFiles      int           `yaml:"max_files"`      // Newest session files read from a larger history, 0 for all
+	MaxScanMB     int           `yaml:"max_scan_mb"`    // Megabytes of newest session files read from a larger history, 0 for all
+	MessageTypes  []string      `yaml:"message_types"`  // Content counted and previewed as messages: user, assistant, tool_use, tool_result, summary
+	MessageLines  int           `yaml:"message_lines"`  // Lines of each message shown in the TUI conversation until expanded, 0 for all
+}
+
+// Default returns the built-in defaults
+func Default() Config {
+	return Config{
+		SortOrder:    "recent",
+		PageLimit:    100,
+		PreviewCount: 10,
+		DateFormat:   "Jan 02 15:04 MST",
+		TimeFormat:   "absolute",
+		Theme:        "default",
+		MaxFiles:     5000,
+		MaxScanMB:    2048,
+		FreshAge:     24 * time.Hour,
+		RecentAge:    7 * 24 * time.Hour,
+		MessageTypes: []string{"user", "assistant", "tool_use", "tool_result"},
+		MessageLines: 200,
+	}
+}
+
+// Path returns the location of the config file, see paths.ConfigDir
+func Path() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// Load reads the config file, falling back to the built-in defaults.
+// A missing file is silently ignored; an unreadable or malformed file
+// produces a warning on stderr rather than an error.
+func Load() Config {
+	cfg := Default()
+
+	path, err := Path()
+	if errors.Is(err, paths.ErrNoHomeDir) {
+		// There is no config file to read; commands needing the home directory report it
+		return cfg
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using default config\n", err)
+		return cfg
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read config %s: %v, using defaults\n", path, err)
+		}
+		return cfg
+	}
+
+	// Decode over the defaults so that keys missing from the file keep their default
+	loaded := cfg
+	if err := yaml.Unmarshal(data, &loaded); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: malformed config %s: %v, using defaults\n", path, err)
+		return cfg
+	}
+
+	if loaded.PageLimit <= 0 {
+		loaded.PageLimit = cfg.PageLimit
+	}
+	if loaded.PreviewCount <= 0 {
+		loaded.PreviewCount = cfg.PreviewCount
+	}
+	if loaded.MessageLines < 0 {
+		loaded.MessageLines = cfg.MessageLines
+	}
+	if loaded.DateFormat == "" {
+		loaded.DateFormat = cfg.DateFormat
+	}
+	if loaded.TimeFormat == "" {
+		loaded.TimeFormat = cfg.TimeFormat
+	}
+	if loaded.SortOrder == "" {
+		loaded.SortOrder = cfg.SortOrder
+	}
+	if loaded.Theme == "" {
+		loaded.Theme = cfg.Theme
+	}
+
+	return loaded
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if content == "" {
+		return
+	}
+	dir := filepath.Join(home, ".config", "claude-resume")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestLoadMissingConfig tests that a missing config file yields the defaults
+func TestLoadMissingConfig(t *testing.T) {
+	writeConfig(t, "")
+
+	if cfg := Load(); !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+}
+
+// TestLoadPartialConfig tests that keys missing from the file keep their defaults
+func TestLoadPartialConfig(t *testing.T) {
+	writeConfig(t, "sort_order: name\nclaude_path: /opt/claude\nfresh_age: 12h\nmessage_types: [user, summary]\nmessage_lines: 0\n")
+
+	cfg := Load()
+	if cfg.SortOrder != "name" {
+		t.Errorf("expected sort_order name, got %q", cfg.SortOrder)
+	}
+	if cfg.ClaudePath != "/opt/claude" {
+		t.Errorf("expected claude_path /opt/claude, got %q", cfg.ClaudePath)
+	}
+	if cfg.FreshAge != 12*time.Hour {
+		t.Errorf("expected fresh_age 12h, got %s", cfg.FreshAge)
+	}
+	if want := []string{"user", "summary"}; !reflect.DeepEqual(cfg.MessageTypes, want) {
+		t.Errorf("expected message_types %v, got %v", want, cfg.MessageTypes)
+	}
+	if cfg.MessageLines != 0 {
+		t.Errorf("expected message_lines 0 to show messages in full, got %d", cfg.MessageLines)
+	}
+	if cfg.PageLimit != Default().PageLimit {
+		t.Errorf("expected default page_limit, got %d", cfg.PageLimit)
+	}
+}
+
+// TestLoadMalformedConfig tests that a malformed file falls back to the defaults
+func TestLoadMalformedConfig(t *testing.T) {
+	writeConfig(t, "sort_order: [unterminated\n")
+
+	if cfg := Load(); !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+}
@@ -1,47 +1,152 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"os"
+	"strings"
 	"sync"
 
-	_ "github.com/marcboeker/go-duckdb"
+	"github.com/marcboeker/go-duckdb"
 )
 
+// maxConns is the number of DuckDB connections kept in the pool. All connections
+// share one in-memory database, so concurrent queries no longer queue behind a
+// single connection.
+const maxConns = 4
+
+// extensionDirEnv names the environment variable pointing DuckDB at a local
+// extension directory, so that machines without network access can use a
+// pre-staged copy of the extensions
+const extensionDirEnv = "DUCKDB_EXTENSION_DIR"
+
 var (
+	// dbMu guards the singleton so that Close can reset it while GetDB is in use
+	dbMu       sync.Mutex
 	dbInstance *sql.DB
 	dbOnce     sync.Once
 	dbErr      error
 )
 
-// GetDB returns a singleton DuckDB connection
+// GetDB returns a singleton pool of DuckDB connections. It is shared by every
+// caller, none of which may close it but through Close.
 func GetDB() (*sql.DB, error) {
+	dbMu.Lock()
+	defer dbMu.Unlock()
 	dbOnce.Do(func() {
 		dbInstance, dbErr = initializeDuckDB()
 	})
 	return dbInstance, dbErr
 }
 
-// initializeDuckDB initializes a DuckDB connection with JSON extension
+// QueryContext runs query on the singleton pool. Only the rows are the caller's
+// to close: the pool stays open for the next query.
+func QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+	return database.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs query, which returns at most one row, on the singleton pool
+func QueryRowContext(ctx context.Context, query string, args ...interface{}) (*sql.Row, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+	return database.QueryRowContext(ctx, query, args...), nil
+}
+
+// ExecContext runs statement on the singleton pool
+func ExecContext(ctx context.Context, statement string, args ...interface{}) (sql.Result, error) {
+	database, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+	return database.ExecContext(ctx, statement, args...)
+}
+
+// Close closes the singleton pool, if it was opened, and resets it so that the
+// next GetDB opens a new one. A failed initialization is retried the same way.
+func Close() error {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+
+	var err error
+	if dbInstance != nil {
+		err = dbInstance.Close()
+	}
+	dbInstance, dbErr = nil, nil
+	dbOnce = sync.Once{}
+	return err
+}
+
+// initializeDuckDB initializes a DuckDB connection pool with the JSON extension
 func initializeDuckDB() (*sql.DB, error) {
-	db, err := sql.Open("duckdb", "")
+	if err := ensureExtension("json"); err != nil {
+		return nil, err
+	}
+	return open("json")
+}
+
+// ensureExtension makes sure a DuckDB extension can be loaded. Installing
+// downloads the extension, so it is skipped when the extension is already
+// bundled or installed, and done once up front rather than from every
+// connection the pool opens.
+func ensureExtension(name string) error {
+	db, err := open()
 	if err != nil {
-		return nil, fmt.Errorf("failed to open DuckDB: %w", err)
+		return err
 	}
+	defer db.Close()
 
-	// Set connection pool settings for better performance
-	db.SetMaxOpenConns(1) // DuckDB works best with single connection
-	db.SetMaxIdleConns(1)
+	if _, err := db.Exec("LOAD " + name); err == nil {
+		return nil
+	}
 
-	// Install and load the JSON extension
-	if _, err := db.Exec("INSTALL json"); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to install JSON extension: %w", err)
+	if _, err := db.Exec("INSTALL " + name); err != nil {
+		return fmt.Errorf("failed to install the DuckDB %s extension: %w\n"+
+			"Run claude-resume once while online so the extension is downloaded, "+
+			"or set %s to a directory containing a pre-staged copy", name, err, extensionDirEnv)
 	}
+	return nil
+}
+
+// open creates a pool of connections to a shared in-memory database. Every
+// connection loads the given extensions when it is created, since loaded
+// extensions are per-connection state.
+func open(extensions ...string) (*sql.DB, error) {
+	extensionDir := os.Getenv(extensionDirEnv)
+
+	connector, err := duckdb.NewConnector("", func(execer driver.ExecerContext) error {
+		if extensionDir != "" {
+			setting := "SET extension_directory = '" + strings.ReplaceAll(extensionDir, "'", "''") + "'"
+			if _, err := execer.ExecContext(context.Background(), setting, nil); err != nil {
+				return fmt.Errorf("failed to use extension directory %s: %w", extensionDir, err)
+			}
+		}
+		for _, name := range extensions {
+			if _, err := execer.ExecContext(context.Background(), "LOAD "+name, nil); err != nil {
+				return fmt.Errorf("failed to load %s extension: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DuckDB: %w", err)
+	}
+
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxConns)
 
-	if _, err := db.Exec("LOAD json"); err != nil {
+	// Open the first connection eagerly so extension errors surface here
+	if err := db.Ping(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to load JSON extension: %w", err)
+		return nil, err
 	}
 
 	return db, nil
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentQueries(t *testing.T) {
+	database, err := open()
+	if err != nil {
+		t.Fatalf("open() error = %v", err)
+	}
+	defer database.Close()
+
+	const workers = 20
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var sum int64
+			row := database.QueryRowContext(ctx, "SELECT SUM(range) + ? FROM range(100000)", i)
+			if err := row.Scan(&sum); err != nil {
+				errs <- err
+				return
+			}
+			if want := int64(4999950000 + i); sum != want {
+				t.Errorf("worker %d: sum = %d, want %d", i, sum, want)
+			}
+		}(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("concurrent queries did not finish in time")
+	}
+
+	close(errs)
+	for err := range errs {
+		t.Errorf("query error = %v", err)
+	}
+
+	if stats := database.Stats(); stats.MaxOpenConnections != maxConns {
+		t.Errorf("MaxOpenConnections = %d, want %d", stats.MaxOpenConnections, maxConns)
+	}
+}
+
+func TestGetDBConcurrent(t *testing.T) {
+	database, err := GetDB()
+	if err != nil {
+		t.Skipf("DuckDB JSON extension unavailable: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var n int
+			if err := database.QueryRow("SELECT COUNT(*) FROM (SELECT json_extract('{\"a\":1}', '$.a'))").Scan(&n); err != nil {
+				t.Errorf("query error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestExtensionDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "o'brien's extensions")
+	t.Setenv(extensionDirEnv, dir)
+
+	database, err := open()
+	if err != nil {
+		t.Fatalf("open() error = %v", err)
+	}
+	defer database.Close()
+
+	var got string
+	if err := database.QueryRow("SELECT current_setting('extension_directory')").Scan(&got); err != nil {
+		t.Fatalf("failed to read extension_directory: %v", err)
+	}
+	if got != dir {
+		t.Errorf("extension_directory = %q, want %q", got, dir)
+	}
+}
+
+func TestEnsureExtensionOffline(t *testing.T) {
+	// An empty extension directory forces an install
+	t.Setenv(extensionDirEnv, t.TempDir())
+
+	err := ensureExtension("json")
+	if err == nil {
+		t.Skip("extension is bundled or could be downloaded")
+	}
+	if !strings.Contains(err.Error(), extensionDirEnv) || !strings.Contains(err.Error(), "online") {
+		t.Errorf("expected an actionable error, got %v", err)
+	}
+}
+
+// TestCloseResetsSingleton checks that Close forgets the shared pool, so the next GetDB opens a new one
+func TestCloseResetsSingleton(t *testing.T) {
+	first, err := GetDB()
+	if closeErr := Close(); closeErr != nil {
+		t.Fatalf("Close() error = %v", closeErr)
+	}
+	if dbInstance != nil || dbErr != nil {
+		t.Fatal("Close() should forget the pool and any initialization error")
+	}
+	if err := Close(); err != nil {
+		t.Errorf("closing twice error = %v", err)
+	}
+	if err != nil {
+		t.Skipf("DuckDB JSON extension unavailable: %v", err)
+	}
+
+	if err := first.Ping(); err == nil {
+		t.Error("the closed pool should no longer be usable")
+	}
+	second, err := GetDB()
+	if err != nil {
+		t.Fatalf("GetDB() after Close() error = %v", err)
+	}
+	t.Cleanup(func() { Close() })
+	if second == first {
+		t.Error("GetDB() after Close() should open a new pool")
+	}
+	if err := second.Ping(); err != nil {
+		t.Errorf("new pool error = %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+// Package paths locates the directories claude-resume keeps its state in,
+// following the XDG base directory specification
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appName names the subdirectory of the config and cache directories
+const appName = "claude-resume"
+
+// ErrNoHomeDir is returned when a directory defaults to one in the home
+// directory but there is none, e.g. in a container without $HOME
+var ErrNoHomeDir = errors.New("home directory not found ($HOME is not set)")
+
+// HomeDir returns the home directory of the user, or ErrNoHomeDir
+func HomeDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil || homeDir == "" {
+		return "", ErrNoHomeDir
+	}
+	return homeDir, nil
+}
+
+// ConfigDir returns the directory holding the config file and favorites:
+// $XDG_CONFIG_HOME/claude-resume, or ~/.config/claude-resume when it is unset
+func ConfigDir() (string, error) {
+	return appDir("XDG_CONFIG_HOME", ".config")
+}
+
+// CacheDir returns the directory holding the message cache and the last
+// selection: $XDG_CACHE_HOME/claude-resume, or ~/.cache/claude-resume when it
+// is unset
+func CacheDir() (string, error) {
+	return appDir("XDG_CACHE_HOME", ".cache")
+}
+
+// DataDir returns the directory holding the trash of deleted sessions:
+// $XDG_DATA_HOME/claude-resume, or ~/.local/share/claude-resume when it is
+// unset
+func DataDir() (string, error) {
+	return appDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// appDir returns the claude-resume directory under the base directory named by
+// env, or under fallback in the home directory. The specification says to
+// ignore relative paths in env, as well as an empty value. The directory is not
+// created here, as looking a file up must not leave directories behind; writers
+// call MkdirFor.
+func appDir(env, fallback string) (string, error) {
+	base := os.Getenv(env)
+	if base == "" || !filepath.IsAbs(base) {
+		homeDir, err := HomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(homeDir, fallback)
+	}
+
+	return filepath.Join(base, appName), nil
+}
+
+// MkdirFor creates the directory of the file at path, and its parents, before
+// the file is written
+func MkdirFor(path string) error {
+	dir := filepath.Dir(path)
+	// The specification asks for directories only the user can read
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package paths
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAppDirs tests that the XDG variables win over the home directory and
+// that looking the directories up doesn't create them
+func TestAppDirs(t *testing.T) {
+	home := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name string
+		env  string
+		dir  func() (string, error)
+		xdg  string
+		want string
+	}{
+		{"config from XDG", "XDG_CONFIG_HOME", ConfigDir, xdg, filepath.Join(xdg, "claude-resume")},
+		{"config fallback", "XDG_CONFIG_HOME", ConfigDir, "", filepath.Join(home, ".config", "claude-resume")},
+		{"config ignores relative XDG", "XDG_CONFIG_HOME", ConfigDir, "relative", filepath.Join(home, ".config", "claude-resume")},
+		{"cache from XDG", "XDG_CACHE_HOME", CacheDir, xdg, filepath.Join(xdg, "claude-resume")},
+		{"cache fallback", "XDG_CACHE_HOME", CacheDir, "", filepath.Join(home, ".cache", "claude-resume")},
+		{"data from XDG", "XDG_DATA_HOME", DataDir, xdg, filepath.Join(xdg, "claude-resume")},
+		{"data fallback", "XDG_DATA_HOME", DataDir, "", filepath.Join(home, ".local", "share", "claude-resume")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.env, tt.xdg)
+			dir, err := tt.dir()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if dir != tt.want {
+				t.Errorf("got %s, want %s", dir, tt.want)
+			}
+			if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("looking %s up created it", dir)
+			}
+		})
+	}
+}
+
+// TestAppDirsWithoutHome tests that a missing home directory is reported as
+// ErrNoHomeDir, unless the XDG variable makes it unnecessary
+func TestAppDirsWithoutHome(t *testing.T) {
+	t.Setenv("HOME", "")
+	if _, err := HomeDir(); !errors.Is(err, ErrNoHomeDir) {
+		t.Errorf("HomeDir() error = %v, want ErrNoHomeDir", err)
+	}
+
+	t.Setenv("XDG_CACHE_HOME", "")
+	if _, err := CacheDir(); !errors.Is(err, ErrNoHomeDir) {
+		t.Errorf("CacheDir() error = %v, want ErrNoHomeDir", err)
+	}
+
+	xdg := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", xdg)
+	if dir, err := CacheDir(); err != nil || dir != filepath.Join(xdg, "claude-resume") {
+		t.Errorf("CacheDir() = %s, %v, want the XDG directory", dir, err)
+	}
+}
+
+// TestMkdirFor tests that the directory of a file is created private to the user
+func TestMkdirFor(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state", "claude-resume")
+	if err := MkdirFor(filepath.Join(dir, "favorites.json")); err != nil {
+		t.Fatalf("MkdirFor failed: %v", err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("directory was not created: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("directory has permissions %o, want 700", perm)
+	}
+}
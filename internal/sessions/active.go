@@ -0,0 +1,81 @@
+package sessions
+
+import (
+	"os"
+	"time"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+const (
+	// activeWindow is how recent the last activity of a session must be for it
+	// to possibly be running
+	activeWindow = 30 * time.Second
+	// activeProbeInterval is how long the files of a possibly running session
+	// are watched for growth
+	activeProbeInterval = 500 * time.Millisecond
+)
+
+// MarkActive sets the Active flag of each session still being written, see
+// ActiveSessions
+func MarkActive(list []models.Session) error {
+	active, err := ActiveSessions(list)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		list[i].Active = active[list[i].SessionID]
+	}
+	return nil
+}
+
+// ActiveSessions returns the sessions of list still being written, most likely
+// by claude running in another terminal. A session is active when it was
+// active within the last 30 seconds and its files grow while watched for a
+// short interval, so listings without recent sessions cost nothing extra.
+func ActiveSessions(list []models.Session) (map[string]bool, error) {
+	candidates := make(map[string][]string)
+	for _, session := range list {
+		if time.Since(session.LastActivity) >= activeWindow {
+			continue
+		}
+		files, err := FetchSessionFiles(session.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		candidates[session.SessionID] = files
+	}
+	if len(candidates) == 0 {
+		return map[string]bool{}, nil
+	}
+	return growingSessions(candidates, func() { time.Sleep(activeProbeInterval) }), nil
+}
+
+// growingSessions returns the sessions among candidates, which map session IDs
+// to their files, whose files grew in size while wait ran
+func growingSessions(candidates map[string][]string, wait func()) map[string]bool {
+	before := make(map[string]int64, len(candidates))
+	for sessionID, files := range candidates {
+		before[sessionID] = totalSize(files)
+	}
+	wait()
+
+	growing := make(map[string]bool)
+	for sessionID, files := range candidates {
+		if totalSize(files) > before[sessionID] {
+			growing[sessionID] = true
+		}
+	}
+	return growing
+}
+
+// totalSize returns the combined size of files, skipping those that can't be read
+func totalSize(files []string) int64 {
+	var size int64
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
@@ -0,0 +1,40 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestGrowingSessions tests that only sessions whose files grow while watched
+// count as active
+func TestGrowingSessions(t *testing.T) {
+	dir := t.TempDir()
+	running := filepath.Join(dir, "running.jsonl")
+	idle := filepath.Join(dir, "idle.jsonl")
+	for _, file := range []string{running, idle} {
+		if err := os.WriteFile(file, []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	candidates := map[string][]string{
+		"running": {running},
+		"idle":    {idle},
+		"missing": {filepath.Join(dir, "missing.jsonl")},
+	}
+	got := growingSessions(candidates, func() {
+		f, err := os.OpenFile(running, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString("{}\n"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if want := map[string]bool{"running": true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
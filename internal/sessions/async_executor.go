@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"path/filepath"
 	"sync"
 	"time"
 
@@ -48,10 +47,15 @@ type SQLProgress struct {
 	Message   string
 }
 
-// AsyncExecutor manages async SQL execution
+// AsyncExecutor manages async SQL execution. Parsed results are delivered on
+// Results; a consumer that stops reading holds up the executor until Close,
+// which drops the results not yet read.
 type AsyncExecutor struct {
 	db        *sql.DB
 	requests  chan SQLRequest
+	results   chan SQLResult
+	done      chan struct{} // Closed by Close to stop processRequests
+	wg        sync.WaitGroup
 	mu        sync.RWMutex
 	contexts  map[string]context.CancelFunc
 	closed    bool
@@ -63,122 +67,161 @@ func NewAsyncExecutor(db *sql.DB) *AsyncExecutor {
 	return &AsyncExecutor{
 		db:       db,
 		requests: make(chan SQLRequest, 10),
+		results:  make(chan SQLResult, 10),
+		done:     make(chan struct{}),
 		contexts: make(map[string]context.CancelFunc),
 	}
 }
 
 // Start begins processing SQL requests
 func (e *AsyncExecutor) Start() {
+	e.wg.Add(1)
 	go e.processRequests()
 }
 
-// Close shuts down the executor
+// Results returns the channel on which query results are delivered. Data holds
+// an AsyncQueryResult with the rows parsed according to the request type. The
+// channel is closed once the executor has been closed.
+func (e *AsyncExecutor) Results() <-chan SQLResult {
+	return e.results
+}
+
+// Close shuts down the executor, cancelling the active requests, and waits
+// for the request being handled to return
 func (e *AsyncExecutor) Close() {
 	e.closeOnce.Do(func() {
 		e.mu.Lock()
 		e.closed = true
-		close(e.requests)
+		close(e.done)
 		// Cancel all active requests
 		for _, cancel := range e.contexts {
 			cancel()
 		}
 		e.mu.Unlock()
 	})
+	e.wg.Wait()
 }
 
-// processRequests handles incoming SQL requests
+// processRequests handles incoming SQL requests until the executor is closed
 func (e *AsyncExecutor) processRequests() {
-	for req := range e.requests {
-		e.handleRequest(req)
+	defer e.wg.Done()
+	defer close(e.results)
+	for {
+		select {
+		case req := <-e.requests:
+			select {
+			case e.results <- e.handleRequest(req):
+			case <-e.done:
+				return
+			}
+		case <-e.done:
+			return
+		}
 	}
 }
 
-// handleRequest processes a single SQL request
-func (e *AsyncExecutor) handleRequest(req SQLRequest) {
-	// Store cancel function
-	ctx, cancel := context.WithCancel(req.Context)
-	e.mu.Lock()
-	e.contexts[req.RequestID] = cancel
-	e.mu.Unlock()
+// handleRequest runs a single SQL request and parses its rows
+func (e *AsyncExecutor) handleRequest(req SQLRequest) SQLResult {
+	ctx := req.Context
+	result := SQLResult{RequestID: req.RequestID, Type: req.Type}
 
-	// Clean up when done
+	// Clean up the cancel function registered by Submit
 	defer func() {
 		e.mu.Lock()
-		delete(e.contexts, req.RequestID)
+		if cancel, ok := e.contexts[req.RequestID]; ok {
+			cancel()
+			delete(e.contexts, req.RequestID)
+		}
 		e.mu.Unlock()
 	}()
 
-	// Execute query with context
+	// The request may have been cancelled while it was queued
+	if err := ctx.Err(); err != nil {
+		result.Error = err
+		return result
+	}
+
 	rows, err := e.db.QueryContext(ctx, req.Query, req.Args...)
 	if err != nil {
-		// Check if cancelled
-		if ctx.Err() == context.Canceled {
-			return // Don't send error for cancelled queries
+		// Report cancellation rather than the driver's interrupt error
+		if ctx.Err() != nil {
+			err = ctx.Err()
 		}
-		// Handle other errors through the result channel
-		return
+		result.Error = err
+		return result
 	}
 	defer rows.Close()
 
-	// Process results based on query type
+	var data AsyncQueryResult
 	switch req.Type {
 	case StateLoadingProjects:
-		// Process project results
-		for rows.Next() {
-			// Check for cancellation
-			if ctx.Err() == context.Canceled {
-				return
-			}
-			// Process row (implementation depends on actual query)
-			// Results would be sent through a channel in a full implementation
-		}
+		data.Projects, data.TotalCount, err = scanProjects(ctx, rows)
 	case StateLoadingSessions:
-		// Process session results
-		for rows.Next() {
-			// Check for cancellation
-			if ctx.Err() == context.Canceled {
-				return
-			}
-			// Process row
-			// Results would be sent through a channel in a full implementation
-		}
+		data.Sessions, data.TotalCount, err = scanSessions(ctx, rows)
 	case StateLoadingMessages:
-		// Process message results
-		for rows.Next() {
-			// Check for cancellation
-			if ctx.Err() == context.Canceled {
-				return
-			}
-			// Process row
-			// Results would be sent through a channel in a full implementation
+		data.Messages, data.TotalCount, err = scanMessages(ctx, rows, previewCountFromArgs(req.Args))
+	default:
+		err = fmt.Errorf("unsupported request type %d", req.Type)
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
 		}
+		result.Error = err
+		return result
 	}
+
+	result.Data = data
+	return result
 }
 
-// Submit submits a new SQL request
+// previewCountFromArgs extracts the preview count bound after the session ID
+// in a messages query, falling back to the configured preview count
+func previewCountFromArgs(args []interface{}) int {
+	if len(args) > 1 {
+		if n, ok := args[1].(int); ok {
+			return n
+		}
+	}
+	return getPreviewCount()
+}
+
+// Submit submits a new SQL request and returns its ID, or "" if the executor
+// is closed or ctx ends before the request could be queued
 func (e *AsyncExecutor) Submit(ctx context.Context, query string, args []interface{}, queryType LoadingState) string {
-	e.mu.RLock()
+	e.mu.Lock()
 	if e.closed {
-		e.mu.RUnlock()
+		e.mu.Unlock()
 		return ""
 	}
-	e.mu.RUnlock()
-
+	// Register the cancel function up front so queued requests can be cancelled too
 	requestID := uuid.New().String()
+	reqCtx, cancel := context.WithCancel(ctx)
+	e.contexts[requestID] = cancel
+	e.mu.Unlock()
+
 	req := SQLRequest{
 		Query:     query,
 		Args:      args,
 		RequestID: requestID,
 		Type:      queryType,
-		Context:   ctx,
+		Context:   reqCtx,
 	}
 
 	select {
 	case e.requests <- req:
 		return requestID
 	case <-ctx.Done():
-		return ""
+	case <-e.done:
 	}
+	e.mu.Lock()
+	delete(e.contexts, requestID)
+	e.mu.Unlock()
+	cancel()
+	return ""
 }
 
 // Cancel cancels a specific request
@@ -208,10 +251,12 @@ func (e *AsyncExecutor) CancelAll() {
 
 // AsyncQueryResult wraps query results with metadata
 type AsyncQueryResult struct {
-	Projects []models.Project
-	Sessions []models.Session
-	Messages []string
-	Error    error
+	Projects   []models.Project
+	Sessions   []models.Session
+	Messages   []PreviewLine
+	Detail     *models.SessionDetail // Of the session previewed, nil when it has no events
+	TotalCount int                   // Rows before truncation: messages in the session, or projects/sessions before paging
+	Error      error
 }
 
 // ExecuteProjectsQueryAsync executes a projects query asynchronously
@@ -235,44 +280,13 @@ func ExecuteProjectsQueryAsync(ctx context.Context, db *sql.DB, query string, ar
 		}
 		defer rows.Close()
 
-		var projects []models.Project
-		for rows.Next() {
-			// Check for cancellation
-			select {
-			case <-ctx.Done():
-				return
-			default:
-			}
-
-			var project models.Project
-			var lastActivity sql.NullString
-
-			if err := rows.Scan(&project.Path, &project.SessionCount, &lastActivity); err != nil {
-				continue
-			}
-
-			// Process project (same logic as before)
-			if project.Path == "Unknown" || project.Path == "" {
-				project.Name = "Unknown"
-			} else {
-				project.Name = filepath.Base(project.Path)
-			}
-
-			if lastActivity.Valid {
-				if t, err := time.Parse(time.RFC3339, lastActivity.String); err == nil {
-					project.LastActivity = t.Local()
-				} else {
-					project.LastActivity = time.Now()
-				}
-			} else {
-				project.LastActivity = time.Now()
-			}
-
-			projects = append(projects, project)
+		projects, total, err := scanProjects(ctx, rows)
+		if err != nil {
+			return
 		}
 
 		select {
-		case resultChan <- AsyncQueryResult{Projects: projects}:
+		case resultChan <- AsyncQueryResult{Projects: projects, TotalCount: total}:
 		case <-ctx.Done():
 		}
 	}()
@@ -301,41 +315,13 @@ func ExecuteSessionsQueryAsync(ctx context.Context, db *sql.DB, query string, ar
 		}
 		defer rows.Close()
 
-		var sessions []models.Session
-		for rows.Next() {
-			// Check for cancellation
-			select {
-			case <-ctx.Done():
-				return
-			default:
-			}
-
-			var session models.Session
-			var lastActivity sql.NullString
-			var isResumed bool
-
-			if err := rows.Scan(&session.SessionID, &lastActivity, &isResumed); err != nil {
-				continue
-			}
-
-			session.IsResumed = isResumed
-
-			// Parse timestamp
-			if lastActivity.Valid {
-				if t, err := time.Parse(time.RFC3339, lastActivity.String); err == nil {
-					session.LastActivity = t.Local()
-				} else {
-					session.LastActivity = time.Now()
-				}
-			} else {
-				session.LastActivity = time.Now()
-			}
-
-			sessions = append(sessions, session)
+		sessions, total, err := scanSessions(ctx, rows)
+		if err != nil {
+			return
 		}
 
 		select {
-		case resultChan <- AsyncQueryResult{Sessions: sessions}:
+		case resultChan <- AsyncQueryResult{Sessions: sessions, TotalCount: total}:
 		case <-ctx.Done():
 		}
 	}()
@@ -343,8 +329,10 @@ func ExecuteSessionsQueryAsync(ctx context.Context, db *sql.DB, query string, ar
 	return resultChan
 }
 
-// ExecuteMessagesQueryAsync executes a messages query asynchronously
-func ExecuteMessagesQueryAsync(ctx context.Context, db *sql.DB, query string, sessionID string) <-chan AsyncQueryResult {
+// ExecuteMessagesQueryAsync executes a session preview query asynchronously, see
+// sessionPreviewQuery. The query must bind the session ID followed by the
+// preview count four times.
+func ExecuteMessagesQueryAsync(ctx context.Context, db *sql.DB, query string, sessionID string, previewCount int) <-chan AsyncQueryResult {
 	resultChan := make(chan AsyncQueryResult, 1)
 
 	go func() {
@@ -354,7 +342,7 @@ func ExecuteMessagesQueryAsync(ctx context.Context, db *sql.DB, query string, se
 		queryCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 		defer cancel()
 
-		rows, err := db.QueryContext(queryCtx, query, sessionID)
+		rows, err := db.QueryContext(queryCtx, query, sessionID, previewCount, previewCount, previewCount, previewCount)
 		if err != nil {
 			select {
 			case resultChan <- AsyncQueryResult{Error: fmt.Errorf("failed to execute messages query: %w", err)}:
@@ -364,69 +352,181 @@ func ExecuteMessagesQueryAsync(ctx context.Context, db *sql.DB, query string, se
 		}
 		defer rows.Close()
 
-		var messages []string
-		var firstMessages []string
-		var lastMessages []string
-		var totalCount int64
-		lastPosition := ""
+		messages, total, detail, err := scanSessionPreview(ctx, rows, sessionID, previewCount)
+		if err != nil {
+			return
+		}
 
-		for rows.Next() {
-			// Check for cancellation
-			select {
-			case <-ctx.Done():
-				return
-			default:
-			}
+		select {
+		case resultChan <- AsyncQueryResult{Messages: messages, TotalCount: total, Detail: detail}:
+		case <-ctx.Done():
+		}
+	}()
 
-			var messageType sql.NullString
-			var messageJSON sql.NullString
-			var position sql.NullString
-			var count sql.NullInt64
+	return resultChan
+}
 
-			if err := rows.Scan(&messageType, &messageJSON, &position, &count); err != nil {
-				continue
-			}
+// scanProjects parses the rows of a projects query. It stops with ctx's error
+// when ctx is cancelled.
+func scanProjects(ctx context.Context, rows *sql.Rows) ([]models.Project, int, error) {
+	var projects []models.Project
+	total := 0
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
 
-			if count.Valid {
-				totalCount = count.Int64
-			}
+		var project models.Project
+		var lastActivity sql.NullString
+		var latestSessionID sql.NullString
 
-			if messageJSON.Valid && messageJSON.String != "" && messageType.Valid && position.Valid {
-				formattedMsg := formatMessageWithRole(messageType.String, messageJSON.String)
-				if formattedMsg != "" {
-					if position.String == "first" {
-						firstMessages = append(firstMessages, formattedMsg)
-						lastPosition = "first"
-					} else if position.String == "last" {
-						if lastPosition == "first" && len(lastMessages) == 0 {
-							if totalCount > 20 {
-								messages = append(messages, firstMessages...)
-								messages = append(messages, fmt.Sprintf("... (%d messages omitted) ...", totalCount-20))
-								lastMessages = append(lastMessages, formattedMsg)
-							} else {
-								firstMessages = append(firstMessages, formattedMsg)
-							}
-						} else {
-							lastMessages = append(lastMessages, formattedMsg)
-						}
-						lastPosition = "last"
-					}
-				}
-			}
+		if err := rows.Scan(&project.Path, &project.SessionCount, &project.MessageCount, &lastActivity, &latestSessionID, &total); err != nil {
+			continue
 		}
+		project.LatestSessionID = latestSessionID.String
+
+		project.Name = ProjectName(project.Path)
+		project.LastActivity = parseNullTimestamp(lastActivity)
 
-		// Combine messages
-		if len(lastMessages) > 0 {
-			messages = append(messages, lastMessages...)
-		} else {
-			messages = firstMessages
+		projects = append(projects, project)
+	}
+	return projects, total, nil
+}
+
+// scanSessions parses the rows of a sessions query. It stops with ctx's error
+// when ctx is cancelled.
+func scanSessions(ctx context.Context, rows *sql.Rows) ([]models.Session, int, error) {
+	var sessions []models.Session
+	total := 0
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
 		}
 
-		select {
-		case resultChan <- AsyncQueryResult{Messages: messages}:
-		case <-ctx.Done():
+		var session models.Session
+		var lastActivity, firstActivity, gitBranch sql.NullString
+
+		if err := rows.Scan(&session.SessionID, &lastActivity, &firstActivity, &session.IsResumed, &gitBranch, &session.MultipleDirs, &total); err != nil {
+			continue
 		}
-	}()
+		session.LastActivity = parseNullTimestamp(lastActivity)
+		session.Duration = sessionDuration(firstActivity, session.LastActivity)
+		session.GitBranch = gitBranch.String
 
-	return resultChan
-}
\ No newline at end of file
+		sessions = append(sessions, session)
+	}
+	return sessions, total, nil
+}
+
+// scanMessages parses the rows of a recent messages query into formatted previews,
+// inserting an omission marker between the first and last previewCount messages.
+// Each preview is prefixed with its time, with a divider before long gaps.
+// It stops with ctx's error when ctx is cancelled.
+func scanMessages(ctx context.Context, rows *sql.Rows, previewCount int) ([]PreviewLine, int, error) {
+	preview := newPreviewBuilder(previewCount)
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+
+		var messageType sql.NullString
+		var messageJSON sql.NullString
+		var timestamp sql.NullString
+		var position sql.NullString
+		var count sql.NullInt64
+
+		if err := rows.Scan(&messageType, &messageJSON, &timestamp, &position, &count); err != nil {
+			continue
+		}
+		preview.add(messageType, messageJSON, timestamp, position, count)
+	}
+
+	messages, totalCount := preview.result()
+	return messages, totalCount, nil
+}
+
+// scanSessionPreview parses the rows of a session preview query into the
+// preview of sessionID, like scanMessages, and its detail
+func scanSessionPreview(ctx context.Context, rows *sql.Rows, sessionID string, previewCount int) ([]PreviewLine, int, *models.SessionDetail, error) {
+	preview := newPreviewBuilder(previewCount)
+	var detail sessionDetailRow
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, nil, err
+		}
+
+		var messageType sql.NullString
+		var messageJSON sql.NullString
+		var timestamp sql.NullString
+		var position sql.NullString
+		var count sql.NullInt64
+
+		targets := append(detail.targets(), &messageType, &messageJSON, &timestamp, &position, &count)
+		if err := rows.Scan(targets...); err != nil {
+			continue
+		}
+		preview.add(messageType, messageJSON, timestamp, position, count)
+	}
+
+	messages, totalCount := preview.result()
+	return messages, totalCount, detail.result(sessionID), nil
+}
+
+// previewBuilder assembles the preview of a session from the rows of a recent
+// messages query, in timestamp order
+type previewBuilder struct {
+	previewCount  int
+	options       FormatOptions
+	timed         timedPreview
+	messages      []PreviewLine
+	firstMessages []PreviewLine
+	lastMessages  []PreviewLine
+	totalCount    int64
+	lastPosition  string
+}
+
+func newPreviewBuilder(previewCount int) *previewBuilder {
+	return &previewBuilder{previewCount: previewCount, options: previewFormatOptions()}
+}
+
+// add formats the message of a row and places it among the first or last messages
+func (p *previewBuilder) add(messageType, messageJSON, timestamp, position sql.NullString, count sql.NullInt64) {
+	if count.Valid {
+		p.totalCount = count.Int64
+	}
+
+	if messageJSON.Valid && messageJSON.String != "" && messageType.Valid && position.Valid {
+		formattedMsg := FormatMessage(messageType.String, messageJSON.String, p.options)
+		if formattedMsg != "" {
+			sent := parseNullTimestamp(timestamp)
+			if position.String == "first" {
+				p.firstMessages = append(p.firstMessages, p.timed.lines(sent, formattedMsg)...)
+				p.lastPosition = "first"
+			} else if position.String == "last" {
+				if p.lastPosition == "first" && len(p.lastMessages) == 0 {
+					if p.totalCount > int64(2*p.previewCount) {
+						p.messages = append(p.messages, p.firstMessages...)
+						p.messages = append(p.messages, PreviewLine{Text: fmt.Sprintf("... (%d messages omitted) ...", p.totalCount-int64(2*p.previewCount))})
+						// The omitted messages fill the gap to the first ones, so no divider spans the marker
+						p.timed.previous = time.Time{}
+						p.lastMessages = append(p.lastMessages, p.timed.lines(sent, formattedMsg)...)
+					} else {
+						p.firstMessages = append(p.firstMessages, p.timed.lines(sent, formattedMsg)...)
+					}
+				} else {
+					p.lastMessages = append(p.lastMessages, p.timed.lines(sent, formattedMsg)...)
+				}
+				p.lastPosition = "last"
+			}
+		}
+	}
+}
+
+// result returns the preview and the total number of messages in the session
+func (p *previewBuilder) result() ([]PreviewLine, int) {
+	// Combine messages
+	if len(p.lastMessages) > 0 {
+		return append(p.messages, p.lastMessages...), int(p.totalCount)
+	}
+	return p.firstMessages, int(p.totalCount)
+}
@@ -0,0 +1,193 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestExecutor(t *testing.T) *AsyncExecutor {
+	t.Helper()
+	database, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("failed to open DuckDB: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	executor := NewAsyncExecutor(database)
+	executor.Start()
+	t.Cleanup(executor.Close)
+	return executor
+}
+
+func waitForResult(t *testing.T, executor *AsyncExecutor, requestID string) SQLResult {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case result, ok := <-executor.Results():
+			if !ok {
+				t.Fatal("results channel closed before the result arrived")
+			}
+			if result.RequestID == requestID {
+				return result
+			}
+		case <-timeout:
+			t.Fatalf("no result for request %s", requestID)
+		}
+	}
+}
+
+func TestAsyncExecutorSubmitResult(t *testing.T) {
+	executor := newTestExecutor(t)
+
+	query := `
+		SELECT * FROM (VALUES
+			('/home/me/alpha', 3, 42, '2024-05-01T10:00:00Z', 's1', 2),
+			('Unknown', 1, 0, NULL, NULL, 2)
+		) AS t(path, session_count, message_count, last_activity, latest_session_id, total_count)`
+	requestID := executor.Submit(context.Background(), query, nil, StateLoadingProjects)
+	if requestID == "" {
+		t.Fatal("Submit returned an empty request ID")
+	}
+
+	result := waitForResult(t, executor, requestID)
+	if result.Error != nil {
+		t.Fatalf("result error = %v", result.Error)
+	}
+	if result.Type != StateLoadingProjects {
+		t.Errorf("result type = %v, want %v", result.Type, StateLoadingProjects)
+	}
+
+	data, ok := result.Data.(AsyncQueryResult)
+	if !ok {
+		t.Fatalf("result data has type %T, want AsyncQueryResult", result.Data)
+	}
+	if len(data.Projects) != 2 || data.TotalCount != 2 {
+		t.Fatalf("got %d projects with total %d, want 2 and 2", len(data.Projects), data.TotalCount)
+	}
+	if data.Projects[0].Name != "alpha" || data.Projects[0].SessionCount != 3 || data.Projects[0].MessageCount != 42 ||
+		data.Projects[0].LatestSessionID != "s1" {
+		t.Errorf("first project = %+v", data.Projects[0])
+	}
+	if data.Projects[1].Name != "Unknown" {
+		t.Errorf("second project name = %q, want Unknown", data.Projects[1].Name)
+	}
+}
+
+func TestAsyncExecutorSessionsResult(t *testing.T) {
+	executor := newTestExecutor(t)
+
+	query := `SELECT 'abc', '2024-05-01T10:00:00Z', '2024-05-01T08:30:00Z', true, 'main', true, 7`
+	requestID := executor.Submit(context.Background(), query, nil, StateLoadingSessions)
+
+	result := waitForResult(t, executor, requestID)
+	if result.Error != nil {
+		t.Fatalf("result error = %v", result.Error)
+	}
+	data := result.Data.(AsyncQueryResult)
+	if len(data.Sessions) != 1 || data.TotalCount != 7 {
+		t.Fatalf("got %d sessions with total %d, want 1 and 7", len(data.Sessions), data.TotalCount)
+	}
+	if session := data.Sessions[0]; session.SessionID != "abc" || !session.IsResumed || session.GitBranch != "main" || !session.MultipleDirs ||
+		session.Duration != 90*time.Minute {
+		t.Errorf("session = %+v", session)
+	}
+}
+
+func TestAsyncExecutorQueryError(t *testing.T) {
+	executor := newTestExecutor(t)
+
+	requestID := executor.Submit(context.Background(), "SELECT * FROM missing_table", nil, StateLoadingProjects)
+
+	result := waitForResult(t, executor, requestID)
+	if result.Error == nil {
+		t.Fatal("expected an error for an invalid query")
+	}
+	if result.Data != nil {
+		t.Errorf("result data = %v, want nil", result.Data)
+	}
+}
+
+func TestAsyncExecutorCancel(t *testing.T) {
+	executor := newTestExecutor(t)
+
+	// Occupy the executor so the next request is still queued when cancelled
+	slow := executor.Submit(context.Background(), "SELECT COUNT(*) FROM range(10000000000)", nil, StateLoadingProjects)
+	queued := executor.Submit(context.Background(), "SELECT 'p', 1, NULL, 1", nil, StateLoadingProjects)
+
+	executor.Cancel(queued)
+	executor.Cancel(slow)
+
+	for _, requestID := range []string{slow, queued} {
+		result := waitForResult(t, executor, requestID)
+		if !errors.Is(result.Error, context.Canceled) {
+			t.Errorf("request %s error = %v, want context.Canceled", requestID, result.Error)
+		}
+	}
+}
+
+func TestAsyncExecutorClose(t *testing.T) {
+	executor := newTestExecutor(t)
+	executor.Close()
+
+	if requestID := executor.Submit(context.Background(), "SELECT 1", nil, StateLoadingProjects); requestID != "" {
+		t.Errorf("Submit after Close returned %q, want empty", requestID)
+	}
+
+	select {
+	case _, ok := <-executor.Results():
+		if ok {
+			t.Error("unexpected result after Close")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("results channel was not closed")
+	}
+}
+
+// TestAsyncExecutorCloseUnread tests that Close returns even though nothing
+// reads the results, which fill their buffer
+func TestAsyncExecutorCloseUnread(t *testing.T) {
+	executor := newTestExecutor(t)
+	for i := 0; i < 30; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		executor.Submit(ctx, "SELECT 1", nil, StateLoadingProjects)
+		cancel()
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		executor.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close blocked on unread results")
+	}
+}
+
+// TestPreviewBuilderOmittedGap tests that no gap divider spans the marker of
+// omitted messages, the omitted messages filling the gap
+func TestPreviewBuilderOmittedGap(t *testing.T) {
+	valid := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	message := valid(`{"role":"user","content":"hi"}`)
+
+	preview := newPreviewBuilder(1)
+	preview.add(valid("user"), message, valid(start.Format(time.RFC3339)), valid("first"), sql.NullInt64{Int64: 5, Valid: true})
+	preview.add(valid("user"), message, valid(start.Add(5*time.Hour).Format(time.RFC3339)), valid("last"), sql.NullInt64{Int64: 5, Valid: true})
+
+	lines, total := preview.result()
+	if total != 5 || len(lines) != 3 {
+		t.Fatalf("expected the first message, the marker and the last message of 5, got %d of %d: %q", len(lines), total, lines)
+	}
+	if lines[1].Text != "... (3 messages omitted) ..." || !lines[1].Time.IsZero() {
+		t.Errorf("expected the undated marker second, got %q", lines[1])
+	}
+	if !lines[2].Time.Equal(start.Add(5 * time.Hour)) {
+		t.Errorf("expected the last message dated right after the marker, got %q", lines[2])
+	}
+}
@@ -3,159 +3,95 @@ package sessions
 import (
 	"context"
 	"database/sql"
-	"fmt"
-	"os"
-	"path/filepath"
+	"slices"
 
 	"github.com/strrl/claude-resume/internal/db"
 	"github.com/strrl/claude-resume/pkg/models"
 )
 
-// FetchProjectsWithStatsAsync fetches projects asynchronously
+// FetchProjectsWithStatsAsync fetches the first page of projects asynchronously
 func FetchProjectsWithStatsAsync(ctx context.Context) ([]models.Project, error) {
-	homeDir, err := os.UserHomeDir()
+	projects, _, err := FetchProjectsPageAsync(ctx, PageLimit(), 0)
+	return projects, err
+}
+
+// FetchProjectsPageAsync fetches a page of projects asynchronously, along with
+// the total number of projects. Results are cached until a session file changes.
+func FetchProjectsPageAsync(ctx context.Context, limit, offset int) ([]models.Project, int, error) {
+	plan, err := listingPlan()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, 0, err
 	}
 
-	claudeDir := filepath.Join(homeDir, ".claude", "projects")
-	globPattern := filepath.Join(claudeDir, "**", "*.jsonl")
+	key := cacheKey(projectsQuery(plan, limit, offset), ProjectSummaries())
+	page, err := cached(key, func() (projectsPage, error) {
+		projects, total, err := fetchProjectsPageAsync(ctx, plan, limit, offset)
+		return projectsPage{projects, total}, err
+	})
+	return slices.Clone(page.projects), page.total, err
+}
 
+func fetchProjectsPageAsync(ctx context.Context, plan *scanPlan, limit, offset int) ([]models.Project, int, error) {
 	database, err := db.GetDB()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	projectsQuery := fmt.Sprintf(`
-		SELECT 
-			COALESCE(cwd, 'Unknown') as project_path,
-			COUNT(DISTINCT CAST(sessionId AS VARCHAR)) as session_count,
-			MAX(timestamp) as last_activity
-		FROM read_json('%s',
-			format = 'newline_delimited',
-			union_by_name = true,
-			filename = true
-		)
-		WHERE sessionId IS NOT NULL
-		GROUP BY cwd
-		HAVING COUNT(DISTINCT CAST(sessionId AS VARCHAR)) > 0
-		ORDER BY MAX(timestamp) DESC
-		LIMIT 100
-	`, globPattern)
-
 	// Execute query asynchronously with context
-	resultChan := ExecuteProjectsQueryAsync(ctx, database, projectsQuery)
+	resultChan := ExecuteProjectsQueryAsync(ctx, database, projectsQuery(plan, limit, offset))
 
 	// Wait for result or cancellation
 	select {
 	case result := <-resultChan:
 		if result.Error != nil {
-			return nil, result.Error
+			return nil, 0, result.Error
+		}
+		if ProjectSummaries() {
+			summaries := batchFetchSummariesAsync(ctx, latestSessionIDs(result.Projects), plan, database)
+			attachLatestSummaries(result.Projects, summaries)
 		}
-		return result.Projects, nil
+		return result.Projects, result.TotalCount, nil
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return nil, 0, ctx.Err()
 	}
 }
 
-// FetchSessionsForProjectAsync fetches sessions asynchronously
+// FetchSessionsForProjectAsync fetches the first page of sessions asynchronously
 func FetchSessionsForProjectAsync(ctx context.Context, projectPath string) ([]models.Session, error) {
-	homeDir, err := os.UserHomeDir()
+	sessions, _, err := FetchSessionsPageAsync(ctx, projectPath, PageLimit(), 0)
+	return sessions, err
+}
+
+// FetchSessionsPageAsync fetches a page of a project's sessions asynchronously,
+// along with the total number of sessions in the project. Results are cached
+// until a session file changes.
+func FetchSessionsPageAsync(ctx context.Context, projectPath string, limit, offset int) ([]models.Session, int, error) {
+	plan, err := listingPlan()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, 0, err
 	}
 
-	claudeDir := filepath.Join(homeDir, ".claude", "projects")
-	globPattern := filepath.Join(claudeDir, "**", "*.jsonl")
+	query, args := sessionsQuery(plan, projectPath, favoriteIDs(), limit, offset)
+	page, err := cached(cacheKey(query, args...), func() (sessionsPage, error) {
+		sessions, total, err := fetchSessionsPageAsync(ctx, query, args, projectPath)
+		return sessionsPage{sessions, total}, err
+	})
+	return slices.Clone(page.sessions), page.total, err
+}
 
+func fetchSessionsPageAsync(ctx context.Context, query string, args []interface{}, projectPath string) ([]models.Session, int, error) {
 	database, err := db.GetDB()
 	if err != nil {
-		return nil, err
-	}
-
-	var sessionsQuery string
-	var args []interface{}
-
-	if projectPath == "Unknown" {
-		sessionsQuery = fmt.Sprintf(`
-			WITH first_events AS (
-				SELECT 
-					CAST(sessionId AS VARCHAR) as session_id,
-					parentUuid,
-					timestamp,
-					ROW_NUMBER() OVER (PARTITION BY sessionId ORDER BY timestamp ASC) as rn
-				FROM read_json('%s',
-					format = 'newline_delimited',
-					union_by_name = true,
-					filename = true
-				)
-				WHERE sessionId IS NOT NULL
-				AND (cwd IS NULL OR cwd = '')
-			)
-			SELECT 
-				fe.session_id,
-				MAX(e.timestamp) as last_activity,
-				CASE WHEN MIN(CASE WHEN fe.rn = 1 THEN fe.parentUuid END) IS NULL THEN false ELSE true END as is_resumed
-			FROM first_events fe
-			JOIN (
-				SELECT CAST(sessionId AS VARCHAR) as session_id, timestamp
-				FROM read_json('%s',
-					format = 'newline_delimited',
-					union_by_name = true,
-					filename = true
-				)
-				WHERE sessionId IS NOT NULL
-				AND (cwd IS NULL OR cwd = '')
-			) e ON e.session_id = fe.session_id
-			GROUP BY fe.session_id
-			ORDER BY MAX(e.timestamp) DESC
-			LIMIT 100
-		`, globPattern, globPattern)
-	} else {
-		sessionsQuery = fmt.Sprintf(`
-			WITH first_events AS (
-				SELECT 
-					CAST(sessionId AS VARCHAR) as session_id,
-					parentUuid,
-					timestamp,
-					ROW_NUMBER() OVER (PARTITION BY sessionId ORDER BY timestamp ASC) as rn
-				FROM read_json('%s',
-					format = 'newline_delimited',
-					union_by_name = true,
-					filename = true
-				)
-				WHERE sessionId IS NOT NULL
-				AND cwd = ?
-			)
-			SELECT 
-				fe.session_id,
-				MAX(e.timestamp) as last_activity,
-				CASE WHEN MIN(CASE WHEN fe.rn = 1 THEN fe.parentUuid END) IS NULL THEN false ELSE true END as is_resumed
-			FROM first_events fe
-			JOIN (
-				SELECT CAST(sessionId AS VARCHAR) as session_id, timestamp
-				FROM read_json('%s',
-					format = 'newline_delimited',
-					union_by_name = true,
-					filename = true
-				)
-				WHERE sessionId IS NOT NULL
-				AND cwd = ?
-			) e ON e.session_id = fe.session_id
-			GROUP BY fe.session_id
-			ORDER BY MAX(e.timestamp) DESC
-			LIMIT 100
-		`, globPattern, globPattern)
-		args = []interface{}{projectPath, projectPath}
+		return nil, 0, err
 	}
 
 	// Execute query asynchronously
-	resultChan := ExecuteSessionsQueryAsync(ctx, database, sessionsQuery, args...)
+	resultChan := ExecuteSessionsQueryAsync(ctx, database, query, args...)
 
 	select {
 	case result := <-resultChan:
 		if result.Error != nil {
-			return nil, result.Error
+			return nil, 0, result.Error
 		}
 		
 		// Set project path for all sessions
@@ -167,74 +103,58 @@ func FetchSessionsForProjectAsync(ctx context.Context, projectPath string) ([]mo
 		// Summaries will be loaded in a separate async call if needed
 		// This provides instant feedback to the user
 
-		return result.Sessions, nil
+		return result.Sessions, result.TotalCount, nil
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return nil, 0, ctx.Err()
 	}
 }
 
 // FetchRecentMessagesForSessionAsync fetches messages asynchronously
-func FetchRecentMessagesForSessionAsync(ctx context.Context, sessionID string) ([]string, error) {
-	homeDir, err := os.UserHomeDir()
+func FetchRecentMessagesForSessionAsync(ctx context.Context, sessionID string) ([]PreviewLine, error) {
+	preview, err := FetchSessionPreviewAsync(ctx, sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
+	return preview.Messages, nil
+}
 
-	claudeDir := filepath.Join(homeDir, ".claude", "projects")
-	globPattern := filepath.Join(claudeDir, "**", "*.jsonl")
+// SessionPreview is what the preview pane shows of a session
+type SessionPreview struct {
+	Messages     []PreviewLine
+	MessageCount int                   // Messages in the session, including those left out of Messages
+	Detail       *models.SessionDetail // nil when the session has no events
+}
 
-	database, err := db.GetDB()
+// FetchSessionPreviewAsync fetches the first and last messages of a session
+// asynchronously, along with the total number of messages and its detail, in
+// a single pass over the session files
+func FetchSessionPreviewAsync(ctx context.Context, sessionID string) (*SessionPreview, error) {
+	plan, err := lookupPlan()
 	if err != nil {
 		return nil, err
 	}
 
-	messagesQuery := fmt.Sprintf(`
-		WITH all_messages AS (
-			SELECT 
-				type,
-				to_json(message) as message_json,
-				timestamp,
-				ROW_NUMBER() OVER (ORDER BY timestamp ASC) as row_num_asc,
-				ROW_NUMBER() OVER (ORDER BY timestamp DESC) as row_num_desc,
-				COUNT(*) OVER () as total_count
-			FROM read_json('%s',
-				format = 'newline_delimited',
-				union_by_name = true,
-				filename = true
-			)
-			WHERE CAST(sessionId AS VARCHAR) = ?
-			AND type IN ('user', 'assistant')
-			AND message IS NOT NULL
-		)
-		SELECT 
-			type,
-			message_json,
-			CASE 
-				WHEN row_num_asc <= 10 THEN 'first'
-				WHEN row_num_desc <= 10 THEN 'last'
-			END as position,
-			total_count
-		FROM all_messages
-		WHERE row_num_asc <= 10 OR row_num_desc <= 10
-		ORDER BY timestamp ASC
-	`, globPattern)
+	database, err := db.GetDB()
+	if err != nil {
+		return nil, err
+	}
 
 	// Execute query asynchronously
-	resultChan := ExecuteMessagesQueryAsync(ctx, database, messagesQuery, sessionID)
+	resultChan := ExecuteMessagesQueryAsync(ctx, database, sessionPreviewQuery(plan), sessionID, getPreviewCount())
 
 	select {
 	case result := <-resultChan:
 		if result.Error != nil {
 			return nil, result.Error
 		}
-		return result.Messages, nil
+		return &SessionPreview{Messages: result.Messages, MessageCount: result.TotalCount, Detail: result.Detail}, nil
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
 }
 
 // batchFetchSummariesAsync fetches summaries asynchronously
-func batchFetchSummariesAsync(ctx context.Context, sessionIDs []string, globPattern string, database *sql.DB) map[string]string {
+func batchFetchSummariesAsync(ctx context.Context, sessionIDs []string, plan *scanPlan, database *sql.DB) map[string]string {
 	summaries := make(map[string]string)
 
 	if len(sessionIDs) == 0 {
@@ -253,7 +173,7 @@ func batchFetchSummariesAsync(ctx context.Context, sessionIDs []string, globPatt
 		defer close(resultChan)
 
 		// Reuse existing batchFetchSummaries logic but with context checks
-		for sessionID, summary := range batchFetchSummaries(sessionIDs, globPattern, database) {
+		for sessionID, summary := range batchFetchSummaries(ctx, sessionIDs, plan, database) {
 			select {
 			case <-ctx.Done():
 				return
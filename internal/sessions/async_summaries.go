@@ -2,53 +2,81 @@ package sessions
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/strrl/claude-resume/internal/db"
+	"github.com/strrl/claude-resume/pkg/models"
 )
 
+// SummaryChunk holds the summaries found by one phase of
+// StreamSessionSummaries
+type SummaryChunk struct {
+	Summaries map[string]string
+	Sources   map[string]models.SummarySource // Where each summary comes from
+	Err       error
+}
+
 // FetchSessionSummariesAsync fetches summaries for sessions asynchronously
 func FetchSessionSummariesAsync(ctx context.Context, projectPath string, sessionIDs []string) (map[string]string, error) {
-	if len(sessionIDs) == 0 {
-		return make(map[string]string), nil
+	summaries := make(map[string]string)
+	for chunk := range StreamSessionSummaries(ctx, sessionIDs) {
+		if chunk.Err != nil {
+			return summaries, chunk.Err
+		}
+		for id, summary := range chunk.Summaries {
+			summaries[id] = summary
+		}
 	}
+	return summaries, ctx.Err()
+}
 
-	homeDir, err := os.UserHomeDir()
+// StreamSessionSummaries titles sessionIDs like sessionTitles and sends the
+// titles of each phase as soon as it is done, so that a long list fills in
+// progressively: summaries first, then first prompts, then first replies.
+// Every phase is one query over all of sessionIDs, so the projects are scanned
+// once per phase rather than once per batch of sessions. The channel is closed
+// once every phase is done or ctx is done; it is buffered, so a reader may
+// stop reading at any time.
+func StreamSessionSummaries(ctx context.Context, sessionIDs []string) <-chan SummaryChunk {
+	plan, err := listingPlan()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return failedChunk(err)
 	}
 
-	claudeDir := filepath.Join(homeDir, ".claude", "projects")
-	globPattern := filepath.Join(claudeDir, "**", "*.jsonl")
-
 	database, err := db.GetDB()
 	if err != nil {
-		return nil, err
+		return failedChunk(err)
 	}
 
-	// Use the existing batchFetchSummaries but with context support
-	summariesChan := make(chan map[string]string, 1)
-	
+	return streamPhases(ctx, func(send func(SummaryChunk) bool) {
+		sessionTitlesByPhase(ctx, sessionIDs, plan, database, func(summaries map[string]string, sources map[string]models.SummarySource) bool {
+			return send(SummaryChunk{Summaries: summaries, Sources: sources})
+		})
+	})
+}
+
+// failedChunk returns a closed stream holding only err
+func failedChunk(err error) <-chan SummaryChunk {
+	out := make(chan SummaryChunk, 1)
+	out <- SummaryChunk{Err: err}
+	close(out)
+	return out
+}
+
+// streamPhases runs phases on a goroutine, sending every chunk it passes to
+// send on the returned channel. send returns false once ctx is done, so that
+// the remaining phases are skipped.
+func streamPhases(ctx context.Context, phases func(send func(SummaryChunk) bool)) <-chan SummaryChunk {
+	// Room for every phase, so the goroutine never blocks on a reader that went away
+	out := make(chan SummaryChunk, titlePhases)
 	go func() {
-		// Check context before expensive operation
-		select {
-		case <-ctx.Done():
-			summariesChan <- make(map[string]string)
-			return
-		default:
-		}
-		
-		summaries := batchFetchSummaries(sessionIDs, globPattern, database)
-		summariesChan <- summaries
+		defer close(out)
+		phases(func(chunk SummaryChunk) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+			out <- chunk
+			return true
+		})
 	}()
-
-	// Wait for result or context cancellation
-	select {
-	case summaries := <-summariesChan:
-		return summaries, nil
-	case <-ctx.Done():
-		return make(map[string]string), ctx.Err()
-	}
-}
\ No newline at end of file
+	return out
+}
@@ -0,0 +1,119 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// TestStreamPhases tests that the chunk of every phase is sent in order, and
+// that the stream is closed once the phases are done
+func TestStreamPhases(t *testing.T) {
+	phases := func(send func(SummaryChunk) bool) {
+		for i := 0; i < titlePhases; i++ {
+			send(SummaryChunk{Summaries: map[string]string{fmt.Sprint(i): "summary"}})
+		}
+	}
+
+	var got []string
+	for chunk := range streamPhases(context.Background(), phases) {
+		if chunk.Err != nil {
+			t.Fatalf("unexpected error: %v", chunk.Err)
+		}
+		for id := range chunk.Summaries {
+			got = append(got, id)
+		}
+	}
+
+	if strings.Join(got, ",") != "0,1,2" {
+		t.Errorf("got phases %v, want 0,1,2", got)
+	}
+}
+
+// TestStreamPhasesCancelled tests that the remaining phases are skipped once
+// the context is cancelled
+func TestStreamPhasesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var ran atomic.Int32
+	phases := func(send func(SummaryChunk) bool) {
+		for i := 0; i < titlePhases; i++ {
+			ran.Add(1)
+			cancel()
+			if !send(SummaryChunk{}) {
+				return
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for range streamPhases(ctx, phases) {
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not closed after cancellation")
+	}
+	if ran.Load() != 1 {
+		t.Errorf("ran %d phases after cancellation, want 1", ran.Load())
+	}
+}
+
+// BenchmarkSummaries compares loading the summaries of a 500-session project in
+// one batch against streaming them phase by phase
+func BenchmarkSummaries(b *testing.B) {
+	database, err := db.GetDB()
+	if err != nil {
+		b.Skipf("Skipping benchmark, database unavailable: %v", err)
+	}
+
+	dir := b.TempDir()
+	SetProjectsDir(dir)
+	b.Cleanup(func() { SetProjectsDir("") })
+
+	projectDir := filepath.Join(dir, "-tmp-bench")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		b.Fatal(err)
+	}
+	ids := make([]string, 500)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("bench-%03d", i)
+		var lines strings.Builder
+		for j := 0; j < 10; j++ {
+			fmt.Fprintf(&lines, `{"type":"user","sessionId":"%s","uuid":"%s-%d","cwd":"/tmp/bench","timestamp":"2025-01-01T00:%02d:00Z","message":{"role":"user","content":"prompt %d"}}`+"\n", ids[i], ids[i], j, j, j)
+		}
+		fmt.Fprintf(&lines, `{"type":"summary","summary":"Session %d","leafUuid":"%s-9"}`+"\n", i, ids[i])
+		if err := os.WriteFile(filepath.Join(projectDir, ids[i]+".jsonl"), []byte(lines.String()), 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	plan, err := listingPlan()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sessionTitles(context.Background(), ids, plan, database)
+		}
+	})
+
+	b.Run("Streamed", func(b *testing.B) {
+		ctx := context.Background()
+		for i := 0; i < b.N; i++ {
+			if _, err := FetchSessionSummariesAsync(ctx, "/tmp/bench", ids); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -4,10 +4,19 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/strrl/claude-resume/internal/db"
 )
 
+// closeDBAfter closes the shared DuckDB pool once the test is done, so that the
+// next test starts from a fresh one instead of connections this test left busy
+func closeDBAfter(tb testing.TB) {
+	tb.Cleanup(func() { db.Close() })
+}
+
 // TestAsyncProjectLoading tests async loading of projects
 func TestAsyncProjectLoading(t *testing.T) {
+	closeDBAfter(t)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -35,6 +44,7 @@ func TestAsyncProjectLoading(t *testing.T) {
 
 // TestAsyncSessionLoading tests async loading of sessions
 func TestAsyncSessionLoading(t *testing.T) {
+	closeDBAfter(t)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -60,6 +70,7 @@ func TestAsyncSessionLoading(t *testing.T) {
 
 // TestAsyncCancellation tests cancellation of async operations
 func TestAsyncCancellation(t *testing.T) {
+	closeDBAfter(t)
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Start loading in goroutine
@@ -84,6 +95,7 @@ func TestAsyncCancellation(t *testing.T) {
 
 // TestAsyncMessageLoading tests async loading of messages
 func TestAsyncMessageLoading(t *testing.T) {
+	closeDBAfter(t)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -108,7 +120,7 @@ func TestAsyncMessageLoading(t *testing.T) {
 
 	// If messages exist, verify they're formatted correctly
 	for _, msg := range messages {
-		if msg == "" {
+		if msg.Text == "" {
 			t.Error("Message should not be empty")
 		}
 	}
@@ -116,6 +128,7 @@ func TestAsyncMessageLoading(t *testing.T) {
 
 // TestConcurrentAsyncOperations tests multiple async operations
 func TestConcurrentAsyncOperations(t *testing.T) {
+	closeDBAfter(t)
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -155,6 +168,7 @@ func TestConcurrentAsyncOperations(t *testing.T) {
 
 // BenchmarkAsyncProjectLoading benchmarks async project loading
 func BenchmarkAsyncProjectLoading(b *testing.B) {
+	closeDBAfter(b)
 	ctx := context.Background()
 
 	b.ResetTimer()
@@ -168,6 +182,7 @@ func BenchmarkAsyncProjectLoading(b *testing.B) {
 
 // BenchmarkSyncVsAsyncLoading compares sync vs async loading
 func BenchmarkSyncVsAsyncLoading(b *testing.B) {
+	closeDBAfter(b)
 	b.Run("Sync", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
 			_, err := FetchProjectsWithStats()
@@ -0,0 +1,154 @@
+package sessions
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScanBudget bounds how much of the session history listing queries read.
+// A zero field means no limit.
+type ScanBudget struct {
+	MaxFiles int   // Session files read at most
+	MaxBytes int64 // Total size of the session files read at most
+}
+
+// exceeded reports whether files session files totaling size bytes are over the budget
+func (b ScanBudget) exceeded(files int, size int64) bool {
+	return (b.MaxFiles > 0 && files > b.MaxFiles) || (b.MaxBytes > 0 && size > b.MaxBytes)
+}
+
+// ScanLimit describes a history too large for the scan budget, of which only
+// the newest session files are read
+type ScanLimit struct {
+	TotalFiles int       // Session files on disk
+	TotalBytes int64     // Total size of the session files on disk
+	ReadFiles  int       // Newest session files read by queries
+	ReadBytes  int64     // Total size of the files read
+	Since      time.Time // Modification time of the oldest file read
+}
+
+// Notice explains to the user that older sessions are left out
+func (l ScanLimit) Notice() string {
+	return fmt.Sprintf("Large history: reading the %d newest of %d session files (%s of %s, modified since %s). "+
+		"Raise --max-files or --max-scan-mb to include older sessions.",
+		l.ReadFiles, l.TotalFiles, formatBytes(l.ReadBytes), formatBytes(l.TotalBytes), l.Since.Format("Jan 02 2006"))
+}
+
+var (
+	budgetMu sync.Mutex
+	// scanBudget is the budget applied by listingPlan
+	scanBudget ScanBudget
+	// lastLimit is the outcome of the most recent listing plan, nil when within budget
+	lastLimit *ScanLimit
+)
+
+// SetScanBudget sets the budget over which listing queries read only the newest session files
+func SetScanBudget(budget ScanBudget) {
+	budgetMu.Lock()
+	defer budgetMu.Unlock()
+	scanBudget = budget
+}
+
+// setScanLimit records the limit of the most recent listing, nil when within budget
+func setScanLimit(limit *ScanLimit) {
+	budgetMu.Lock()
+	defer budgetMu.Unlock()
+	lastLimit = limit
+}
+
+// ScanLimited returns the limit applied by the most recent listing, and whether
+// the session history exceeded the scan budget
+func ScanLimited() (ScanLimit, bool) {
+	budgetMu.Lock()
+	defer budgetMu.Unlock()
+	if lastLimit == nil {
+		return ScanLimit{}, false
+	}
+	return *lastLimit, true
+}
+
+// sessionFile is a session file found by listSessionFiles
+type sessionFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// listSessionFiles returns the session files under claudeDir, sorted by path.
+// Unreadable entries are skipped.
+func listSessionFiles(claudeDir string) ([]sessionFile, error) {
+	var files []sessionFile
+	err := filepath.WalkDir(claudeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".jsonl" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, sessionFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", claudeDir, err)
+	}
+	return files, nil
+}
+
+// newestWithinBudget returns the newest of files that fit in budget along with
+// a description of the limit, when files are over it. It returns nil files when
+// they all fit. The newest file is always kept, even when it alone is over the
+// budget.
+func newestWithinBudget(files []sessionFile, budget ScanBudget) ([]string, *ScanLimit) {
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+	if !budget.exceeded(len(files), total) {
+		return nil, nil
+	}
+
+	all := slices.Clone(files)
+	sort.Slice(all, func(i, j int) bool { return all[i].modTime.After(all[j].modTime) })
+	limit := &ScanLimit{TotalFiles: len(all), TotalBytes: total}
+	var newest []string
+	for _, file := range all {
+		if len(newest) > 0 && budget.exceeded(len(newest)+1, limit.ReadBytes+file.size) {
+			break
+		}
+		newest = append(newest, file.path)
+		limit.ReadBytes += file.size
+		limit.Since = file.modTime
+	}
+	limit.ReadFiles = len(newest)
+	return newest, limit
+}
+
+// listLiteral quotes paths as a SQL list of string literals
+func listLiteral(paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		quoted[i] = quoteLiteral(path)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// formatBytes renders a size in bytes with a binary unit, e.g. 1.5 GB
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
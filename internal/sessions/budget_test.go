@@ -0,0 +1,133 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeAgedFiles writes a session file of size bytes for each name, the first
+// name being the newest, one hour apart
+func writeAgedFiles(t *testing.T, dir string, size int, names ...string) {
+	t.Helper()
+	now := time.Now()
+	for i, name := range names {
+		path := filepath.Join(dir, "-tmp-project", name+".jsonl")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := now.Add(-time.Duration(i) * time.Hour)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestNewestWithinBudget tests that an oversized history is narrowed to its
+// newest files and that a history within budget is read whole
+func TestNewestWithinBudget(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedFiles(t, dir, 100, "a", "b", "c", "d")
+	all, err := listSessionFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		budget ScanBudget
+		want   []string
+	}{
+		{"no budget", ScanBudget{}, nil},
+		{"within budget", ScanBudget{MaxFiles: 4, MaxBytes: 400}, nil},
+		{"too many files", ScanBudget{MaxFiles: 2}, []string{"a", "b"}},
+		{"too large", ScanBudget{MaxBytes: 250}, []string{"a", "b"}},
+		{"both", ScanBudget{MaxFiles: 3, MaxBytes: 150}, []string{"a"}},
+		{"newest file alone too large", ScanBudget{MaxBytes: 10}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, limit := newestWithinBudget(all, tt.budget)
+			if tt.want == nil {
+				if files != nil || limit != nil {
+					t.Errorf("got %v, want the whole history", files)
+				}
+				return
+			}
+
+			var names []string
+			for _, file := range files {
+				names = append(names, strings.TrimSuffix(filepath.Base(file), ".jsonl"))
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", names, tt.want)
+			}
+			if limit.TotalFiles != 4 || limit.TotalBytes != 400 {
+				t.Errorf("got totals %d files, %d bytes, want 4 files, 400 bytes", limit.TotalFiles, limit.TotalBytes)
+			}
+			if limit.ReadFiles != len(tt.want) || limit.ReadBytes != int64(100*len(tt.want)) {
+				t.Errorf("got %d files, %d bytes read, want %d files", limit.ReadFiles, limit.ReadBytes, len(tt.want))
+			}
+		})
+	}
+}
+
+// TestScanBudgetNarrowsQueries tests that queries read the newest files instead
+// of the glob while the history is over budget, and the glob once it is not
+func TestScanBudgetNarrowsQueries(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedFiles(t, dir, 10, "new", "old'er")
+	SetProjectsDir(dir)
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		SetScanBudget(ScanBudget{})
+	})
+
+	SetScanBudget(ScanBudget{MaxFiles: 1})
+	plan, err := listingPlan()
+	if err != nil {
+		t.Fatalf("listingPlan failed: %v", err)
+	}
+	source := jsonSource(plan)
+	if strings.Contains(source, quoteLiteral(plan.glob)) {
+		t.Errorf("source still reads the whole glob: %s", source)
+	}
+	if !strings.Contains(source, "['"+filepath.Join(dir, "-tmp-project", "new.jsonl")+"']") {
+		t.Errorf("source does not read only the newest file: %s", source)
+	}
+	limit, ok := ScanLimited()
+	if !ok || limit.ReadFiles != 1 || limit.TotalFiles != 2 {
+		t.Errorf("got limit %+v (%v), want 1 of 2 files read", limit, ok)
+	}
+
+	SetScanBudget(ScanBudget{})
+	if plan, err = listingPlan(); err != nil {
+		t.Fatalf("listingPlan failed: %v", err)
+	}
+	if source := jsonSource(plan); !strings.Contains(source, quoteLiteral(plan.glob)) {
+		t.Errorf("source does not read the glob without a budget: %s", source)
+	}
+	if _, ok := ScanLimited(); ok {
+		t.Error("history reported as limited without a budget")
+	}
+}
+
+// TestFormatBytes tests the sizes shown in the scan limit notice
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512 B",
+		1536:            "1.5 KB",
+		5 << 20:         "5.0 MB",
+		3<<30 + 512<<20: "3.5 GB",
+	}
+	for size, want := range tests {
+		if got := formatBytes(size); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", size, got, want)
+		}
+	}
+}
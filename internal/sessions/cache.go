@@ -0,0 +1,80 @@
+package sessions
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// Listing results are cached until a session file changes. Session files are
+// append-only and usually only the active one grows, so repeated navigation
+// can skip the full scan of every .jsonl file.
+var (
+	queryCacheMu sync.Mutex
+	queryCache   = make(map[string]cacheEntry)
+)
+
+type cacheEntry struct {
+	stamp filesStamp
+	value interface{}
+}
+
+// cacheKey builds a cache key from a query and its arguments
+func cacheKey(query string, args ...interface{}) string {
+	return fmt.Sprintf("%s\x00%v", query, args)
+}
+
+// cached returns the value stored under key if no session file has changed
+// since it was stored. Otherwise it calls load and caches its result. Errors
+// are never cached, and caching is skipped when the files can't be stamped.
+func cached[T any](key string, load func() (T, error)) (T, error) {
+	stamp, err := sessionFilesStamp()
+	if err != nil {
+		return load()
+	}
+
+	queryCacheMu.Lock()
+	entry, ok := queryCache[key]
+	queryCacheMu.Unlock()
+	if ok && entry.stamp.equal(stamp) {
+		return entry.value.(T), nil
+	}
+
+	// The stamp is taken before loading, so a file changing mid-query leaves
+	// a stale stamp behind and forces a reload on the next call
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	queryCacheMu.Lock()
+	defer queryCacheMu.Unlock()
+	// Entries stored under an older stamp can never be served again
+	for k, e := range queryCache {
+		if !e.stamp.equal(stamp) {
+			delete(queryCache, k)
+		}
+	}
+	queryCache[key] = cacheEntry{stamp: stamp, value: value}
+	return value, nil
+}
+
+// ClearQueryCache drops every cached listing result
+func ClearQueryCache() {
+	queryCacheMu.Lock()
+	defer queryCacheMu.Unlock()
+	queryCache = make(map[string]cacheEntry)
+}
+
+// projectsPage is a cached page of projects
+type projectsPage struct {
+	projects []models.Project
+	total    int
+}
+
+// sessionsPage is a cached page of sessions
+type sessionsPage struct {
+	sessions []models.Session
+	total    int
+}
@@ -0,0 +1,88 @@
+package sessions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCachedInvalidation tests that cached results survive until a session file changes
+func TestCachedInvalidation(t *testing.T) {
+	dir := t.TempDir()
+	SetProjectsDir(dir)
+	ClearQueryCache()
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		ClearQueryCache()
+	})
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	writeFile := func(name string, mtime time.Time) {
+		t.Helper()
+		path := filepath.Join(dir, "project", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("a.jsonl", base)
+
+	loads := 0
+	load := func() (int, error) {
+		loads++
+		return loads, nil
+	}
+	expect := func(step string, want int) {
+		t.Helper()
+		got, err := cached("key", load)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step, err)
+		}
+		if got != want || loads != want {
+			t.Errorf("%s: got value %d after %d loads, want %d", step, got, loads, want)
+		}
+	}
+
+	expect("first call", 1)
+	expect("unchanged files", 1)
+
+	writeFile("a.jsonl", base.Add(time.Minute))
+	expect("appended file", 2)
+
+	// A new file is noticed even when it is older than the newest one
+	writeFile("b.jsonl", base)
+	expect("new file", 3)
+	expect("unchanged again", 3)
+
+	if err := os.Remove(filepath.Join(dir, "project", "b.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	expect("removed file", 4)
+}
+
+// TestCachedErrors tests that failed loads are not cached
+func TestCachedErrors(t *testing.T) {
+	SetProjectsDir(t.TempDir())
+	ClearQueryCache()
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		ClearQueryCache()
+	})
+
+	loadErr := errors.New("boom")
+	if _, err := cached("key", func() (string, error) { return "", loadErr }); !errors.Is(err, loadErr) {
+		t.Fatalf("expected load error, got %v", err)
+	}
+
+	got, err := cached("key", func() (string, error) { return "ok", nil })
+	if err != nil || got != "ok" {
+		t.Errorf("expected a fresh load after an error, got %q (%v)", got, err)
+	}
+}
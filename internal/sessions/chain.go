@@ -0,0 +1,75 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// FetchResumedFrom maps each resumed session among sessionIDs to the session it
+// was resumed from. Sessions that weren't resumed are left out.
+func FetchResumedFrom(sessionIDs []string) (map[string]string, error) {
+	parents := make(map[string]string)
+	if len(sessionIDs) == 0 {
+		return parents, nil
+	}
+
+	plan, err := listingPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, len(sessionIDs))
+	for i, id := range sessionIDs {
+		args[i] = id
+	}
+
+	rows, err := db.QueryContext(context.Background(), resumedFromQuery(plan, len(sessionIDs)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute resumed sessions query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sessionID, parentID sql.NullString
+		if err := rows.Scan(&sessionID, &parentID); err != nil {
+			continue
+		}
+		if sessionID.Valid && parentID.Valid && parentID.String != "" {
+			parents[sessionID.String] = parentID.String
+		}
+	}
+
+	return parents, nil
+}
+
+// FetchSessionChain follows parentUuid links from a session back to the session
+// it was originally started as, see sessionChainQuery. The result starts with
+// sessionID itself and ends with the original session.
+func FetchSessionChain(sessionID string) ([]string, error) {
+	plan, err := lookupPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(context.Background(), sessionChainQuery(plan), sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute session chain query: %w", err)
+	}
+	defer rows.Close()
+
+	var chain []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan session chain: %w", err)
+		}
+		chain = append(chain, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session chain: %w", err)
+	}
+	return chain, nil
+}
@@ -0,0 +1,207 @@
+package sessions
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// TestFetchSessionChain tests that parentUuid links are followed across resumed sessions
+func TestFetchSessionChain(t *testing.T) {
+	if _, err := db.GetDB(); err != nil {
+		t.Skipf("Skipping test, database unavailable: %v", err)
+	}
+
+	writeSessionFixture(t, map[string]string{
+		// The original session
+		"-tmp-project/a.jsonl": jsonl(
+			`{"type":"user","sessionId":"a","uuid":"a1","parentUuid":null,"cwd":"/tmp/project","timestamp":"2025-01-01T00:00:00Z"}`,
+			`{"type":"assistant","sessionId":"a","uuid":"a2","parentUuid":"a1","cwd":"/tmp/project","timestamp":"2025-01-01T00:01:00Z"}`,
+		),
+		// Resumed from a
+		"-tmp-project/b.jsonl": jsonl(
+			`{"type":"user","sessionId":"b","uuid":"b1","parentUuid":"a2","cwd":"/tmp/project","timestamp":"2025-01-02T00:00:00Z"}`,
+			`{"type":"assistant","sessionId":"b","uuid":"b2","parentUuid":"b1","cwd":"/tmp/project","timestamp":"2025-01-02T00:01:00Z"}`,
+		),
+		// Resumed from b
+		"-tmp-project/c.jsonl": jsonl(
+			`{"type":"user","sessionId":"c","uuid":"c1","parentUuid":"b2","cwd":"/tmp/project","timestamp":"2025-01-03T00:00:00Z"}`,
+		),
+	})
+
+	parents, err := FetchResumedFrom([]string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("FetchResumedFrom failed: %v", err)
+	}
+	if want := map[string]string{"b": "a", "c": "b"}; !reflect.DeepEqual(parents, want) {
+		t.Errorf("expected parents %v, got %v", want, parents)
+	}
+
+	chain, err := FetchSessionChain("c")
+	if err != nil {
+		t.Fatalf("FetchSessionChain failed: %v", err)
+	}
+	if want := []string{"c", "b", "a"}; !reflect.DeepEqual(chain, want) {
+		t.Errorf("expected chain %v, got %v", want, chain)
+	}
+}
+
+// TestResumedFromQuery tests that the query binds one placeholder per session
+func TestResumedFromQuery(t *testing.T) {
+	query := resumedFromQuery(globPlan("/tmp/projects/**/*.jsonl"), 3)
+	if !strings.Contains(query, "IN (?,?,?)") {
+		t.Errorf("expected three placeholders, got:\n%s", query)
+	}
+}
+
+// TestSessionChainQuery tests that the chain is followed in one query, and that
+// it ends where it would loop in corrupted data
+func TestSessionChainQuery(t *testing.T) {
+	projects := t.TempDir()
+	indexDir := t.TempDir()
+	SetProjectsDir(projects)
+	SetIndexDir(indexDir)
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		SetIndexDir("")
+	})
+
+	database, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("failed to open DuckDB: %v", err)
+	}
+	defer database.Close()
+
+	// The index stands in for the session files, so no DuckDB extension is needed
+	writeAgedFiles(t, projects, 10, "s")
+	file := filepath.Join(projects, "-tmp-project", "s.jsonl")
+	manifest := indexManifest{ProjectsDir: projects, Built: time.Now(), Files: map[string]indexedFile{
+		file: indexedFileOf(t, file, nil),
+	}}
+	event := func(sessionID, uuid, parentUUID, timestamp string) string {
+		parent := "NULL"
+		if parentUUID != "" {
+			parent = quoteLiteral(parentUUID)
+		}
+		return fmt.Sprintf("(%s, '%s', '%s', %s, NULL, 'user', '/tmp/project', TIMESTAMP '2025-01-01 %s', NULL, NULL)",
+			quoteLiteral(file), sessionID, uuid, parent, timestamp)
+	}
+	writeTestIndex(t, database, indexDir, manifest,
+		event("a", "a1", "", "10:00:00"),
+		event("a", "a2", "a1", "10:05:00"),
+		// Resumed from a
+		event("b", "b1", "a2", "11:00:00"),
+		event("b", "b2", "b1", "11:10:00"),
+		// Resumed from b
+		event("c", "c1", "b2", "12:00:00"),
+		// Resumed from each other
+		event("x", "x1", "y1", "09:00:00"),
+		event("y", "y1", "x1", "09:00:01"),
+	)
+
+	plan, err := lookupPlan()
+	if err != nil {
+		t.Fatalf("lookupPlan failed: %v", err)
+	}
+	chain := func(sessionID string) []string {
+		t.Helper()
+		rows, err := database.Query(sessionChainQuery(plan), sessionID)
+		if err != nil {
+			t.Fatalf("session chain query failed: %v", err)
+		}
+		defer rows.Close()
+		var got []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				t.Fatalf("failed to scan session: %v", err)
+			}
+			got = append(got, id)
+		}
+		return got
+	}
+	if got, want := chain("c"), []string{"c", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chain of c = %v, want %v", got, want)
+	}
+	if got, want := chain("a"), []string{"a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chain of a = %v, want %v", got, want)
+	}
+	if got, want := chain("x"), []string{"x", "y"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chain of x = %v, want %v", got, want)
+	}
+}
+
+// TestSinceLastResume tests that only the sessions with activity after their
+// last resume, on either side of it, are selected
+func TestSinceLastResume(t *testing.T) {
+	projects := t.TempDir()
+	indexDir := t.TempDir()
+	SetProjectsDir(projects)
+	SetIndexDir(indexDir)
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		SetIndexDir("")
+	})
+
+	database, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("failed to open DuckDB: %v", err)
+	}
+	defer database.Close()
+
+	// The index stands in for the session files, so no DuckDB extension is needed
+	writeAgedFiles(t, projects, 10, "s")
+	file := filepath.Join(projects, "-tmp-project", "s.jsonl")
+	manifest := indexManifest{ProjectsDir: projects, Built: time.Now(), Files: map[string]indexedFile{
+		file: indexedFileOf(t, file, nil),
+	}}
+	event := func(sessionID, uuid, parentUUID, timestamp string) string {
+		parent := "NULL"
+		if parentUUID != "" {
+			parent = quoteLiteral(parentUUID)
+		}
+		return fmt.Sprintf("(%s, '%s', '%s', %s, NULL, 'user', '/tmp/project', TIMESTAMP '2025-01-01 %s', NULL, NULL)",
+			quoteLiteral(file), sessionID, uuid, parent, timestamp)
+	}
+	writeTestIndex(t, database, indexDir, manifest,
+		// Resumed twice, then continued after the last resume
+		event("a", "a1", "", "10:00:00"),
+		event("a", "a2", "a1", "10:05:00"),
+		event("a", "a3", "a2", "13:00:00"),
+		// Resumed from a and worked on
+		event("b", "b1", "a2", "11:00:00"),
+		event("b", "b2", "b1", "11:10:00"),
+		// Resumed from a and left
+		event("c", "c1", "a2", "12:00:00"),
+		// Never resumed
+		event("d", "d1", "", "09:00:00"),
+		event("d", "d2", "d1", "14:00:00"),
+	)
+
+	plan, err := listingPlan()
+	if err != nil {
+		t.Fatalf("listingPlan failed: %v", err)
+	}
+	rows, err := database.Query(sinceLastResumeQuery(plan) + " ORDER BY e.session_id")
+	if err != nil {
+		t.Fatalf("since last resume query failed: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var sessionID string
+		if err := rows.Scan(&sessionID); err != nil {
+			t.Fatalf("failed to scan session: %v", err)
+		}
+		got = append(got, sessionID)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sessions active since their last resume = %v, want %v", got, want)
+	}
+}
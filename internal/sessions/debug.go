@@ -0,0 +1,94 @@
+package sessions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Roles of the events DebugSessionMessages can select
+const (
+	DebugRoleUser      = "user"
+	DebugRoleAssistant = "assistant"
+	DebugRoleAll       = "all"
+)
+
+// DebugOptions selects the events DebugSessionMessages reports
+type DebugOptions struct {
+	Role         string // DebugRoleUser, DebugRoleAssistant or DebugRoleAll
+	IncludeTools bool   // Show tool calls and results in full rather than as placeholders
+}
+
+// debugTypeCondition returns the SQL condition on the event type selecting role
+func debugTypeCondition(role string) (string, error) {
+	switch role {
+	case DebugRoleUser, "":
+		return "type = 'user'", nil
+	case DebugRoleAssistant:
+		return "type = 'assistant'", nil
+	case DebugRoleAll:
+		return "type IN ('user', 'assistant')", nil
+	}
+	return "", fmt.Errorf("invalid role '%s': must be user, assistant or all", role)
+}
+
+// debugEventEntries describes each content item of event n, labelled with the
+// role of the event and the type of the item. Tool items are placeholders unless
+// includeTools is set.
+func debugEventEntries(n int, eventType, messageJSON, timestamp string, includeTools bool) []string {
+	msgObj, ok := decodeMessage(messageJSON)
+	if !ok {
+		return nil
+	}
+
+	role := eventType
+	if r, ok := msgObj["role"].(string); ok && r != "" {
+		role = r
+	}
+	if role != "" {
+		role = strings.ToUpper(role[:1]) + role[1:]
+	}
+	label := func(itemType string) string {
+		return fmt.Sprintf("%s event %d (%s) at %s", role, n, itemType, timestamp)
+	}
+
+	if content, ok := msgObj["content"].(string); ok {
+		return []string{label("string") + ":\n" + content}
+	}
+	content, ok := msgObj["content"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var entries []string
+	for _, item := range content {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch typeStr, _ := itemMap["type"].(string); typeStr {
+		case "text":
+			if text, ok := itemMap["text"].(string); ok {
+				entries = append(entries, label("text")+":\n"+text)
+			}
+		case "tool_use":
+			name, _ := itemMap["name"].(string)
+			if !includeTools {
+				entries = append(entries, fmt.Sprintf("%s: [Tool Use: %s]", label("tool_use"), name))
+				continue
+			}
+			entry := fmt.Sprintf("%s:\n%s", label("tool_use"), name)
+			if input, err := json.MarshalIndent(itemMap["input"], "", "  "); err == nil {
+				entry += " " + string(input)
+			}
+			entries = append(entries, entry)
+		case "tool_result":
+			if !includeTools {
+				entries = append(entries, label("tool_result")+": [Tool Result]")
+				continue
+			}
+			entries = append(entries, label("tool_result")+":\n"+strings.TrimSpace(toolResultText(itemMap["content"])))
+		}
+	}
+	return entries
+}
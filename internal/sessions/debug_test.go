@@ -0,0 +1,64 @@
+package sessions
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDebugEventEntries tests that debug entries are labelled with the role and
+// item type, and that tool items are only shown in full when asked for
+func TestDebugEventEntries(t *testing.T) {
+	assistant := `{"role":"assistant","content":[{"type":"text","text":"Running it"},{"type":"tool_use","name":"Bash","input":{"command":"ls"}}]}`
+	user := `{"role":"user","content":[{"type":"tool_result","content":"main.go\n"}]}`
+
+	tests := []struct {
+		name         string
+		eventType    string
+		message      string
+		includeTools bool
+		want         []string
+	}{
+		{"string content", "user", `{"role":"user","content":"hello"}`, false, []string{
+			"User event 1 (string) at ts:\nhello",
+		}},
+		{"tool use placeholder", "assistant", assistant, false, []string{
+			"Assistant event 1 (text) at ts:\nRunning it",
+			"Assistant event 1 (tool_use) at ts: [Tool Use: Bash]",
+		}},
+		{"tool use in full", "assistant", assistant, true, []string{
+			"Assistant event 1 (text) at ts:\nRunning it",
+			"Assistant event 1 (tool_use) at ts:\nBash {\n  \"command\": \"ls\"\n}",
+		}},
+		{"tool result placeholder", "user", user, false, []string{
+			"User event 1 (tool_result) at ts: [Tool Result]",
+		}},
+		{"tool result in full", "user", user, true, []string{
+			"User event 1 (tool_result) at ts:\nmain.go",
+		}},
+		{"invalid message", "user", `not json`, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := debugEventEntries(1, tt.eventType, tt.message, "ts", tt.includeTools)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDebugTypeCondition tests the event types selected by each role
+func TestDebugTypeCondition(t *testing.T) {
+	for role, want := range map[string]string{
+		DebugRoleUser:      "type = 'user'",
+		DebugRoleAssistant: "type = 'assistant'",
+		DebugRoleAll:       "type IN ('user', 'assistant')",
+	} {
+		if got, err := debugTypeCondition(role); err != nil || got != want {
+			t.Errorf("role %s: got %q (%v), want %q", role, got, err, want)
+		}
+	}
+	if _, err := debugTypeCondition("system"); err == nil {
+		t.Error("expected an error for an unknown role")
+	}
+}
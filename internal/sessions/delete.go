@@ -0,0 +1,213 @@
+package sessions
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// FetchSessionFiles returns the distinct .jsonl files containing events of a session
+func FetchSessionFiles(sessionID string) ([]string, error) {
+	plan, err := lookupPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	filesQuery := fmt.Sprintf(`
+		SELECT DISTINCT filename
+		FROM %s
+		WHERE CAST(sessionId AS VARCHAR) = ?
+		ORDER BY filename
+	`, jsonSource(plan))
+
+	rows, err := db.QueryContext(context.Background(), filesQuery, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute session files query: %w", err)
+	}
+	defer rows.Close()
+
+	var files []string
+	for rows.Next() {
+		var filename sql.NullString
+		if err := rows.Scan(&filename); err != nil {
+			continue
+		}
+		if filename.Valid && filename.String != "" {
+			files = append(files, filename.String)
+		}
+	}
+
+	return files, nil
+}
+
+// DeleteSession moves every event of a session from the .jsonl files it lives in
+// to the trash, see RestoreSession. Summary events pointing at the removed events
+// are moved as well. Each file is rewritten atomically, and files left without
+// any events are removed.
+func DeleteSession(sessionID string) error {
+	files, err := FetchSessionFiles(sessionID)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	return deleteFromFiles(sessionID, files)
+}
+
+// deleteFromFiles moves the lines of a session from files to the trash
+func deleteFromFiles(sessionID string, files []string) error {
+	// First pass: collect the uuids of the session's events so that
+	// summaries referencing them can be removed too
+	uuids := make(map[string]bool)
+	projectPath := ""
+	for _, file := range files {
+		err := forEachLine(file, func(line []byte) {
+			event, ok := parseEventKeys(line)
+			if !ok || event.SessionID != sessionID {
+				return
+			}
+			if event.UUID != "" {
+				uuids[event.UUID] = true
+			}
+			if projectPath == "" {
+				projectPath = event.Cwd
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	drop := func(line []byte) bool {
+		event, ok := parseEventKeys(line)
+		if !ok {
+			return false
+		}
+		return event.SessionID == sessionID || (event.Type == "summary" && uuids[event.LeafUUID])
+	}
+
+	// Second pass: copy the session's lines to the trash, so that nothing is
+	// removed before it can be restored
+	if err := trashLines(sessionID, projectPath, files, drop); err != nil {
+		return err
+	}
+
+	// Third pass: rewrite the files without the session's lines
+	for _, file := range files {
+		if err := rewriteWithout(file, drop); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eventKeys holds the identifying fields of a raw .jsonl event
+type eventKeys struct {
+	Type      string `json:"type"`
+	SessionID string `json:"sessionId"`
+	UUID      string `json:"uuid"`
+	LeafUUID  string `json:"leafUuid"`
+	Cwd       string `json:"cwd"`
+}
+
+func parseEventKeys(line []byte) (eventKeys, bool) {
+	var event eventKeys
+	if err := json.Unmarshal(line, &event); err != nil {
+		return event, false
+	}
+	return event, true
+}
+
+// forEachLine calls fn for every non-empty line in the file, without the trailing newline
+func forEachLine(path string, fn func(line []byte)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimRight(line, "\r\n"); len(trimmed) > 0 {
+			fn(trimmed)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+}
+
+// rewriteWithout atomically rewrites a file, dropping the lines for which drop returns true.
+// If no lines remain the file is removed.
+func rewriteWithout(path string, drop func(line []byte) bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	writer := bufio.NewWriter(tmp)
+	kept := 0
+	var writeErr error
+	err = forEachLine(path, func(line []byte) {
+		if writeErr != nil || drop(line) {
+			return
+		}
+		kept++
+		if _, err := writer.Write(line); err != nil {
+			writeErr = err
+			return
+		}
+		writeErr = writer.WriteByte('\n')
+	})
+	if err == nil {
+		err = writeErr
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rewrite %s: %w", path, err)
+	}
+
+	if kept == 0 {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return nil
+	}
+
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
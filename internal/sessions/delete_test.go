@@ -0,0 +1,65 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRewriteWithout tests that matching lines are dropped and others kept verbatim
+func TestRewriteWithout(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	content := `{"type":"user","sessionId":"keep","uuid":"1"}
+{"type":"user","sessionId":"drop","uuid":"2"}
+{"type":"summary","summary":"x","leafUuid":"2"}
+{"type":"assistant","sessionId":"keep","uuid":"3"}
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := rewriteWithout(path, func(line []byte) bool {
+		event, ok := parseEventKeys(line)
+		return ok && (event.SessionID == "drop" || event.LeafUUID == "2")
+	})
+	if err != nil {
+		t.Fatalf("rewrite failed: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"type":"user","sessionId":"keep","uuid":"1"}
+{"type":"assistant","sessionId":"keep","uuid":"3"}
+`
+	if string(got) != expected {
+		t.Errorf("unexpected file content:\n%s", got)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode should be preserved, got %v", info.Mode().Perm())
+	}
+}
+
+// TestRewriteWithoutRemovesEmptyFile tests that a file with no remaining lines is removed
+func TestRewriteWithoutRemovesEmptyFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	if err := os.WriteFile(path, []byte(`{"sessionId":"drop"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rewriteWithout(path, func(line []byte) bool { return true }); err != nil {
+		t.Fatalf("rewrite failed: %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("empty file should be removed")
+	}
+}
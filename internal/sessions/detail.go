@@ -0,0 +1,107 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/strrl/claude-resume/internal/db"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// FetchSessionDetail aggregates the facts about a session shown before resuming it
+// in a single pass over its events
+func FetchSessionDetail(sessionID string) (*models.SessionDetail, error) {
+	plan, err := lookupPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	source := fmt.Sprintf("(SELECT * FROM %s WHERE CAST(sessionId AS VARCHAR) = ?)", jsonSource(plan))
+	row, err := db.QueryRowContext(context.Background(), sessionDetailQuery(plan, source), sessionID)
+	if err != nil {
+		return nil, err
+	}
+	var detailRow sessionDetailRow
+	if err := row.Scan(detailRow.targets()...); err != nil {
+		return nil, fmt.Errorf("failed to execute session detail query: %w", err)
+	}
+	detail := detailRow.result(sessionID)
+	if detail == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	return detail, nil
+}
+
+// sessionDetailRow receives the columns of sessionDetailQuery
+type sessionDetailRow struct {
+	detail                             models.SessionDetail
+	createdAt, lastActivity, gitBranch sql.NullString
+	eventCount                         int
+}
+
+// targets returns the scan destinations of the columns
+func (r *sessionDetailRow) targets() []interface{} {
+	return []interface{}{
+		&r.detail.ProjectPath,
+		&r.createdAt,
+		&r.lastActivity,
+		&r.detail.MessageCount,
+		&r.detail.ToolCalls,
+		&r.detail.IsResumed,
+		&r.gitBranch,
+		&r.eventCount,
+	}
+}
+
+// result returns the detail of sessionID read into the row, nil when the
+// session has no events
+func (r *sessionDetailRow) result(sessionID string) *models.SessionDetail {
+	if r.eventCount == 0 {
+		return nil
+	}
+	detail := r.detail
+	detail.SessionID = sessionID
+	detail.CreatedAt = parseNullTimestamp(r.createdAt)
+	detail.LastActivity = parseNullTimestamp(r.lastActivity)
+	detail.GitBranch = r.gitBranch.String
+	return &detail
+}
+
+// FetchMessageCounts counts the messages of each of the sessions in a single
+// query, as counted by FetchSessionDetail. Sessions without events are missing
+// from the result.
+func FetchMessageCounts(ctx context.Context, sessionIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(sessionIDs) == 0 {
+		return counts, nil
+	}
+
+	plan, err := listingPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, len(sessionIDs))
+	for i, id := range sessionIDs {
+		args[i] = id
+	}
+	rows, err := db.QueryContext(ctx, messageCountsQuery(plan, len(sessionIDs)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute message counts query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sessionID string
+		var count int
+		if err := rows.Scan(&sessionID, &count); err != nil {
+			continue
+		}
+		counts[sessionID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read message counts: %w", err)
+	}
+	return counts, nil
+}
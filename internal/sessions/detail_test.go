@@ -0,0 +1,64 @@
+package sessions
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// TestFetchSessionDetail tests the facts aggregated over a session's events
+func TestFetchSessionDetail(t *testing.T) {
+	if _, err := db.GetDB(); err != nil {
+		t.Skipf("Skipping test, database unavailable: %v", err)
+	}
+
+	writeSessionFixture(t, map[string]string{
+		"-p/d1.jsonl": jsonl(
+			`{"type":"user","sessionId":"d1","uuid":"u1","parentUuid":"p0","cwd":"/p","gitBranch":"main","timestamp":"2025-01-01T10:00:00Z","message":{"role":"user","content":"hi"}}`,
+			`{"type":"assistant","sessionId":"d1","uuid":"u2","parentUuid":"u1","cwd":"/p","gitBranch":"main","timestamp":"2025-01-01T10:05:00Z","message":{"role":"assistant","content":[{"type":"text","text":"reading"},{"type":"tool_use","name":"Read","input":{}}]}}`,
+			`{"type":"user","sessionId":"d1","uuid":"u4","parentUuid":"u2","cwd":"/p","gitBranch":"main","timestamp":"2025-01-01T10:06:00Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"done"}]}}`,
+			`{"type":"assistant","sessionId":"d1","uuid":"u3","parentUuid":"u2","cwd":"/p","gitBranch":"feature","timestamp":"2025-01-01T10:10:00Z","message":{"id":"m2","role":"assistant","content":[{"type":"tool_use","name":"Bash","input":{}}]}}`,
+			// The same message, continued in another event
+			`{"type":"assistant","sessionId":"d1","uuid":"u5","parentUuid":"u3","cwd":"/p","gitBranch":"feature","timestamp":"2025-01-01T10:10:00Z","message":{"id":"m2","role":"assistant","content":[{"type":"text","text":"done"}]}}`,
+		),
+	})
+
+	detail, err := FetchSessionDetail("d1")
+	if err != nil {
+		t.Fatalf("FetchSessionDetail failed: %v", err)
+	}
+	if detail.ProjectPath != "/p" || detail.MessageCount != 3 || detail.ToolCalls != 2 {
+		t.Errorf("expected 3 messages and 2 tool calls in /p, got %+v", detail)
+	}
+	if !detail.IsResumed || detail.GitBranch != "feature" {
+		t.Errorf("expected a resumed session on feature, got %+v", detail)
+	}
+	if got := detail.LastActivity.Sub(detail.CreatedAt); got.Minutes() != 10 {
+		t.Errorf("expected 10 minutes between creation and last activity, got %v", got)
+	}
+
+	if _, err := FetchSessionDetail("missing"); err == nil {
+		t.Error("expected an error for an unknown session")
+	}
+
+	preview, err := FetchSessionPreviewAsync(context.Background(), "d1")
+	if err != nil {
+		t.Fatalf("FetchSessionPreviewAsync failed: %v", err)
+	}
+	if !reflect.DeepEqual(preview.Detail, detail) || len(preview.Messages) == 0 {
+		t.Errorf("expected the preview to come with the same detail, got %+v", preview)
+	}
+	if preview, err := FetchSessionPreviewAsync(context.Background(), "missing"); err != nil || preview.Detail != nil {
+		t.Errorf("expected no detail for an unknown session, got %+v, %v", preview, err)
+	}
+
+	counts, err := FetchMessageCounts(context.Background(), []string{"d1", "missing"})
+	if err != nil {
+		t.Fatalf("FetchMessageCounts failed: %v", err)
+	}
+	if len(counts) != 1 || counts["d1"] != detail.MessageCount {
+		t.Errorf("expected the detail's message count for d1 only, got %v", counts)
+	}
+}
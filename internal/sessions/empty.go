@@ -0,0 +1,81 @@
+package sessions
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// EmptyReason explains why no projects were found
+type EmptyReason int
+
+const (
+	// NoProjectsDir means the projects directory does not exist, so Claude Code
+	// is likely not installed or has never been run
+	NoProjectsDir EmptyReason = iota
+	// NoSessionFiles means the projects directory exists but holds no session files
+	NoSessionFiles
+	// NoReadableSessions means session files exist but none of them yielded a session
+	NoReadableSessions
+)
+
+// EmptyDiagnosis describes why no projects were found in Dir
+type EmptyDiagnosis struct {
+	Reason EmptyReason
+	Dir    string
+}
+
+// DiagnoseEmpty inspects the projects directory to explain an empty project list
+func DiagnoseEmpty() (EmptyDiagnosis, error) {
+	dir, err := ProjectsDir()
+	if err != nil {
+		return EmptyDiagnosis{}, err
+	}
+	diagnosis := EmptyDiagnosis{Dir: dir}
+
+	info, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && !info.IsDir()) {
+		diagnosis.Reason = NoProjectsDir
+		return diagnosis, nil
+	}
+	if err != nil {
+		return diagnosis, fmt.Errorf("failed to stat %s: %w", dir, err)
+	}
+
+	stamp, err := sessionFilesStamp()
+	if err != nil {
+		return diagnosis, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	if stamp.count == 0 {
+		diagnosis.Reason = NoSessionFiles
+	} else {
+		diagnosis.Reason = NoReadableSessions
+	}
+	return diagnosis, nil
+}
+
+// Guidance returns advice for a user who has no projects
+func (d EmptyDiagnosis) Guidance() string {
+	switch d.Reason {
+	case NoProjectsDir:
+		return fmt.Sprintf("Claude Code doesn't appear to be installed: %s does not exist.\n"+
+			"Install it and run `claude` in a project, or point --project-dir or CLAUDE_CONFIG_DIR at your sessions.", d.Dir)
+	case NoSessionFiles:
+		return fmt.Sprintf("No sessions yet — run `claude` in a project first.\n"+
+			"Sessions are read from %s.", d.Dir)
+	default:
+		return fmt.Sprintf("No sessions could be read from the files in %s.\n"+
+			"Run `claude-resume debug-session <session-id>` to inspect one.", d.Dir)
+	}
+}
+
+// EmptyGuidance returns advice for a user who has no projects, falling back to
+// a plain notice when the projects directory cannot be inspected
+func EmptyGuidance() string {
+	diagnosis, err := DiagnoseEmpty()
+	if err != nil {
+		return "No projects found"
+	}
+	return diagnosis.Guidance()
+}
@@ -0,0 +1,54 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDiagnoseEmpty tests that a missing projects directory is told apart from an empty one
+func TestDiagnoseEmpty(t *testing.T) {
+	t.Cleanup(func() { SetProjectsDir("") })
+	root := t.TempDir()
+
+	missing := filepath.Join(root, "missing")
+	SetProjectsDir(missing)
+	diagnosis, err := DiagnoseEmpty()
+	if err != nil {
+		t.Fatalf("diagnose failed: %v", err)
+	}
+	if diagnosis.Reason != NoProjectsDir || diagnosis.Dir != missing {
+		t.Errorf("expected a missing projects directory, got %+v", diagnosis)
+	}
+	if !strings.Contains(diagnosis.Guidance(), "doesn't appear to be installed") {
+		t.Errorf("unexpected guidance: %s", diagnosis.Guidance())
+	}
+
+	empty := filepath.Join(root, "empty")
+	if err := os.MkdirAll(filepath.Join(empty, "-tmp-project"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	SetProjectsDir(empty)
+	diagnosis, err = DiagnoseEmpty()
+	if err != nil {
+		t.Fatalf("diagnose failed: %v", err)
+	}
+	if diagnosis.Reason != NoSessionFiles {
+		t.Errorf("expected no session files, got %+v", diagnosis)
+	}
+	if !strings.Contains(diagnosis.Guidance(), "run `claude` in a project first") {
+		t.Errorf("unexpected guidance: %s", diagnosis.Guidance())
+	}
+
+	if err := os.WriteFile(filepath.Join(empty, "-tmp-project", "s1.jsonl"), []byte("not json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	diagnosis, err = DiagnoseEmpty()
+	if err != nil {
+		t.Fatalf("diagnose failed: %v", err)
+	}
+	if diagnosis.Reason != NoReadableSessions {
+		t.Errorf("expected unreadable sessions, got %+v", diagnosis)
+	}
+}
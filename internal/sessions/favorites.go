@@ -0,0 +1,163 @@
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/strrl/claude-resume/internal/paths"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+var (
+	// favoritesMu serializes read-modify-write cycles of the favorites file
+	favoritesMu       sync.Mutex
+	favoritesOverride string
+)
+
+// SetFavoritesPath overrides the location of the favorites file, empty for the default
+func SetFavoritesPath(path string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	favoritesOverride = path
+}
+
+// FavoritesPath returns the location of the favorites file, in paths.ConfigDir
+// unless overridden
+func FavoritesPath() (string, error) {
+	settingsMu.RLock()
+	override := favoritesOverride
+	settingsMu.RUnlock()
+
+	if override != "" {
+		return override, nil
+	}
+
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "favorites.json"), nil
+}
+
+// LoadFavorites returns the set of favorite session IDs. A missing file is an empty set.
+func LoadFavorites() (map[string]bool, error) {
+	favoritesMu.Lock()
+	defer favoritesMu.Unlock()
+	return loadFavorites()
+}
+
+func loadFavorites() (map[string]bool, error) {
+	path, err := FavoritesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	favorites := make(map[string]bool)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return favorites, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read favorites: %w", err)
+	}
+
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("failed to parse favorites %s: %w", path, err)
+	}
+	for _, id := range ids {
+		favorites[id] = true
+	}
+	return favorites, nil
+}
+
+// ToggleFavorite stars or unstars a session and persists the change.
+// It returns whether the session is a favorite afterwards.
+func ToggleFavorite(sessionID string) (bool, error) {
+	favoritesMu.Lock()
+	defer favoritesMu.Unlock()
+
+	favorites, err := loadFavorites()
+	if err != nil {
+		return false, err
+	}
+	favorite := !favorites[sessionID]
+	if favorite {
+		favorites[sessionID] = true
+	} else {
+		delete(favorites, sessionID)
+	}
+	return favorite, saveFavorites(favorites)
+}
+
+// saveFavorites atomically writes the favorites file as a sorted JSON array
+func saveFavorites(favorites map[string]bool) error {
+	path, err := FavoritesPath()
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(favorites))
+	for id := range favorites {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	data, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode favorites: %w", err)
+	}
+
+	if err := paths.MkdirFor(path); err != nil {
+		return fmt.Errorf("failed to create favorites directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".favorites-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	_, err = tmp.Write(append(data, '\n'))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write favorites: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// MarkFavorites sets the Favorite flag of each session from the favorites file
+func MarkFavorites(list []models.Session) error {
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		list[i].Favorite = favorites[list[i].SessionID]
+	}
+	return nil
+}
+
+// favoriteIDs returns the favorite session IDs in order, for sessionsQuery to
+// list them first. Favorites are best-effort, so an unreadable file lists none.
+func favoriteIDs() []string {
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(favorites))
+	for id := range favorites {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
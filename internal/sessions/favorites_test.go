@@ -0,0 +1,39 @@
+package sessions
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+func TestToggleFavorite(t *testing.T) {
+	SetFavoritesPath(filepath.Join(t.TempDir(), "config", "favorites.json"))
+	t.Cleanup(func() { SetFavoritesPath("") })
+
+	favorites, err := LoadFavorites()
+	if err != nil || len(favorites) != 0 {
+		t.Fatalf("a missing favorites file should be an empty set, got %v, %v", favorites, err)
+	}
+
+	if favorite, err := ToggleFavorite("a"); err != nil || !favorite {
+		t.Fatalf("expected a to be starred, got %v, %v", favorite, err)
+	}
+	if _, err := ToggleFavorite("b"); err != nil {
+		t.Fatal(err)
+	}
+	if favorite, err := ToggleFavorite("a"); err != nil || favorite {
+		t.Fatalf("expected a to be unstarred, got %v, %v", favorite, err)
+	}
+
+	list := []models.Session{{SessionID: "a"}, {SessionID: "b"}}
+	if err := MarkFavorites(list); err != nil {
+		t.Fatal(err)
+	}
+	if list[0].Favorite || !list[1].Favorite {
+		t.Errorf("expected only b to be marked as a favorite, got %+v", list)
+	}
+	if ids := favoriteIDs(); len(ids) != 1 || ids[0] != "b" {
+		t.Errorf("expected the favorite IDs [b], got %v", ids)
+	}
+}
@@ -0,0 +1,38 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSessionFixture writes files, contents by path relative to the projects
+// directory, to a temporary projects directory and reads sessions from it for
+// the rest of the test. It returns the directory.
+func writeSessionFixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	SetProjectsDir(dir)
+	ClearQueryCache()
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		ClearQueryCache()
+	})
+	return dir
+}
+
+// jsonl returns the contents of a session file holding lines
+func jsonl(lines ...string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,266 @@
+package sessions
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FormatOptions controls how FormatMessage renders a message
+type FormatOptions struct {
+	IncludeTools      bool // Include tool calls and tool results
+	SkipToolUse       bool // Leave out tool calls even with IncludeTools
+	SkipToolResults   bool // Leave out tool results even with IncludeTools
+	SkipUserText      bool // Leave out the text of user events, keeping their tool results
+	SkipAssistantText bool // Leave out the text of assistant events, keeping their tool calls
+	MaxLength         int  // Truncate text and tool results to this many characters, 0 for no limit
+}
+
+// previewMaxLength bounds the text kept for a preview message. It is enough to
+// fill a wide pane; the TUI and the show command cut it to the space they have.
+const previewMaxLength = 200
+
+// previewFormatOptions returns the options used for message previews
+func previewFormatOptions() FormatOptions {
+	return FormatOptions{
+		IncludeTools:      !MessagesOnly(),
+		SkipToolUse:       !messageTypeSelected("tool_use"),
+		SkipToolResults:   !messageTypeSelected("tool_result"),
+		SkipUserText:      !messageTypeSelected("user"),
+		SkipAssistantText: !messageTypeSelected("assistant"),
+		MaxLength:         previewMaxLength,
+	}
+}
+
+// GapDividerPrefix starts the preview line marking a long gap between two messages
+const GapDividerPrefix = "⏱ "
+
+// previewGap is the pause between two messages after which previews show a divider
+const previewGap = time.Hour
+
+// PreviewLine is a line of a session's message preview: a formatted message such
+// as "[User] ...", a divider before a long gap, or the marker of omitted messages
+type PreviewLine struct {
+	Text string    `json:"text"`
+	Time time.Time `json:"time"` // When the message was sent, zero for dividers, markers and untimed messages
+}
+
+// String returns the line as plain text, prefixed with the time of a message,
+// e.g. "[15:04] [User] ..."
+func (l PreviewLine) String() string {
+	return l.TimePrefix() + l.Text
+}
+
+// TimePrefix returns the time prefixed to a message, e.g. "[15:04] ", or "" for an undated line
+func (l PreviewLine) TimePrefix() string {
+	if l.Time.IsZero() {
+		return ""
+	}
+	return "[" + l.Time.Format("15:04") + "] "
+}
+
+// PreviewStrings returns the lines of a preview as plain text, see PreviewLine.String
+func PreviewStrings(lines []PreviewLine) []string {
+	strs := make([]string, len(lines))
+	for i, line := range lines {
+		strs[i] = line.String()
+	}
+	return strs
+}
+
+// timedPreview dates preview messages and inserts a divider such as "⏱ 3h later"
+// when a message follows a long gap
+type timedPreview struct {
+	previous time.Time
+}
+
+// lines returns the preview lines for a formatted message sent at timestamp
+func (t *timedPreview) lines(timestamp time.Time, formatted string) []PreviewLine {
+	if timestamp.IsZero() {
+		return []PreviewLine{{Text: formatted}}
+	}
+
+	var lines []PreviewLine
+	if divider := GapDivider(t.previous, timestamp); divider != "" {
+		lines = append(lines, PreviewLine{Text: divider})
+	}
+	t.previous = timestamp
+	return append(lines, PreviewLine{Text: formatted, Time: timestamp})
+}
+
+// GapDivider returns a divider such as "⏱ 3h later" when more than an hour
+// passed between two messages, or "" otherwise
+func GapDivider(previous, next time.Time) string {
+	if previous.IsZero() || next.IsZero() || next.Sub(previous) <= previewGap {
+		return ""
+	}
+	return GapDividerPrefix + formatGap(next.Sub(previous)) + " later"
+}
+
+// EarlierGapDivider is GapDivider for messages listed newest first: it returns a
+// divider such as "⏱ 3h earlier" when more than an hour passed between the
+// earlier message, listed after the divider, and the later one, or "" otherwise
+func EarlierGapDivider(earlier, later time.Time) string {
+	if earlier.IsZero() || later.IsZero() || later.Sub(earlier) <= previewGap {
+		return ""
+	}
+	return GapDividerPrefix + formatGap(later.Sub(earlier)) + " earlier"
+}
+
+// formatGap formats a pause between messages in whole hours, or whole days from two days on
+func formatGap(gap time.Duration) string {
+	if gap >= 48*time.Hour {
+		return fmt.Sprintf("%dd", int(gap.Hours())/24)
+	}
+	return fmt.Sprintf("%dh", int(gap.Hours()))
+}
+
+// FormatDuration formats how long a session lasted, e.g. "45m", "1h23m" or "2d3h".
+// Sessions shorter than a minute are "<1m".
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+}
+
+// Pluralize formats a count with a singular or plural noun, e.g. "1 project", "2 projects"
+func Pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// FormatMessage formats the JSON message payload of an event as a single line
+// prefixed with its role, e.g. "[Assistant] Listing files | 🔧 Bash: ls".
+// System reminders are left out. It returns "" when nothing is left to display.
+func FormatMessage(msgType, messageStr string, opts FormatOptions) string {
+	payload, ok := decodeMessage(messageStr)
+	if !ok {
+		return ""
+	}
+
+	rolePrefix := fmt.Sprintf("[%s] ", msgType)
+	switch msgType {
+	case "user":
+		rolePrefix = "[User] "
+	case "assistant":
+		rolePrefix = "[Assistant] "
+	case "summary":
+		rolePrefix = "[Summary] "
+	}
+
+	skipText := (msgType == "user" && opts.SkipUserText) || (msgType == "assistant" && opts.SkipAssistantText)
+
+	var parts []string
+	switch content := payload["content"].(type) {
+	case string:
+		if content != "" && !skipText && !strings.Contains(content, "system-reminder") {
+			parts = append(parts, truncateString(content, opts.MaxLength))
+		}
+
+	case []interface{}:
+		for _, item := range content {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			switch itemMap["type"] {
+			case "text":
+				if skipText {
+					continue
+				}
+				if text, ok := itemMap["text"].(string); ok && text != "" {
+					// Skip system reminders
+					if !strings.Contains(text, "system-reminder") {
+						parts = append(parts, truncateString(text, opts.MaxLength))
+					}
+				}
+
+			case "tool_use":
+				if !opts.IncludeTools || opts.SkipToolUse {
+					continue
+				}
+				toolName := "unknown"
+				if name, ok := itemMap["name"].(string); ok {
+					toolName = name
+				}
+				if input := toolInputSummary(itemMap["input"]); input != "" {
+					parts = append(parts, fmt.Sprintf("🔧 %s: %s", toolName, input))
+				} else {
+					parts = append(parts, fmt.Sprintf("🔧 %s", toolName))
+				}
+
+			case "tool_result":
+				if !opts.IncludeTools || opts.SkipToolResults {
+					continue
+				}
+				parts = append(parts, fmt.Sprintf("↩ %s", truncateString(toolResultText(itemMap["content"]), opts.MaxLength)))
+			}
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return rolePrefix + strings.Join(parts, " | ")
+}
+
+// toolInputSummary returns a short description of a tool input: its command,
+// file name or search pattern, falling back to the truncated JSON
+func toolInputSummary(input interface{}) string {
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if cmd, ok := inputMap["command"].(string); ok {
+		return truncateString(cmd, 30)
+	}
+	if path, ok := inputMap["file_path"].(string); ok {
+		return filepath.Base(path)
+	}
+	if pattern, ok := inputMap["pattern"].(string); ok {
+		return truncateString(pattern, 20)
+	}
+	inputBytes, _ := json.Marshal(inputMap)
+	return truncateString(string(inputBytes), 30)
+}
+
+// decodeMessage decodes the JSON message payload of an event, which may be
+// double-encoded as a JSON string
+func decodeMessage(messageStr string) (map[string]interface{}, bool) {
+	if strings.HasPrefix(messageStr, `"`) && strings.HasSuffix(messageStr, `"`) {
+		var unquoted string
+		if err := json.Unmarshal([]byte(messageStr), &unquoted); err == nil {
+			messageStr = unquoted
+		}
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(messageStr), &payload); err != nil {
+		return nil, false
+	}
+	return payload, true
+}
+
+// truncateString collapses whitespace in s and truncates it to maxLen characters,
+// counted in runes so that multibyte characters are never split.
+// A maxLen of 0 leaves the length unchanged.
+func truncateString(s string, maxLen int) string {
+	s = strings.Join(strings.Fields(s), " ")
+
+	if maxLen <= 0 || utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	return string([]rune(s)[:maxLen]) + "..."
+}
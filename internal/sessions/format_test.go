@@ -0,0 +1,246 @@
+package sessions
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// TestFormatMessage tests formatting of the different message payloads
+func TestFormatMessage(t *testing.T) {
+	preview := FormatOptions{IncludeTools: true, MaxLength: 50}
+	textOnly := FormatOptions{MaxLength: 50}
+
+	tests := []struct {
+		name    string
+		msgType string
+		message string
+		opts    FormatOptions
+		want    string
+	}{
+		{
+			name:    "string content",
+			msgType: "user",
+			message: `{"role":"user","content":"Fix the\nfailing   test"}`,
+			opts:    preview,
+			want:    "[User] Fix the failing test",
+		},
+		{
+			name:    "double-encoded string content",
+			msgType: "user",
+			message: `"{\"content\":\"hello\"}"`,
+			opts:    preview,
+			want:    "[User] hello",
+		},
+		{
+			name:    "string content truncated",
+			msgType: "user",
+			message: `{"content":"abcdefghij"}`,
+			opts:    FormatOptions{MaxLength: 4},
+			want:    "[User] abcd...",
+		},
+		{
+			name:    "string content without limit",
+			msgType: "user",
+			message: `{"content":"abcdefghij"}`,
+			opts:    FormatOptions{},
+			want:    "[User] abcdefghij",
+		},
+		{
+			name:    "system reminder string",
+			msgType: "user",
+			message: `{"content":"<system-reminder>ignore</system-reminder>"}`,
+			opts:    preview,
+			want:    "",
+		},
+		{
+			name:    "text array",
+			msgType: "assistant",
+			message: `{"content":[{"type":"text","text":"First"},{"type":"text","text":"<system-reminder>x</system-reminder>"},{"type":"text","text":"Second"}]}`,
+			opts:    preview,
+			want:    "[Assistant] First | Second",
+		},
+		{
+			name:    "tool_use with command",
+			msgType: "assistant",
+			message: `{"content":[{"type":"text","text":"Listing files"},{"type":"tool_use","name":"Bash","input":{"command":"ls"}}]}`,
+			opts:    preview,
+			want:    "[Assistant] Listing files | 🔧 Bash: ls",
+		},
+		{
+			name:    "tool_use with file path",
+			msgType: "assistant",
+			message: `{"content":[{"type":"tool_use","name":"Read","input":{"file_path":"/src/main.go"}}]}`,
+			opts:    preview,
+			want:    "[Assistant] 🔧 Read: main.go",
+		},
+		{
+			name:    "tool_use left out",
+			msgType: "assistant",
+			message: `{"content":[{"type":"text","text":"Listing files"},{"type":"tool_use","name":"Bash","input":{"command":"ls"}}]}`,
+			opts:    textOnly,
+			want:    "[Assistant] Listing files",
+		},
+		{
+			name:    "tool_result string",
+			msgType: "user",
+			message: `{"content":[{"type":"tool_result","content":"main.go"}]}`,
+			opts:    preview,
+			want:    "[User] ↩ main.go",
+		},
+		{
+			name:    "tool_result text array",
+			msgType: "user",
+			message: `{"content":[{"type":"tool_result","content":[{"type":"text","text":"main.go"},{"type":"text","text":"go.mod"}]}]}`,
+			opts:    preview,
+			want:    "[User] ↩ main.go go.mod",
+		},
+		{
+			name:    "tool_result left out",
+			msgType: "user",
+			message: `{"content":[{"type":"tool_result","content":"main.go"}]}`,
+			opts:    textOnly,
+			want:    "",
+		},
+		{
+			name:    "tool_use skipped by message type",
+			msgType: "assistant",
+			message: `{"content":[{"type":"text","text":"Listing files"},{"type":"tool_use","name":"Bash","input":{"command":"ls"}}]}`,
+			opts:    FormatOptions{IncludeTools: true, SkipToolUse: true, MaxLength: 50},
+			want:    "[Assistant] Listing files",
+		},
+		{
+			name:    "tool_result skipped by message type",
+			msgType: "user",
+			message: `{"content":[{"type":"tool_result","content":"main.go"}]}`,
+			opts:    FormatOptions{IncludeTools: true, SkipToolResults: true, MaxLength: 50},
+			want:    "",
+		},
+		{
+			name:    "assistant text skipped by message type",
+			msgType: "assistant",
+			message: `{"content":[{"type":"text","text":"Listing files"},{"type":"tool_use","name":"Bash","input":{"command":"ls"}}]}`,
+			opts:    FormatOptions{IncludeTools: true, SkipAssistantText: true, MaxLength: 50},
+			want:    "[Assistant] 🔧 Bash: ls",
+		},
+		{
+			name:    "user text skipped by message type",
+			msgType: "user",
+			message: `{"content":"hi"}`,
+			opts:    FormatOptions{IncludeTools: true, SkipUserText: true, MaxLength: 50},
+			want:    "",
+		},
+		{
+			name:    "summary",
+			msgType: "summary",
+			message: `{"content":"Fix the login flow"}`,
+			opts:    preview,
+			want:    "[Summary] Fix the login flow",
+		},
+		{
+			name:    "other role",
+			msgType: "system",
+			message: `{"content":"compacted"}`,
+			opts:    preview,
+			want:    "[system] compacted",
+		},
+		{
+			name:    "invalid JSON",
+			msgType: "user",
+			message: `{"content":`,
+			opts:    preview,
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatMessage(tt.msgType, tt.message, tt.opts); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestTruncateStringRunes tests that truncation never splits a multibyte character
+func TestTruncateStringRunes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxLen   int
+		expected string
+	}{
+		{"ascii", "hello world", 5, "hello..."},
+		{"cjk", "修复登录页面的错误", 4, "修复登录..."},
+		{"emoji", "🚀🔥✨ launch", 2, "🚀🔥..."},
+		{"short cjk", "こんにちは", 5, "こんにちは"},
+		{"no limit", "日本語 テキスト", 0, "日本語 テキスト"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateString(tt.input, tt.maxLen)
+			if got != tt.expected {
+				t.Errorf("truncateString(%q, %d) = %q, want %q", tt.input, tt.maxLen, got, tt.expected)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateString(%q, %d) produced invalid UTF-8: %q", tt.input, tt.maxLen, got)
+			}
+		})
+	}
+}
+
+// TestPreviewFormatOptions tests that previews follow the messages-only setting
+func TestPreviewFormatOptions(t *testing.T) {
+	t.Cleanup(func() { SetMessagesOnly(false) })
+
+	if opts := previewFormatOptions(); !opts.IncludeTools {
+		t.Error("previews should include tool calls by default")
+	}
+	SetMessagesOnly(true)
+	if opts := previewFormatOptions(); opts.IncludeTools {
+		t.Error("previews should leave out tool calls in messages-only mode")
+	}
+}
+
+// TestTimedPreview tests the time prefix and the divider before long gaps
+func TestTimedPreview(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	var timed timedPreview
+
+	steps := []struct {
+		timestamp time.Time
+		want      []string
+	}{
+		{start, []string{"[10:00] [User] a"}},
+		{start.Add(time.Hour), []string{"[11:00] [User] a"}},
+		{start.Add(4*time.Hour + 5*time.Minute), []string{"⏱ 3h later", "[14:05] [User] a"}},
+		{time.Time{}, []string{"[User] a"}},
+		{start.Add(76 * time.Hour), []string{"⏱ 2d later", "[14:00] [User] a"}},
+	}
+	for i, step := range steps {
+		got := PreviewStrings(timed.lines(step.timestamp, "[User] a"))
+		if strings.Join(got, "\n") != strings.Join(step.want, "\n") {
+			t.Errorf("step %d: expected %q, got %q", i, step.want, got)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{30 * time.Second, "<1m"},
+		{45 * time.Minute, "45m"},
+		{time.Hour + 3*time.Minute, "1h03m"},
+		{23*time.Hour + 59*time.Minute, "23h59m"},
+		{51 * time.Hour, "2d3h"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.duration); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.duration, got, tt.want)
+		}
+	}
+}
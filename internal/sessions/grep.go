@@ -0,0 +1,69 @@
+package sessions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// GrepLine is a line of a session's messages found by GrepMessages: a match, or
+// a line of context around one
+type GrepLine struct {
+	Message int    // Position of the message in the session, from 1
+	Role    string // Role of the message, e.g. "user"
+	Line    int    // Position of the line in the message text, from 1
+	Text    string
+	Match   bool // False for a context line
+}
+
+// Follows reports whether l comes right after prev in the same message, so that
+// no separator is needed between them
+func (l GrepLine) Follows(prev GrepLine) bool {
+	return l.Message == prev.Message && l.Line == prev.Line+1
+}
+
+// messageLines returns the text of msg searched by GrepMessages: its content,
+// then its tool calls and their results
+func messageLines(msg models.Message) []string {
+	var lines []string
+	if msg.Content != "" {
+		lines = append(lines, strings.Split(msg.Content, "\n")...)
+	}
+	for _, call := range msg.ToolCalls {
+		lines = append(lines, fmt.Sprintf("[Tool Use: %s] %s", call.Name, call.Input))
+	}
+	for _, result := range msg.ToolResults {
+		lines = append(lines, strings.Split("[Tool Result] "+result, "\n")...)
+	}
+	return lines
+}
+
+// GrepMessages returns the lines of messages matching re, in order, each with up
+// to context lines of the same message before and after it. Lines shared by the
+// context of several matches are returned once.
+func GrepMessages(messages []models.Message, re *regexp.Regexp, context int) []GrepLine {
+	var found []GrepLine
+	for i, msg := range messages {
+		lines := messageLines(msg)
+		// Lines before next are already in found
+		next := 0
+		for n, text := range lines {
+			if !re.MatchString(text) {
+				continue
+			}
+			for c := max(n-context, next); c <= min(n+context, len(lines)-1); c++ {
+				found = append(found, GrepLine{
+					Message: i + 1,
+					Role:    msg.Role,
+					Line:    c + 1,
+					Text:    lines[c],
+					Match:   re.MatchString(lines[c]),
+				})
+			}
+			next = min(n+context, len(lines)-1) + 1
+		}
+	}
+	return found
+}
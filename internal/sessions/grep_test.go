@@ -0,0 +1,66 @@
+package sessions
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// TestGrepMessages tests that matches carry their message and line, that
+// context stays within a message and that overlapping context is not repeated
+func TestGrepMessages(t *testing.T) {
+	messages := []models.Message{
+		{Role: "user", Content: "fix the flaky test\nin the parser"},
+		{Role: "assistant", Content: "one\ntwo\nthree test\nfour\nfive test\nsix",
+			ToolCalls: []models.ToolCall{{Name: "Bash", Input: `{"command":"go test"}`}}},
+		{Role: "user", ToolResults: []string{"ok\nFAIL: TestParser"}},
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		context int
+		want    []GrepLine
+	}{
+		{"matches", "test$", 0, []GrepLine{
+			{1, "user", 1, "fix the flaky test", true},
+			{2, "assistant", 3, "three test", true},
+			{2, "assistant", 5, "five test", true},
+		}},
+		{"context within the message", "flaky", 1, []GrepLine{
+			{1, "user", 1, "fix the flaky test", true},
+			{1, "user", 2, "in the parser", false},
+		}},
+		{"overlapping context", "test$", 1, []GrepLine{
+			{1, "user", 1, "fix the flaky test", true},
+			{1, "user", 2, "in the parser", false},
+			{2, "assistant", 2, "two", false},
+			{2, "assistant", 3, "three test", true},
+			{2, "assistant", 4, "four", false},
+			{2, "assistant", 5, "five test", true},
+			{2, "assistant", 6, "six", false},
+		}},
+		{"tool calls and results", "go test|FAIL", 0, []GrepLine{
+			{2, "assistant", 7, `[Tool Use: Bash] {"command":"go test"}`, true},
+			{3, "user", 2, "FAIL: TestParser", true},
+		}},
+		{"case-insensitive", "(?i)fail", 0, []GrepLine{
+			{3, "user", 2, "FAIL: TestParser", true},
+		}},
+		{"no match", "nothing", 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GrepMessages(messages, regexp.MustCompile(tt.pattern), tt.context)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("line %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
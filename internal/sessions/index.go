@@ -0,0 +1,389 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/db"
+	"github.com/strrl/claude-resume/internal/paths"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// The session index is an opt-in copy of the few columns listings read, built by
+// BuildIndex so that they don't parse every session file. It holds a parquet
+// table of events and a manifest of the files it covers, with the first prompt
+// of each of their sessions. Listings read the files changed since the build
+// directly, so a stale index only makes them slower, never wrong.
+
+const (
+	indexEventsFile   = "events.parquet"
+	indexManifestFile = "manifest.json"
+	// indexFormat changes with the columns of the events table. An index in
+	// another format is ignored, and rebuilt from scratch by the next build.
+	indexFormat = 1
+)
+
+// indexManifest describes the session files covered by the session index
+type indexManifest struct {
+	Format      int                    `json:"format"`
+	ProjectsDir string                 `json:"projectsDir"`
+	Built       time.Time              `json:"built"`
+	Files       map[string]indexedFile `json:"files"`
+}
+
+// indexedFile is a session file as it was when indexed
+type indexedFile struct {
+	Size    int64             `json:"size"`
+	ModTime time.Time         `json:"modTime"`
+	Titles  map[string]string `json:"titles,omitempty"`  // First prompt of each session in the file
+	Replies map[string]string `json:"replies,omitempty"` // First reply of the sessions without a prompt
+}
+
+// unchanged reports whether a session file of size bytes modified at modTime is
+// still the one that was indexed
+func (f indexedFile) unchanged(size int64, modTime time.Time) bool {
+	return f.Size == size && f.ModTime.Equal(modTime)
+}
+
+// indexPlan tells the queries of a scanPlan which events to take from the index
+type indexPlan struct {
+	events  string            // Path of the events table
+	read    []string          // Indexed files read from the index, unchanged since the build and in scope
+	skip    []string          // Other indexed files: changed or removed since the build, or out of scope
+	live    []string          // Session files read directly, new or changed since the build
+	titles  map[string]string // First prompts of the sessions in the unchanged files
+	replies map[string]string // First replies of the sessions in the unchanged files without a prompt
+}
+
+// condition returns the WHERE condition selecting the indexed events to read,
+// naming whichever of the files to read or to skip are fewer
+func (p *indexPlan) condition() string {
+	switch {
+	case len(p.skip) == 0:
+		return "true"
+	case len(p.read) == 0:
+		return "false"
+	case len(p.read) < len(p.skip):
+		return "filename IN " + inList(p.read)
+	default:
+		return "filename NOT IN " + inList(p.skip)
+	}
+}
+
+// title returns the title of sessionID kept in the index and where it comes
+// from, NoSummary when there is none. It is safe to call on a nil plan.
+func (p *indexPlan) title(sessionID string) (string, models.SummarySource) {
+	if p == nil {
+		return "", models.NoSummary
+	}
+	if title, ok := p.titles[sessionID]; ok {
+		return title, models.FirstPrompt
+	}
+	if reply, ok := p.replies[sessionID]; ok {
+		return reply, models.FirstReply
+	}
+	return "", models.NoSummary
+}
+
+var indexDirOverride string
+
+// SetIndexDir overrides the directory holding the session index, empty for the default
+func SetIndexDir(dir string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	indexDirOverride = dir
+}
+
+// IndexDir returns the directory holding the session index, in paths.CacheDir
+// unless overridden
+func IndexDir() (string, error) {
+	settingsMu.RLock()
+	override := indexDirOverride
+	settingsMu.RUnlock()
+
+	if override != "" {
+		return override, nil
+	}
+
+	dir, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "index"), nil
+}
+
+// planIndex compares files, the session files under claudeDir, with the
+// session index in dir. Only the files among only are read, unless it is nil.
+// It returns nil when there is no index for claudeDir.
+func planIndex(claudeDir, dir string, files []sessionFile, only []string) (*indexPlan, error) {
+	manifest, err := loadIndexManifest(dir)
+	if err != nil || manifest == nil || manifest.ProjectsDir != claudeDir {
+		// An unreadable index is ignored rather than failing the listing
+		return nil, nil
+	}
+	events := filepath.Join(dir, indexEventsFile)
+	if _, err := os.Stat(events); err != nil {
+		return nil, nil
+	}
+
+	var inScope map[string]bool
+	if only != nil {
+		inScope = make(map[string]bool, len(only))
+		for _, path := range only {
+			inScope[path] = true
+		}
+	}
+
+	plan := &indexPlan{events: events, titles: make(map[string]string), replies: make(map[string]string)}
+	read := make(map[string]bool, len(files))
+	for _, file := range files {
+		scoped := inScope == nil || inScope[file.path]
+		indexed, ok := manifest.Files[file.path]
+		if ok && indexed.unchanged(file.size, file.modTime) {
+			if scoped {
+				read[file.path] = true
+				plan.read = append(plan.read, file.path)
+				for id, title := range indexed.Titles {
+					plan.titles[id] = title
+				}
+				for id, reply := range indexed.Replies {
+					plan.replies[id] = reply
+				}
+			}
+			continue
+		}
+		if scoped {
+			plan.live = append(plan.live, file.path)
+		}
+	}
+	for path := range manifest.Files {
+		if !read[path] {
+			plan.skip = append(plan.skip, path)
+		}
+	}
+	sort.Strings(plan.skip)
+	return plan, nil
+}
+
+// loadIndexManifest reads the manifest of the session index in dir, nil when
+// there is none or it is in another format, see indexFormat
+func loadIndexManifest(dir string) (*indexManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, indexManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session index: %w", err)
+	}
+	var manifest indexManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse session index: %w", err)
+	}
+	if manifest.Format != indexFormat {
+		return nil, nil
+	}
+	return &manifest, nil
+}
+
+// IndexStats describes a session index built by BuildIndex
+type IndexStats struct {
+	Dir       string // Directory holding the index
+	Files     int    // Session files covered
+	Bytes     int64  // Total size of the session files covered
+	Refreshed int    // Session files read by the build, the others were unchanged
+}
+
+// Message describes the build
+func (s IndexStats) Message() string {
+	return fmt.Sprintf("Indexed %d session files (%s) in %s, reading %d of them",
+		s.Files, formatBytes(s.Bytes), s.Dir, s.Refreshed)
+}
+
+// BuildIndex builds or refreshes the session index of the projects directory.
+// Only the session files new or changed since the previous build are read; the
+// rows of the others are copied from the previous index.
+func BuildIndex(ctx context.Context) (*IndexStats, error) {
+	claudeDir, err := ProjectsDir()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := IndexDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := listSessionFiles(claudeDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no session files found in %s", claudeDir)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	events := filepath.Join(dir, indexEventsFile)
+	previous, err := loadIndexManifest(dir)
+	if err == nil && previous != nil && previous.ProjectsDir == claudeDir {
+		if _, err := os.Stat(events); err != nil {
+			previous = nil
+		}
+	} else {
+		previous = nil
+	}
+
+	manifest := indexManifest{ProjectsDir: claudeDir, Built: time.Now(), Files: make(map[string]indexedFile)}
+	stats := &IndexStats{Dir: dir, Files: len(files)}
+	var reused, refreshed []string
+	for _, file := range files {
+		stats.Bytes += file.size
+		if previous != nil {
+			if indexed, ok := previous.Files[file.path]; ok && indexed.unchanged(file.size, file.modTime) {
+				reused = append(reused, file.path)
+				manifest.Files[file.path] = indexed
+				continue
+			}
+		}
+		refreshed = append(refreshed, file.path)
+		manifest.Files[file.path] = indexedFile{Size: file.size, ModTime: file.modTime}
+	}
+	stats.Refreshed = len(refreshed)
+
+	building := events + ".tmp"
+	if _, err := db.ExecContext(ctx, buildIndexQuery(events, building, reused, refreshed)); err != nil {
+		os.Remove(building)
+		return nil, fmt.Errorf("failed to build session index: %w", err)
+	}
+	if len(refreshed) > 0 {
+		if err := indexTitles(ctx, building, refreshed, manifest.Files); err != nil {
+			os.Remove(building)
+			return nil, err
+		}
+	}
+	if err := os.Rename(building, events); err != nil {
+		os.Remove(building)
+		return nil, fmt.Errorf("failed to replace session index: %w", err)
+	}
+
+	// The manifest goes last, so that an interrupted build leaves the previous
+	// one describing rows the new table still holds
+	if err := writeIndexManifest(dir, manifest); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// writeIndexManifest replaces the manifest of the session index in dir
+func writeIndexManifest(dir string, manifest indexManifest) error {
+	manifest.Format = indexFormat
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to encode session index: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".manifest-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write session index: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(dir, indexManifestFile)); err != nil {
+		return fmt.Errorf("failed to replace session index: %w", err)
+	}
+	return nil
+}
+
+// indexTitles records in files the first prompt of each session of the
+// refreshed session files, found in the events table at path, or its first
+// reply when it has no prompt
+func indexTitles(ctx context.Context, path string, refreshed []string, files map[string]indexedFile) error {
+	database, err := db.GetDB()
+	if err != nil {
+		return err
+	}
+	rows, err := database.QueryContext(ctx, indexedSessionsQuery(path, refreshed))
+	if err != nil {
+		return fmt.Errorf("failed to list indexed sessions: %w", err)
+	}
+	sessionFiles := make(map[string][]string)
+	var sessionIDs []string
+	for rows.Next() {
+		var filename, sessionID string
+		if err := rows.Scan(&filename, &sessionID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan indexed session: %w", err)
+		}
+		if _, ok := sessionFiles[sessionID]; !ok {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+		sessionFiles[sessionID] = append(sessionFiles[sessionID], filename)
+	}
+	rows.Close()
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	source := readJSON(listLiteral(refreshed))
+	query := firstMessagesFrom(source, "user", len(sessionIDs), firstPromptCandidates)
+	prompts := fetchFirstTitles(ctx, sessionIDs, "user", query, database)
+	var promptless []string
+	for _, id := range sessionIDs {
+		if _, ok := prompts[id]; !ok {
+			promptless = append(promptless, id)
+		}
+	}
+	var replies map[string]string
+	if len(promptless) > 0 {
+		query = firstMessagesFrom(source, "assistant", len(promptless), firstPromptCandidates)
+		replies = fetchFirstTitles(ctx, promptless, "assistant", query, database)
+	}
+
+	for id, filenames := range sessionFiles {
+		for _, filename := range filenames {
+			file := files[filename]
+			if prompt, ok := prompts[id]; ok {
+				file.Titles = setTitle(file.Titles, id, prompt)
+			} else if reply, ok := replies[id]; ok {
+				file.Replies = setTitle(file.Replies, id, reply)
+			}
+			files[filename] = file
+		}
+	}
+	return nil
+}
+
+// setTitle sets the title of sessionID in titles, creating the map when nil
+func setTitle(titles map[string]string, sessionID, title string) map[string]string {
+	if titles == nil {
+		titles = make(map[string]string)
+	}
+	titles[sessionID] = title
+	return titles
+}
+
+// RemoveIndex deletes the session index, so that listings read the session
+// files only. A missing index is not an error.
+func RemoveIndex() (string, error) {
+	dir, err := IndexDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("failed to remove %s: %w", dir, err)
+	}
+	return dir, nil
+}
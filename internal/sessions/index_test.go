@@ -0,0 +1,207 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// writeTestIndex writes a session index in dir over the events rows, for the
+// files described by manifest. The rows are SQL tuples of the indexed columns.
+func writeTestIndex(t *testing.T, database *sql.DB, dir string, manifest indexManifest, rows ...string) {
+	t.Helper()
+	query := `COPY (
+		SELECT *, NULL::VARCHAR AS messageId, NULL::VARCHAR[] AS contentTypes, NULL::BOOLEAN AS isSidechain
+		FROM (VALUES ` + strings.Join(rows, ", ") + `)
+		AS events(filename, sessionId, uuid, parentUuid, leafUuid, type, cwd, timestamp, gitBranch, summary)
+	) TO ` + quoteLiteral(filepath.Join(dir, indexEventsFile)) + ` (FORMAT parquet)`
+	if _, err := database.Exec(query); err != nil {
+		t.Fatalf("failed to write index: %v", err)
+	}
+	if err := writeIndexManifest(dir, manifest); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+}
+
+// indexedFileOf describes path as it is on disk
+func indexedFileOf(t *testing.T, path string, titles map[string]string) indexedFile {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return indexedFile{Size: info.Size(), ModTime: info.ModTime(), Titles: titles}
+}
+
+// TestIndexPlan tests that files changed or removed since the index was built
+// are skipped in it, and that changed and new files are read directly
+func TestIndexPlan(t *testing.T) {
+	projects := t.TempDir()
+	indexDir := t.TempDir()
+	SetProjectsDir(projects)
+	SetIndexDir(indexDir)
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		SetIndexDir("")
+	})
+
+	writeAgedFiles(t, projects, 10, "fresh", "changed", "new")
+	path := func(name string) string { return filepath.Join(projects, "-tmp-project", name+".jsonl") }
+	changed := indexedFileOf(t, path("changed"), nil)
+	changed.Size--
+	fresh := indexedFileOf(t, path("fresh"), map[string]string{"s1": "Fix the tests"})
+	fresh.Replies = map[string]string{"s2": "The tests pass now"}
+	manifest := indexManifest{ProjectsDir: projects, Built: time.Now(), Files: map[string]indexedFile{
+		path("fresh"):   fresh,
+		path("changed"): changed,
+		path("removed"): {Size: 10},
+	}}
+	if err := os.WriteFile(filepath.Join(indexDir, indexEventsFile), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeIndexManifest(indexDir, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := listSessionFiles(projects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := planIndex(projects, indexDir, files, nil)
+	if err != nil {
+		t.Fatalf("planIndex failed: %v", err)
+	}
+	if got, want := strings.Join(index.skip, ","), path("changed")+","+path("removed"); got != want {
+		t.Errorf("skip = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(index.live, ","), path("changed")+","+path("new"); got != want {
+		t.Errorf("live = %s, want %s", got, want)
+	}
+	if title, source := index.title("s1"); source != models.FirstPrompt || title != "Fix the tests" {
+		t.Errorf("title(s1) = %q, %v, want the indexed prompt", title, source)
+	}
+	if title, source := index.title("s2"); source != models.FirstReply || title != "The tests pass now" {
+		t.Errorf("title(s2) = %q, %v, want the indexed reply", title, source)
+	}
+
+	// The scan budget keeps the newest files only
+	index, err = planIndex(projects, indexDir, files, []string{path("fresh")})
+	if err != nil {
+		t.Fatalf("planIndex failed: %v", err)
+	}
+	if len(index.live) != 0 {
+		t.Errorf("live = %v, want none outside the budget", index.live)
+	}
+	if !strings.Contains(index.condition(), "filename IN ("+quoteLiteral(path("fresh"))+")") {
+		t.Errorf("condition %s doesn't narrow the index to the budget", index.condition())
+	}
+
+	// An index of another projects directory is ignored
+	if index, err := planIndex(t.TempDir(), indexDir, files, nil); err != nil || index != nil {
+		t.Errorf("planIndex of another directory = %v, %v, want no plan", index, err)
+	}
+
+	plan, err := listingPlan()
+	if err != nil {
+		t.Fatalf("listingPlan failed: %v", err)
+	}
+	source := eventsSource(plan)
+	if !strings.Contains(source, "read_parquet(") || !strings.Contains(source, readJSON(listLiteral([]string{path("changed"), path("new")}))) {
+		t.Errorf("events source doesn't read the index and the changed files: %s", source)
+	}
+	if !strings.Contains(firstMessagesQuery(plan, "user", 1, firstPromptCandidates), readJSON(listLiteral([]string{path("changed"), path("new")}))) {
+		t.Error("first prompts query doesn't read only the changed files")
+	}
+}
+
+// TestIndexedListing tests that listings read an up-to-date index without
+// touching the session files, which needs no DuckDB extension
+func TestIndexedListing(t *testing.T) {
+	projects := t.TempDir()
+	indexDir := t.TempDir()
+	SetProjectsDir(projects)
+	SetIndexDir(indexDir)
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		SetIndexDir("")
+	})
+
+	database, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("failed to open DuckDB: %v", err)
+	}
+	defer database.Close()
+
+	// The file content is not read, the index stands in for it
+	writeAgedFiles(t, projects, 10, "a", "b")
+	a := filepath.Join(projects, "-tmp-project", "a.jsonl")
+	b := filepath.Join(projects, "-tmp-project", "b.jsonl")
+	manifest := indexManifest{ProjectsDir: projects, Built: time.Now(), Files: map[string]indexedFile{
+		a: indexedFileOf(t, a, nil),
+		b: indexedFileOf(t, b, map[string]string{"s2": "Write the docs"}),
+	}}
+	writeTestIndex(t, database, indexDir, manifest,
+		"("+quoteLiteral(a)+", 's1', 'u1', NULL, NULL, 'user', '/proj', TIMESTAMP '2025-01-01 10:00:00', 'main', NULL)",
+		"("+quoteLiteral(a)+", 's1', 'u2', 'u1', NULL, 'assistant', '/proj', TIMESTAMP '2025-01-01 11:00:00', 'main', NULL)",
+		"("+quoteLiteral(a)+", NULL, NULL, NULL, 'u2', 'summary', NULL, NULL, NULL, 'Fix the tests')",
+		"("+quoteLiteral(b)+", 's2', 'u3', NULL, NULL, 'user', '/other', TIMESTAMP '2025-01-02 10:00:00', NULL, NULL)",
+	)
+
+	plan, err := listingPlan()
+	if err != nil {
+		t.Fatalf("listingPlan failed: %v", err)
+	}
+	rows, err := database.Query(recentSessionsQuery(plan, 10))
+	if err != nil {
+		t.Fatalf("recent sessions query failed: %v", err)
+	}
+	var got []string
+	for rows.Next() {
+		var sessionID, projectPath string
+		var lastActivity time.Time
+		var resumed, multipleDirs bool
+		if err := rows.Scan(&sessionID, &projectPath, &lastActivity, &resumed, &multipleDirs); err != nil {
+			t.Fatalf("failed to scan session: %v", err)
+		}
+		got = append(got, sessionID+" "+projectPath)
+	}
+	rows.Close()
+	if want := "s2 /other,s1 /proj"; strings.Join(got, ",") != want {
+		t.Errorf("recent sessions = %v, want %s", got, want)
+	}
+
+	rows, err = database.Query(projectsQuery(plan, 10, 0))
+	if err != nil {
+		t.Fatalf("projects query failed: %v", err)
+	}
+	got = nil
+	for rows.Next() {
+		var projectPath string
+		var sessionCount, messageCount, total int
+		var lastActivity time.Time
+		var latestSessionID string
+		if err := rows.Scan(&projectPath, &sessionCount, &messageCount, &lastActivity, &latestSessionID, &total); err != nil {
+			t.Fatalf("failed to scan project: %v", err)
+		}
+		got = append(got, fmt.Sprintf("%s %d/%d", projectPath, sessionCount, messageCount))
+	}
+	rows.Close()
+	if want := "/other 1/1,/proj 1/2"; strings.Join(got, ",") != want {
+		t.Errorf("projects = %v, want %s", got, want)
+	}
+
+	titles, sources := sessionTitles(context.Background(), []string{"s1", "s2"}, plan, database)
+	if titles["s1"] != "Fix the tests" || titles["s2"] != "Write the docs" {
+		t.Errorf("titles = %v, want the summary of s1 and the prompt of s2", titles)
+	}
+	if sources["s1"] != models.ClaudeSummary || sources["s2"] != models.FirstPrompt {
+		t.Errorf("sources = %v, want a Claude summary for s1 and a prompt for s2", sources)
+	}
+}
@@ -0,0 +1,164 @@
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/strrl/claude-resume/internal/paths"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// Labels are nicknames the user gives sessions, whose IDs are UUIDs. They are
+// kept apart from Claude Code's data, in a JSON object mapping session IDs to
+// labels.
+
+var (
+	// labelsMu serializes read-modify-write cycles of the labels file
+	labelsMu       sync.Mutex
+	labelsOverride string
+)
+
+// SetLabelsPath overrides the location of the labels file, empty for the default
+func SetLabelsPath(path string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	labelsOverride = path
+}
+
+// LabelsPath returns the location of the labels file, in paths.ConfigDir
+// unless overridden
+func LabelsPath() (string, error) {
+	settingsMu.RLock()
+	override := labelsOverride
+	settingsMu.RUnlock()
+
+	if override != "" {
+		return override, nil
+	}
+
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "labels.json"), nil
+}
+
+// LoadLabels returns the label of each labeled session. A missing file has none.
+func LoadLabels() (map[string]string, error) {
+	labelsMu.Lock()
+	defer labelsMu.Unlock()
+	return loadLabels()
+}
+
+func loadLabels() (map[string]string, error) {
+	path, err := LabelsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return labels, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read labels: %w", err)
+	}
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse labels %s: %w", path, err)
+	}
+	return labels, nil
+}
+
+// SetLabel gives a session a label and persists it. An empty label removes the
+// session's label.
+func SetLabel(sessionID, label string) error {
+	if sessionID == "" {
+		return fmt.Errorf("session ID must not be empty")
+	}
+	label = strings.Join(strings.Fields(label), " ")
+
+	labelsMu.Lock()
+	defer labelsMu.Unlock()
+
+	labels, err := loadLabels()
+	if err != nil {
+		return err
+	}
+	if label == "" {
+		delete(labels, sessionID)
+	} else {
+		labels[sessionID] = label
+	}
+	return saveLabels(labels)
+}
+
+// saveLabels atomically writes the labels file
+func saveLabels(labels map[string]string) error {
+	path, err := LabelsPath()
+	if err != nil {
+		return err
+	}
+
+	// Map keys are encoded sorted, so the file diffs well
+	data, err := json.MarshalIndent(labels, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode labels: %w", err)
+	}
+
+	if err := paths.MkdirFor(path); err != nil {
+		return fmt.Errorf("failed to create labels directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".labels-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	_, err = tmp.Write(append(data, '\n'))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write labels: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// MarkLabels sets the Label of each session from the labels file
+func MarkLabels(list []models.Session) error {
+	labels, err := LoadLabels()
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		list[i].Label = labels[list[i].SessionID]
+	}
+	return nil
+}
+
+// labeledSessionIDs returns the IDs of the sessions labeled label, ignoring case
+func labeledSessionIDs(label string) ([]string, error) {
+	labels, err := LoadLabels()
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for id, l := range labels {
+		if strings.EqualFold(l, label) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
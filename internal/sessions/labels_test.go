@@ -0,0 +1,49 @@
+package sessions
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// TestSetLabel tests that labels are kept per session ID, cleaned of extra
+// spaces, removed when empty and found again ignoring case
+func TestSetLabel(t *testing.T) {
+	SetLabelsPath(filepath.Join(t.TempDir(), "config", "labels.json"))
+	t.Cleanup(func() { SetLabelsPath("") })
+
+	labels, err := LoadLabels()
+	if err != nil || len(labels) != 0 {
+		t.Fatalf("a missing labels file should have no labels, got %v, %v", labels, err)
+	}
+
+	if err := SetLabel("a", "  auth   refactor "); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLabel("b", "spike"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLabel("c", "Spike"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLabel("c", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLabel("", "x"); err == nil {
+		t.Error("labeling no session should fail")
+	}
+
+	list := []models.Session{{SessionID: "a"}, {SessionID: "b"}, {SessionID: "c"}}
+	if err := MarkLabels(list); err != nil {
+		t.Fatal(err)
+	}
+	if list[0].Label != "auth refactor" || list[1].Label != "spike" || list[2].Label != "" {
+		t.Errorf("labels = %q, %q, %q", list[0].Label, list[1].Label, list[2].Label)
+	}
+
+	if ids, err := labeledSessionIDs("SPIKE"); err != nil || !reflect.DeepEqual(ids, []string{"b"}) {
+		t.Errorf("sessions labeled SPIKE = %v, %v, want [b]", ids, err)
+	}
+}
@@ -0,0 +1,43 @@
+package sessions
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path/filepath"
+)
+
+// MalformedFile reports the lines of a session file that are not valid JSON
+type MalformedFile struct {
+	Path  string
+	Lines int
+}
+
+// ScanMalformedLines reads every session file and reports those containing lines
+// that are not valid JSON. Queries skip such lines, so this tells the user what
+// was left out. Unreadable entries are skipped.
+func ScanMalformedLines() ([]MalformedFile, error) {
+	claudeDir, err := ProjectsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var malformed []MalformedFile
+	err = filepath.WalkDir(claudeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".jsonl" {
+			return nil
+		}
+		count := 0
+		if err := forEachLine(path, func(line []byte) {
+			if !json.Valid(line) {
+				count++
+			}
+		}); err != nil {
+			return nil
+		}
+		if count > 0 {
+			malformed = append(malformed, MalformedFile{Path: path, Lines: count})
+		}
+		return nil
+	})
+	return malformed, err
+}
@@ -0,0 +1,58 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// setupTruncatedSession copies the fixture whose final line was cut off mid-write into a projects directory
+func setupTruncatedSession(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "truncated.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := writeSessionFixture(t, map[string]string{"-tmp-truncated/t1.jsonl": string(data)})
+	return filepath.Join(dir, "-tmp-truncated", "t1.jsonl")
+}
+
+// TestScanMalformedLines tests that a truncated final line is reported
+func TestScanMalformedLines(t *testing.T) {
+	path := setupTruncatedSession(t)
+
+	malformed, err := ScanMalformedLines()
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if len(malformed) != 1 || malformed[0].Path != path || malformed[0].Lines != 1 {
+		t.Errorf("expected one malformed line in %s, got %+v", path, malformed)
+	}
+}
+
+// TestQueriesSkipMalformedLines tests that a truncated line doesn't hide the rest of the session
+func TestQueriesSkipMalformedLines(t *testing.T) {
+	if _, err := db.GetDB(); err != nil {
+		t.Skipf("Skipping test, database unavailable: %v", err)
+	}
+	setupTruncatedSession(t)
+
+	projects, _, err := FetchProjectsPage(10, 0)
+	if err != nil {
+		t.Fatalf("FetchProjectsPage failed: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Path != "/tmp/truncated" || projects[0].SessionCount != 1 {
+		t.Fatalf("expected the truncated project to be listed, got %+v", projects)
+	}
+
+	messages, err := FetchMessages("t1")
+	if err != nil {
+		t.Fatalf("FetchMessages failed: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Errorf("expected the two intact messages, got %d", len(messages))
+	}
+}
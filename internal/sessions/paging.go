@@ -0,0 +1,72 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// CountProjects returns the total number of projects, regardless of the page limit
+func CountProjects() (int, error) {
+	plan, err := listingPlan()
+	if err != nil {
+		return 0, err
+	}
+
+	row, err := db.QueryRowContext(context.Background(), countProjectsQuery(plan))
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count projects: %w", err)
+	}
+	return count, nil
+}
+
+// CountSessions returns the total number of sessions of a project, regardless of the page limit
+func CountSessions(projectPath string) (int, error) {
+	plan, err := listingPlan()
+	if err != nil {
+		return 0, err
+	}
+
+	query, args := countSessionsQuery(plan, projectPath)
+	row, err := db.QueryRowContext(context.Background(), query, args...)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count sessions: %w", err)
+	}
+	return count, nil
+}
+
+// CountTotals returns the total number of projects and of sessions across all
+// projects, regardless of the page limit
+func CountTotals(ctx context.Context) (projects, sessions int, err error) {
+	plan, err := listingPlan()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	row, err := db.QueryRowContext(ctx, countTotalsQuery(plan))
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := row.Scan(&projects, &sessions); err != nil {
+		return 0, 0, fmt.Errorf("failed to count sessions: %w", err)
+	}
+	return projects, sessions, nil
+}
+
+// FormatPageRange describes which part of a paged listing is shown,
+// e.g. "Showing 1–100 of 237"
+func FormatPageRange(offset, count, total int) string {
+	if count == 0 {
+		return fmt.Sprintf("Showing 0 of %d", total)
+	}
+	return fmt.Sprintf("Showing %d–%d of %d", offset+1, offset+count, total)
+}
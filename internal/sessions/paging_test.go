@@ -0,0 +1,20 @@
+package sessions
+
+import "testing"
+
+// TestFormatPageRange tests the paging indicator
+func TestFormatPageRange(t *testing.T) {
+	tests := []struct {
+		offset, count, total int
+		want                 string
+	}{
+		{0, 100, 237, "Showing 1–100 of 237"},
+		{200, 37, 237, "Showing 201–237 of 237"},
+		{0, 0, 0, "Showing 0 of 0"},
+	}
+	for _, tt := range tests {
+		if got := FormatPageRange(tt.offset, tt.count, tt.total); got != tt.want {
+			t.Errorf("FormatPageRange(%d, %d, %d) = %q, want %q", tt.offset, tt.count, tt.total, got, tt.want)
+		}
+	}
+}
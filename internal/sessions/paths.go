@@ -0,0 +1,140 @@
+package sessions
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/paths"
+)
+
+var projectsDirOverride string
+
+// SetProjectsDir overrides the directory containing Claude Code's project session files
+func SetProjectsDir(dir string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	projectsDirOverride = dir
+}
+
+// ProjectsDir returns the directory containing Claude Code's project session files.
+// An explicit SetProjectsDir override wins, then $CLAUDE_CONFIG_DIR/projects,
+// then ~/.claude/projects. Without a home directory, $XDG_CONFIG_HOME/claude/projects
+// is used when XDG_CONFIG_HOME is set, and paths.ErrNoHomeDir returned otherwise.
+func ProjectsDir() (string, error) {
+	settingsMu.RLock()
+	override := projectsDirOverride
+	settingsMu.RUnlock()
+
+	if override != "" {
+		return override, nil
+	}
+
+	if configDir := os.Getenv("CLAUDE_CONFIG_DIR"); configDir != "" {
+		return filepath.Join(configDir, "projects"), nil
+	}
+
+	homeDir, err := paths.HomeDir()
+	if err != nil {
+		// The specification of XDG_CONFIG_HOME says to ignore relative paths
+		if configHome := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(configHome) {
+			return filepath.Join(configHome, "claude", "projects"), nil
+		}
+		return "", err
+	}
+	return filepath.Join(homeDir, ".claude", "projects"), nil
+}
+
+// LatestModTime returns the most recent modification time of any session file,
+// or the zero time when there are none. Unreadable entries are skipped.
+func LatestModTime() (time.Time, error) {
+	stamp, err := sessionFilesStamp()
+	return stamp.modTime, err
+}
+
+// filesStamp summarizes the session files on disk. Appending to a file, adding
+// one or removing one changes the stamp.
+type filesStamp struct {
+	modTime time.Time
+	count   int
+}
+
+func (s filesStamp) equal(other filesStamp) bool {
+	return s.count == other.count && s.modTime.Equal(other.modTime)
+}
+
+// sessionFilesStamp walks the projects directory and stamps its session files.
+// Unreadable entries are skipped.
+func sessionFilesStamp() (filesStamp, error) {
+	claudeDir, err := ProjectsDir()
+	if err != nil {
+		return filesStamp{}, err
+	}
+	files, err := listSessionFiles(claudeDir)
+	if err != nil {
+		return filesStamp{}, err
+	}
+	return stampFiles(files), nil
+}
+
+// stampFiles stamps the session files found by listSessionFiles
+func stampFiles(files []sessionFile) filesStamp {
+	stamp := filesStamp{count: len(files)}
+	for _, file := range files {
+		if file.modTime.After(stamp.modTime) {
+			stamp.modTime = file.modTime
+		}
+	}
+	return stamp
+}
+
+// SessionFileModTime returns the modification time of the file named after a
+// session, or the zero time when there is no such file
+func SessionFileModTime(sessionID string) (time.Time, error) {
+	claudeDir, err := ProjectsDir()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(claudeDir, "*", sessionID+".jsonl"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to find session file: %w", err)
+	}
+
+	var latest time.Time
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// SessionFileModTimes maps session IDs to the modification time of the file
+// named after them. Unreadable entries are skipped.
+func SessionFileModTimes() (map[string]time.Time, error) {
+	claudeDir, err := ProjectsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	modTimes := make(map[string]time.Time)
+	err = filepath.WalkDir(claudeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".jsonl" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		sessionID := strings.TrimSuffix(d.Name(), ".jsonl")
+		if info.ModTime().After(modTimes[sessionID]) {
+			modTimes[sessionID] = info.ModTime()
+		}
+		return nil
+	})
+	return modTimes, err
+}
@@ -0,0 +1,105 @@
+package sessions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/paths"
+)
+
+// TestProjectsDir tests the resolution order of the projects directory
+func TestProjectsDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Cleanup(func() { SetProjectsDir("") })
+
+	dir, err := ProjectsDir()
+	if err != nil {
+		t.Fatalf("ProjectsDir failed: %v", err)
+	}
+	if want := filepath.Join(home, ".claude", "projects"); dir != want {
+		t.Errorf("expected default %s, got %s", want, dir)
+	}
+
+	t.Setenv("CLAUDE_CONFIG_DIR", "/tmp/claude-config")
+	if dir, _ := ProjectsDir(); dir != filepath.Join("/tmp/claude-config", "projects") {
+		t.Errorf("expected CLAUDE_CONFIG_DIR to be honored, got %s", dir)
+	}
+
+	SetProjectsDir("/srv/projects")
+	if dir, _ := ProjectsDir(); dir != "/srv/projects" {
+		t.Errorf("expected explicit override to win, got %s", dir)
+	}
+}
+
+// TestProjectsDirWithoutHome tests that XDG_CONFIG_HOME stands in for a missing
+// home directory, and that the error says what is missing otherwise
+func TestProjectsDirWithoutHome(t *testing.T) {
+	t.Setenv("HOME", "")
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Setenv("XDG_CONFIG_HOME", "/srv/config")
+
+	if dir, err := ProjectsDir(); err != nil || dir != filepath.Join("/srv/config", "claude", "projects") {
+		t.Errorf("expected the XDG config directory, got %s (%v)", dir, err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "relative")
+	if _, err := ProjectsDir(); !errors.Is(err, paths.ErrNoHomeDir) {
+		t.Errorf("expected ErrNoHomeDir, got %v", err)
+	}
+	if _, err := listingPlan(); !errors.Is(err, paths.ErrNoHomeDir) {
+		t.Errorf("expected listings to fail with ErrNoHomeDir, got %v", err)
+	}
+}
+
+// TestLatestModTime tests that the newest session file determines the modification time
+func TestLatestModTime(t *testing.T) {
+	dir := t.TempDir()
+	SetProjectsDir(dir)
+	t.Cleanup(func() { SetProjectsDir("") })
+
+	if latest, err := LatestModTime(); err != nil || !latest.IsZero() {
+		t.Fatalf("expected zero time for an empty directory, got %v (%v)", latest, err)
+	}
+
+	older := time.Now().Add(-time.Hour).Truncate(time.Second)
+	newer := time.Now().Truncate(time.Second)
+	files := map[string]time.Time{
+		filepath.Join(dir, "a", "old.jsonl"): older,
+		filepath.Join(dir, "b", "new.jsonl"): newer,
+		filepath.Join(dir, "b", "notes.txt"): newer.Add(time.Hour), // Ignored
+	}
+	for path, mtime := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latest, err := LatestModTime()
+	if err != nil {
+		t.Fatalf("LatestModTime failed: %v", err)
+	}
+	if !latest.Equal(newer) {
+		t.Errorf("expected %v, got %v", newer, latest)
+	}
+}
+
+// TestLatestModTimeMissingDir tests that a missing projects directory is not an error
+func TestLatestModTimeMissingDir(t *testing.T) {
+	SetProjectsDir(filepath.Join(t.TempDir(), "missing"))
+	t.Cleanup(func() { SetProjectsDir("") })
+
+	if latest, err := LatestModTime(); err != nil || !latest.IsZero() {
+		t.Errorf("expected zero time and no error, got %v (%v)", latest, err)
+	}
+}
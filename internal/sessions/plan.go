@@ -0,0 +1,125 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// scanPlan tells the queries built on it which session files to read: every
+// file matched by glob, or only the newest ones within the scan budget, the
+// files unchanged since the build of the session index being read from it.
+// It is made by listingPlan or lookupPlan and passed explicitly to the query
+// builders.
+type scanPlan struct {
+	glob  string     // Pattern matching every session file
+	only  []string   // Files the scan budget narrows the reading to, nil for all
+	index *indexPlan // Use of the session index, nil when there is none
+}
+
+// globPlan returns the plan reading every file matched by globPattern directly
+func globPlan(globPattern string) *scanPlan {
+	return &scanPlan{glob: globPattern}
+}
+
+// planKey identifies what a plan was made from: the session files on disk, the
+// scan budget and the session index
+type planKey struct {
+	claudeDir    string
+	indexDir     string
+	budget       ScanBudget
+	stamp        filesStamp
+	manifestTime time.Time // Modification time of the index manifest
+}
+
+func (k planKey) equal(other planKey) bool {
+	return k.claudeDir == other.claudeDir && k.indexDir == other.indexDir && k.budget == other.budget &&
+		k.stamp.equal(other.stamp) && k.manifestTime.Equal(other.manifestTime)
+}
+
+// cachedPlan is a plan along with what it was made from
+type cachedPlan struct {
+	plan *scanPlan
+	key  planKey
+}
+
+var (
+	planMu sync.Mutex
+	// listingCache and lookupCache are reused by planScan until their key changes
+	listingCache, lookupCache cachedPlan
+)
+
+// listingPlan plans how listings read the session files: over the scan budget
+// only the newest files are read, and the limit is reported by ScanLimited
+func listingPlan() (*scanPlan, error) {
+	return planScan(true)
+}
+
+// lookupPlan plans how the lookups of a single session read the session files:
+// all of them, as the scan budget only bounds listings, so that a session is
+// found however old it is
+func lookupPlan() (*scanPlan, error) {
+	return planScan(false)
+}
+
+// planScan plans how queries read the session files, within the scan budget
+// when budgeted. The projects directory is walked once, for both the budget and
+// the session index, and not at all when neither applies. A plan is reused for
+// as long as neither the files nor the budget or the index change.
+func planScan(budgeted bool) (*scanPlan, error) {
+	claudeDir, err := ProjectsDir()
+	if err != nil {
+		return nil, err
+	}
+	indexDir, err := IndexDir()
+	if err != nil {
+		return nil, err
+	}
+	key := planKey{claudeDir: claudeDir, indexDir: indexDir}
+	if budgeted {
+		budgetMu.Lock()
+		key.budget = scanBudget
+		budgetMu.Unlock()
+	}
+	manifest, err := os.Stat(filepath.Join(indexDir, indexManifestFile))
+	if err != nil && key.budget == (ScanBudget{}) {
+		if budgeted {
+			setScanLimit(nil)
+		}
+		return globPlan(filepath.Join(claudeDir, "**", "*.jsonl")), nil
+	}
+	if err == nil {
+		key.manifestTime = manifest.ModTime()
+	}
+
+	files, err := listSessionFiles(claudeDir)
+	if err != nil {
+		return nil, err
+	}
+	key.stamp = stampFiles(files)
+
+	planMu.Lock()
+	defer planMu.Unlock()
+	cache := &lookupCache
+	if budgeted {
+		cache = &listingCache
+	}
+	if cache.plan != nil && cache.key.equal(key) {
+		return cache.plan, nil
+	}
+
+	plan := globPlan(filepath.Join(claudeDir, "**", "*.jsonl"))
+	var limit *ScanLimit
+	if key.budget != (ScanBudget{}) {
+		plan.only, limit = newestWithinBudget(files, key.budget)
+	}
+	if plan.index, err = planIndex(claudeDir, indexDir, files, plan.only); err != nil {
+		return nil, err
+	}
+	if budgeted {
+		setScanLimit(limit)
+	}
+	*cache = cachedPlan{plan: plan, key: key}
+	return plan, nil
+}
@@ -0,0 +1,76 @@
+package sessions
+
+import (
+	"testing"
+)
+
+// TestListingPlanReused tests that the plan of listings is made once for as
+// long as the session files and the scan budget stay the same
+func TestListingPlanReused(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedFiles(t, dir, 10, "a", "b")
+	SetProjectsDir(dir)
+	SetIndexDir(t.TempDir())
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		SetIndexDir("")
+		SetScanBudget(ScanBudget{})
+	})
+
+	SetScanBudget(ScanBudget{MaxFiles: 1})
+	first, err := listingPlan()
+	if err != nil {
+		t.Fatalf("listingPlan failed: %v", err)
+	}
+	if again, err := listingPlan(); err != nil || again != first {
+		t.Errorf("unchanged files planned again: %v", err)
+	}
+
+	SetScanBudget(ScanBudget{MaxFiles: 2})
+	raised, err := listingPlan()
+	if err != nil {
+		t.Fatalf("listingPlan failed: %v", err)
+	}
+	if raised == first || raised.only != nil {
+		t.Errorf("a new budget kept the plan reading %v", raised.only)
+	}
+
+	writeAgedFiles(t, dir, 10, "c")
+	if added, err := listingPlan(); err != nil || added == raised || len(added.only) != 2 {
+		t.Errorf("a new session file kept the plan: %v", err)
+	}
+}
+
+// TestLookupPlanIgnoresBudget tests that lookups read every session file while
+// listings are narrowed by the scan budget
+func TestLookupPlanIgnoresBudget(t *testing.T) {
+	dir := t.TempDir()
+	writeAgedFiles(t, dir, 10, "new", "old")
+	SetProjectsDir(dir)
+	SetIndexDir(t.TempDir())
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		SetIndexDir("")
+		SetScanBudget(ScanBudget{})
+	})
+
+	SetScanBudget(ScanBudget{MaxFiles: 1})
+	listing, err := listingPlan()
+	if err != nil {
+		t.Fatalf("listingPlan failed: %v", err)
+	}
+	if len(listing.only) != 1 {
+		t.Errorf("listing reads %v, want the newest file only", listing.only)
+	}
+
+	lookup, err := lookupPlan()
+	if err != nil {
+		t.Fatalf("lookupPlan failed: %v", err)
+	}
+	if lookup.only != nil {
+		t.Errorf("lookup reads %v, want every file", lookup.only)
+	}
+	if limit, ok := ScanLimited(); !ok || limit.ReadFiles != 1 {
+		t.Errorf("got limit %+v (%v), want the one of the listing", limit, ok)
+	}
+}
@@ -0,0 +1,73 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// FetchRecentMessagesForSessions fetches the first and last N messages of each of
+// the sessions, like FetchRecentMessagesForSession, in a single query. Listing a
+// project's sessions this way scans the history once rather than once per session.
+// Sessions without messages are missing from the result.
+func FetchRecentMessagesForSessions(ctx context.Context, sessionIDs []string) (map[string][]PreviewLine, error) {
+	previews := make(map[string][]PreviewLine)
+	if len(sessionIDs) == 0 {
+		return previews, nil
+	}
+
+	plan, err := listingPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	previewCount := getPreviewCount()
+	args := make([]interface{}, 0, len(sessionIDs)+4)
+	for _, id := range sessionIDs {
+		args = append(args, id)
+	}
+	args = append(args, previewCount, previewCount, previewCount, previewCount)
+
+	rows, err := db.QueryContext(ctx, batchRecentMessagesQuery(plan, len(sessionIDs)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute messages query: %w", err)
+	}
+	defer rows.Close()
+
+	// Rows arrive grouped by session, each session's in timestamp order
+	builders := make(map[string]*previewBuilder)
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var sessionID string
+		var messageType sql.NullString
+		var messageJSON sql.NullString
+		var timestamp sql.NullString
+		var position sql.NullString
+		var count sql.NullInt64
+
+		if err := rows.Scan(&sessionID, &messageType, &messageJSON, &timestamp, &position, &count); err != nil {
+			continue
+		}
+		builder, ok := builders[sessionID]
+		if !ok {
+			builder = newPreviewBuilder(previewCount)
+			builders[sessionID] = builder
+		}
+		builder.add(messageType, messageJSON, timestamp, position, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	for sessionID, builder := range builders {
+		if messages, _ := builder.result(); len(messages) > 0 {
+			previews[sessionID] = messages
+		}
+	}
+	return previews, nil
+}
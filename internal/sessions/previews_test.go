@@ -0,0 +1,96 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// writePreviewSessions writes count sessions of 30 messages each to a new
+// projects directory and returns their IDs
+func writePreviewSessions(tb testing.TB, count int) []string {
+	dir := tb.TempDir()
+	SetProjectsDir(dir)
+	tb.Cleanup(func() { SetProjectsDir("") })
+
+	projectDir := filepath.Join(dir, "-tmp-previews")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		tb.Fatal(err)
+	}
+	ids := make([]string, count)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("preview-%03d", i)
+		var lines strings.Builder
+		for j := 0; j < 30; j++ {
+			role := "user"
+			if j%2 == 1 {
+				role = "assistant"
+			}
+			fmt.Fprintf(&lines, `{"type":"%s","sessionId":"%s","uuid":"%s-%d","cwd":"/tmp/previews","timestamp":"2025-01-01T00:%02d:00Z","message":{"role":"%s","content":"message %d of session %d"}}`+"\n", role, ids[i], ids[i], j, j, role, j, i)
+		}
+		if err := os.WriteFile(filepath.Join(projectDir, ids[i]+".jsonl"), []byte(lines.String()), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return ids
+}
+
+// TestFetchRecentMessagesForSessions tests that the batched previews match the
+// previews loaded one session at a time
+func TestFetchRecentMessagesForSessions(t *testing.T) {
+	if _, err := db.GetDB(); err != nil {
+		t.Skipf("Skipping test, database unavailable: %v", err)
+	}
+	ids := writePreviewSessions(t, 3)
+
+	previews, err := FetchRecentMessagesForSessions(context.Background(), append(ids, "missing"))
+	if err != nil {
+		t.Fatalf("FetchRecentMessagesForSessions failed: %v", err)
+	}
+	if len(previews) != len(ids) {
+		t.Errorf("got previews of %d sessions, want %d", len(previews), len(ids))
+	}
+	for _, id := range ids {
+		want, err := FetchRecentMessagesForSession(id)
+		if err != nil {
+			t.Fatalf("FetchRecentMessagesForSession failed: %v", err)
+		}
+		if !reflect.DeepEqual(previews[id], want) {
+			t.Errorf("session %s: got %q, want %q", id, previews[id], want)
+		}
+	}
+}
+
+// BenchmarkRecentMessages compares loading the previews of a 100-session project
+// one session at a time against loading them in a single query
+func BenchmarkRecentMessages(b *testing.B) {
+	if _, err := db.GetDB(); err != nil {
+		b.Skipf("Skipping benchmark, database unavailable: %v", err)
+	}
+	ids := writePreviewSessions(b, 100)
+	ctx := context.Background()
+
+	b.Run("PerSession", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, id := range ids {
+				if _, err := FetchRecentMessagesForSessionContext(ctx, id); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("Batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := FetchRecentMessagesForSessions(ctx, ids); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package sessions
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Claude Code files each session under a directory of the projects directory
+// named after the project path. Events recorded without a cwd are attributed to
+// the project the directory name decodes to, rather than to "Unknown".
+
+var (
+	decodedDirsMu sync.Mutex
+	decodedDirs   = map[string]string{} // Directory name to project path, see decodeProjectDir
+)
+
+// decodeProjectDir decodes the name of a project directory back into the project
+// path, or returns "" when the name doesn't encode an absolute path. Claude Code
+// replaces the "/" of the path with "-", and newer versions every other character
+// that is not a letter or digit too, so the decoding is ambiguous: the existing
+// directory the name encodes wins, else every "-" is taken for a "/".
+func decodeProjectDir(name string) string {
+	encoded, ok := strings.CutPrefix(name, "-")
+	if !ok || encoded == "" {
+		return ""
+	}
+
+	decodedDirsMu.Lock()
+	defer decodedDirsMu.Unlock()
+	if path, ok := decodedDirs[name]; ok {
+		return path
+	}
+	path := resolveEncodedDir(string(filepath.Separator), encoded)
+	if path == "" {
+		path = "/" + strings.ReplaceAll(encoded, "-", "/")
+	}
+	decodedDirs[name] = path
+	return path
+}
+
+// resolveEncodedDir returns the existing directory below dir whose path relative
+// to dir encodes to encoded, or "" when there is none
+func resolveEncodedDir(dir, encoded string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && entry.Type()&fs.ModeSymlink == 0 {
+			continue
+		}
+		name := entry.Name()
+		for _, candidate := range []string{name, encodeDirName(name)} {
+			rest, ok := strings.CutPrefix(encoded, candidate)
+			if !ok {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			if rest == "" {
+				if info, err := os.Stat(path); err == nil && info.IsDir() {
+					return path
+				}
+				continue
+			}
+			if rest, ok := strings.CutPrefix(rest, "-"); ok {
+				if resolved := resolveEncodedDir(path, rest); resolved != "" {
+					return resolved
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// encodeDirName encodes a path component the way newer versions of Claude Code do
+func encodeDirName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+// projectDirs returns the projects directory searched by globPattern and the
+// project path each of its directories decodes to. Patterns other than the one
+// of listingPlan yield no directories.
+func projectDirs(globPattern string) (string, map[string]string) {
+	root, ok := strings.CutSuffix(globPattern, string(filepath.Separator)+filepath.Join("**", "*.jsonl"))
+	if !ok {
+		return "", nil
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return "", nil
+	}
+	dirs := make(map[string]string)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if path := decodeProjectDir(entry.Name()); path != "" {
+			dirs[entry.Name()] = path
+		}
+	}
+	return root, dirs
+}
+
+// projectDirNameColumn returns the expression extracting the name of the project
+// directory from the session file named by column, a file below root
+func projectDirNameColumn(root, column string) string {
+	return fmt.Sprintf("split_part(substr(%s, %d), '/', 1)", column, utf8.RuneCountInString(root)+2)
+}
+
+// projectDirColumn returns the expression decoding the project directory of the
+// session file named by column into its project path, or NULL when it decodes
+// to none
+func projectDirColumn(globPattern, column string) string {
+	root, dirs := projectDirs(globPattern)
+	if len(dirs) == 0 {
+		return "NULL"
+	}
+	names := make([]string, 0, len(dirs))
+	for name := range dirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CASE %s", projectDirNameColumn(root, column))
+	for _, name := range names {
+		fmt.Fprintf(&b, " WHEN %s THEN %s", quoteLiteral(name), quoteLiteral(dirs[name]))
+	}
+	b.WriteString(" END")
+	return b.String()
+}
+
+// projectDirCondition returns the condition selecting the session files, named by
+// column, whose project directory decodes to projectPath. The "Unknown" project
+// matches the files whose directory decodes to no path.
+func projectDirCondition(globPattern, column, projectPath string) string {
+	root, dirs := projectDirs(globPattern)
+	var names []string
+	for name, path := range dirs {
+		if projectPath == "Unknown" || path == projectPath {
+			names = append(names, name)
+		}
+	}
+	if projectPath == "Unknown" {
+		if len(names) == 0 {
+			return "true"
+		}
+		sort.Strings(names)
+		return fmt.Sprintf("%s NOT IN %s", projectDirNameColumn(root, column), inList(names))
+	}
+	if len(names) == 0 {
+		return "false"
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("%s IN %s", projectDirNameColumn(root, column), inList(names))
+}
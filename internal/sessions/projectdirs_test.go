@@ -0,0 +1,115 @@
+package sessions
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDecodeProjectDir tests that project directory names of both encodings
+// decode to the existing directory they name, and to a path otherwise
+func TestDecodeProjectDir(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"my-project/src", ".config", "v1.2"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Newer versions of Claude Code replace every character but letters and digits
+	encodeNew := func(path string) string {
+		parts := strings.Split(path, "/")
+		for i, part := range parts {
+			parts[i] = encodeDirName(part)
+		}
+		return strings.Join(parts, "-")
+	}
+	encodeOld := func(path string) string { return strings.ReplaceAll(path, "/", "-") }
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{encodeOld(filepath.Join(root, "my-project", "src")), filepath.Join(root, "my-project", "src")},
+		{encodeNew(filepath.Join(root, "my-project", "src")), filepath.Join(root, "my-project", "src")},
+		{encodeNew(filepath.Join(root, ".config")), filepath.Join(root, ".config")},
+		{encodeOld(filepath.Join(root, ".config")), filepath.Join(root, ".config")},
+		{encodeNew(filepath.Join(root, "v1.2")), filepath.Join(root, "v1.2")},
+		{"-nonexistent-claude-resume-dir", "/nonexistent/claude/resume/dir"},
+		{"project", ""},
+	}
+	for _, tt := range tests {
+		if got := decodeProjectDir(tt.name); got != tt.want {
+			t.Errorf("decodeProjectDir(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestSessionWithoutCwd tests that a session recording no cwd is attributed to
+// the project its directory decodes to rather than to "Unknown"
+func TestSessionWithoutCwd(t *testing.T) {
+	projects := t.TempDir()
+	indexDir := t.TempDir()
+	SetProjectsDir(projects)
+	SetIndexDir(indexDir)
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		SetIndexDir("")
+	})
+
+	database, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("failed to open DuckDB: %v", err)
+	}
+	defer database.Close()
+
+	// The index stands in for the session files, so no DuckDB extension is needed
+	writeAgedFiles(t, projects, 10, "a", "b")
+	a := filepath.Join(projects, "-tmp-project", "a.jsonl")
+	b := filepath.Join(projects, "-tmp-project", "b.jsonl")
+	manifest := indexManifest{ProjectsDir: projects, Built: time.Now(), Files: map[string]indexedFile{
+		a: indexedFileOf(t, a, nil),
+		b: indexedFileOf(t, b, nil),
+	}}
+	writeTestIndex(t, database, indexDir, manifest,
+		"("+quoteLiteral(a)+", 's1', 'u1', NULL, NULL, 'user', NULL, TIMESTAMP '2025-01-01 10:00:00', NULL, NULL)",
+		"("+quoteLiteral(b)+", 's2', 'u2', NULL, NULL, 'user', '/elsewhere', TIMESTAMP '2025-01-02 10:00:00', NULL, NULL)",
+	)
+
+	plan, err := listingPlan()
+	if err != nil {
+		t.Fatalf("listingPlan failed: %v", err)
+	}
+	rows, err := database.Query(recentSessionsQuery(plan, 10))
+	if err != nil {
+		t.Fatalf("recent sessions query failed: %v", err)
+	}
+	var got []string
+	for rows.Next() {
+		var sessionID, projectPath string
+		var lastActivity time.Time
+		var resumed, multipleDirs bool
+		if err := rows.Scan(&sessionID, &projectPath, &lastActivity, &resumed, &multipleDirs); err != nil {
+			t.Fatalf("failed to scan session: %v", err)
+		}
+		got = append(got, sessionID+" "+projectPath)
+	}
+	rows.Close()
+	if want := "s2 /elsewhere,s1 /tmp/project"; strings.Join(got, ",") != want {
+		t.Errorf("recent sessions = %v, want %s", got, want)
+	}
+
+	for projectPath, want := range map[string]int{"/tmp/project": 1, "/elsewhere": 1, "Unknown": 0} {
+		filter, args := projectFilter(plan.glob, projectPath)
+		var count int
+		query := "SELECT COUNT(*) FROM " + eventsSource(plan) + " AS src WHERE " + filter
+		if err := database.QueryRow(query, args...).Scan(&count); err != nil {
+			t.Fatalf("project filter query failed: %v", err)
+		}
+		if count != want {
+			t.Errorf("events of %s = %d, want %d", projectPath, count, want)
+		}
+	}
+}
@@ -0,0 +1,858 @@
+package sessions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// This file holds the SQL shared by the synchronous and asynchronous fetchers,
+// so that both always read the same files with the same query.
+
+// jsonSource returns the events of the session files of plan, see readJSON:
+// every file matched by its glob, or only the newest of them when it is over the
+// scan budget. DuckDB cannot bind parameters to table function arguments, so the
+// pattern is embedded as an escaped string literal. Malformed lines, such as a
+// final line truncated by a crash mid-write, are skipped rather than failing the
+// query.
+func jsonSource(plan *scanPlan) string {
+	files := quoteLiteral(plan.glob)
+	if plan.only != nil {
+		files = listLiteral(plan.only)
+	}
+	return readJSON(files)
+}
+
+// readJSON returns the events of files, a SQL string or list literal, read with
+// the read_json table function and completed by withOptionalColumns
+func readJSON(files string) string {
+	return withOptionalColumns(fmt.Sprintf(`SELECT * FROM read_json(%s,
+			format = 'newline_delimited',
+			union_by_name = true,
+			filename = true,
+			ignore_errors = true
+		)`, files))
+}
+
+// optionalColumns lists the event fields that older versions of Claude Code
+// don't record, see DetectSchemaVersion. When none of the files read records one,
+// union_by_name yields no column for it, and queries naming it would fail to bind.
+var optionalColumns = []string{"cwd", "parentUuid", "leafUuid", "summary", "gitBranch", "isSidechain"}
+
+// withOptionalColumns returns the rows of query as a subquery with a NULL column
+// for each of optionalColumns it lacks. The columns it has keep their type.
+func withOptionalColumns(query string) string {
+	columns := make([]string, len(optionalColumns))
+	for i, column := range optionalColumns {
+		columns[i] = "NULL AS " + column
+	}
+	return fmt.Sprintf("(%s\n\t\tUNION ALL BY NAME SELECT %s WHERE false)", query, strings.Join(columns, ", "))
+}
+
+// eventsSource returns the events of the session files of plan with only the
+// columns kept in the session index, see indexedEventsQuery. When the plan uses
+// an index, indexed files unchanged since the build are read from it and only
+// the others from the session files. Otherwise it reads the session files like
+// jsonSource, projected onto the same columns.
+func eventsSource(plan *scanPlan) string {
+	index := plan.index
+	if index == nil {
+		return "(" + indexedEventsQuery(jsonSource(plan)) + ")"
+	}
+	indexed := fmt.Sprintf("SELECT * FROM read_parquet(%s) WHERE %s", quoteLiteral(index.events), index.condition())
+	if len(index.live) == 0 {
+		return "(" + indexed + ")"
+	}
+	return fmt.Sprintf("(%s\n\t\tUNION ALL %s)", indexed, indexedEventsQuery(readJSON(listLiteral(index.live))))
+}
+
+// indexedEventsQuery builds the query projecting the events of source, read by
+// readJSON, onto the columns kept in the session index. Every column is read from
+// the row as a whole, as text, whatever type read_json inferred for it.
+func indexedEventsQuery(source string) string {
+	return fmt.Sprintf(`
+		SELECT 
+			filename,
+			json_extract_string(event, '$.sessionId') as sessionId,
+			json_extract_string(event, '$.uuid') as uuid,
+			json_extract_string(event, '$.parentUuid') as parentUuid,
+			json_extract_string(event, '$.leafUuid') as leafUuid,
+			json_extract_string(event, '$.type') as type,
+			json_extract_string(event, '$.cwd') as cwd,
+			TRY_CAST(json_extract_string(event, '$.timestamp') AS TIMESTAMP) as timestamp,
+			json_extract_string(event, '$.gitBranch') as gitBranch,
+			json_extract_string(event, '$.summary') as summary,
+			json_extract_string(event, '$.message.id') as messageId,
+			json_extract_string(event, '$.message.content[*].type') as contentTypes,
+			TRY_CAST(json_extract_string(event, '$.isSidechain') AS BOOLEAN) as isSidechain
+		FROM (SELECT filename, to_json(src) as event FROM %s AS src)`, source)
+}
+
+// buildIndexQuery builds the statement writing the session index to target: the
+// rows of the previous index for the reused files, then the events of the
+// refreshed ones. Either list may be empty, but not both.
+func buildIndexQuery(previous, target string, reused, refreshed []string) string {
+	var parts []string
+	if len(reused) > 0 {
+		parts = append(parts, fmt.Sprintf("SELECT * FROM read_parquet(%s) WHERE filename IN %s",
+			quoteLiteral(previous), inList(reused)))
+	}
+	if len(refreshed) > 0 {
+		parts = append(parts, indexedEventsQuery(readJSON(listLiteral(refreshed))))
+	}
+	return fmt.Sprintf("COPY (%s) TO %s (FORMAT parquet)", strings.Join(parts, "\n\t\tUNION ALL "), quoteLiteral(target))
+}
+
+// indexedSessionsQuery builds the query listing the sessions recorded in files
+// according to the session index at path, with the file recording each
+func indexedSessionsQuery(path string, files []string) string {
+	return fmt.Sprintf(`
+		SELECT DISTINCT filename, sessionId
+		FROM read_parquet(%s)
+		WHERE filename IN %s
+		AND sessionId IS NOT NULL
+	`, quoteLiteral(path), inList(files))
+}
+
+// inList quotes values as the parenthesized list of an IN condition
+func inList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quoteLiteral(value)
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
+
+// quoteLiteral quotes s as a SQL string literal, doubling any embedded single quotes
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// projectPathColumn returns the canonical project of a session, aggregated over
+// its events: the first cwd it recorded. A session moving to another directory
+// with cd is still filed by claude under the directory it started in, which is
+// where claude --resume finds it. A session recording no cwd belongs to the
+// project its directory decodes to, see projectDirColumn. Expects cwd with empty
+// values turned into NULL, and the filename column.
+func projectPathColumn(globPattern string) string {
+	return fmt.Sprintf("COALESCE(arg_min(cwd, timestamp) FILTER (WHERE cwd IS NOT NULL), %s, 'Unknown')",
+		projectDirColumn(globPattern, "MIN(filename)"))
+}
+
+// messageKeyColumn identifies the message of an event, so that it is counted
+// once: an assistant message is written as one event per content block, all
+// sharing its id. Other events are messages of their own. Expects the
+// messageId column, see messageColumns.
+const messageKeyColumn = "COALESCE(messageId, CAST(uuid AS VARCHAR))"
+
+// messageColumns extracts from the message of an event the columns counting it,
+// see messageKeyColumn and messageCountCondition. The session index keeps them,
+// see indexedEventsQuery.
+const messageColumns = `json_extract_string(to_json(message), '$.id') as messageId,
+				json_extract_string(to_json(message), '$.content[*].type') as contentTypes`
+
+// multipleDirsColumn tells whether a session recorded more than one cwd, see projectPathColumn
+const multipleDirsColumn = "COUNT(DISTINCT cwd) > 1"
+
+// sessionProjectsSource returns the subquery attributing each session to its
+// canonical project, with the time it was last active and its number of messages
+func sessionProjectsSource(plan *scanPlan) string {
+	return fmt.Sprintf(`(
+			SELECT 
+				CAST(sessionId AS VARCHAR) as session_id,
+				%s as project_path,
+				MAX(timestamp) as last_activity,
+				COUNT(DISTINCT %s) FILTER (WHERE %s) as message_count
+			FROM (
+				SELECT sessionId, NULLIF(cwd, '') as cwd, timestamp, filename, uuid, type, messageId, contentTypes, isSidechain
+				FROM %s
+				WHERE sessionId IS NOT NULL
+			)
+			GROUP BY sessionId
+		)`, projectPathColumn(plan.glob), messageKeyColumn, messageCountCondition(), eventsSource(plan))
+}
+
+// projectsQuery builds the query listing one page of projects with aggregated session
+// statistics and the most recent session. Sessions count towards their canonical
+// project only. The last column holds the total number of projects before paging.
+func projectsQuery(plan *scanPlan, limit, offset int) string {
+	return fmt.Sprintf(`
+		SELECT 
+			project_path,
+			COUNT(*) as session_count,
+			SUM(message_count) as message_count,
+			MAX(last_activity) as last_activity,
+			arg_max(session_id, last_activity) as latest_session_id,
+			COUNT(*) OVER () as total_count
+		FROM %s
+		WHERE %s
+		GROUP BY project_path
+		ORDER BY %s
+		LIMIT %d OFFSET %d
+	`, sessionProjectsSource(plan), projectPathCondition("project_path"), projectsOrderBy(), limit, offset)
+}
+
+// sessionsQuery builds the query listing one page of a project's sessions along with its
+// bind arguments. A session belongs to its canonical project, see projectPathColumn;
+// the "Unknown" project holds the sessions recorded without a cwd. Favorites are
+// listed first, so that they lead the first page rather than only their own. The
+// last column holds the total number of sessions before paging.
+func sessionsQuery(plan *scanPlan, projectPath string, favorites []string, limit, offset int) (string, []interface{}) {
+	// Only the sessions with an event in the project can belong to it
+	cwdFilter, args := projectFilter(plan.glob, projectPath)
+	args = append(args, projectPath)
+
+	// Sessions are on the branch most recently recorded in them
+	gitBranch := "arg_max(git_branch, timestamp) FILTER (WHERE git_branch <> '')"
+	branchFilter := ""
+	if branch := BranchFilter(); branch != "" {
+		branchFilter = "AND " + gitBranch + " = ?"
+		args = append(args, branch)
+	}
+	resumeFilter := ""
+	if SinceLastResume() {
+		resumeFilter = "AND session_id IN (" + sinceLastResumeQuery(plan) + ")"
+	}
+
+	favoritesOrder := ""
+	if len(favorites) > 0 {
+		favoritesOrder = "CASE WHEN session_id IN (" + strings.TrimSuffix(strings.Repeat("?,", len(favorites)), ",") + ") THEN 0 ELSE 1 END,"
+		for _, id := range favorites {
+			args = append(args, id)
+		}
+	}
+
+	source := eventsSource(plan)
+	query := fmt.Sprintf(`
+		WITH events AS (
+			SELECT 
+				CAST(sessionId AS VARCHAR) as session_id,
+				NULLIF(cwd, '') as cwd,
+				filename,
+				parentUuid,
+				timestamp,
+				%s as git_branch,
+				ROW_NUMBER() OVER (PARTITION BY sessionId ORDER BY timestamp ASC) as rn
+			FROM %s AS src
+			WHERE sessionId IS NOT NULL
+			AND sessionId IN (
+				SELECT sessionId
+				FROM %s
+				WHERE sessionId IS NOT NULL
+				AND %s
+			)
+		)
+		SELECT 
+			session_id,
+			MAX(timestamp) as last_activity,
+			MIN(timestamp) as first_activity,
+			COALESCE(BOOL_OR(rn = 1 AND parentUuid IS NOT NULL), false) as is_resumed,
+			%s as git_branch,
+			%s as multiple_dirs,
+			COUNT(*) OVER () as total_count
+		FROM events
+		GROUP BY session_id
+		HAVING %s = ?
+		%s
+		%s
+		ORDER BY %s MAX(timestamp) DESC, session_id
+		LIMIT %d OFFSET %d
+	`, gitBranchColumn("src"), source, source, cwdFilter, gitBranch, multipleDirsColumn, projectPathColumn(plan.glob), branchFilter, resumeFilter, favoritesOrder, limit, offset)
+
+	return query, args
+}
+
+// gitBranchColumn returns the expression reading the git branch of the events of
+// source. Older session files don't record gitBranch, see optionalColumns.
+func gitBranchColumn(source string) string {
+	return fmt.Sprintf("CAST(%s.gitBranch AS VARCHAR)", source)
+}
+
+// projectFilter returns the WHERE condition selecting the events of a project and its bind arguments.
+// Events recorded without a cwd belong to the project their directory decodes to, see
+// projectDirCondition, and to the "Unknown" project when it decodes to none.
+func projectFilter(globPattern, projectPath string) (string, []interface{}) {
+	dirFilter := projectDirCondition(globPattern, "filename", projectPath)
+	if projectPath == "Unknown" {
+		return fmt.Sprintf("((cwd IS NULL OR cwd = '') AND %s)", dirFilter), nil
+	}
+	if dirFilter == "false" {
+		return "cwd = ?", []interface{}{projectPath}
+	}
+	return fmt.Sprintf("(cwd = ? OR (cwd IS NULL OR cwd = '') AND %s)", dirFilter), []interface{}{projectPath}
+}
+
+// countProjectsQuery builds the query counting all projects.
+// It only reads the cwd, sessionId and timestamp columns, not message bodies.
+func countProjectsQuery(plan *scanPlan) string {
+	return fmt.Sprintf(`
+		SELECT COUNT(DISTINCT project_path)
+		FROM %s
+		WHERE %s
+	`, sessionProjectsSource(plan), projectPathCondition("project_path"))
+}
+
+// countTotalsQuery builds the query counting the projects and the sessions across
+// all of them, so that both totals agree
+func countTotalsQuery(plan *scanPlan) string {
+	return fmt.Sprintf(`
+		SELECT COUNT(DISTINCT project_path), COUNT(*)
+		FROM %s
+		WHERE %s
+	`, sessionProjectsSource(plan), projectPathCondition("project_path"))
+}
+
+// countSessionsQuery builds the query counting the sessions of a project along with its bind arguments
+func countSessionsQuery(plan *scanPlan, projectPath string) (string, []interface{}) {
+	return fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM %s
+		WHERE project_path = ?
+	`, sessionProjectsSource(plan)), []interface{}{projectPath}
+}
+
+// recentMessagesQuery builds the query returning the first and last N messages of a session.
+// It binds the session ID followed by N four times.
+func recentMessagesQuery(plan *scanPlan) string {
+	return fmt.Sprintf(`
+		WITH all_messages AS (
+			SELECT 
+				type,
+				message_json,
+				timestamp,
+				ROW_NUMBER() OVER (ORDER BY timestamp ASC) as row_num_asc,
+				ROW_NUMBER() OVER (ORDER BY timestamp DESC) as row_num_desc,
+				COUNT(*) OVER () as total_count
+			FROM %s
+			WHERE CAST(sessionId AS VARCHAR) = ?
+		)
+		SELECT 
+			type,
+			message_json,
+			timestamp,
+			CASE 
+				WHEN row_num_asc <= ? THEN 'first'
+				WHEN row_num_desc <= ? THEN 'last'
+			END as position,
+			total_count
+		FROM all_messages
+		WHERE row_num_asc <= ? OR row_num_desc <= ?
+		ORDER BY timestamp ASC
+	`, messageEventsSource(plan))
+}
+
+// batchRecentMessagesQuery builds the query returning the first and last N messages
+// of each of count sessions, ordered by session. It binds the count session IDs
+// followed by N four times.
+func batchRecentMessagesQuery(plan *scanPlan, count int) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", count), ",")
+	return fmt.Sprintf(`
+		WITH all_messages AS (
+			SELECT 
+				CAST(sessionId AS VARCHAR) as session_id,
+				type,
+				message_json,
+				timestamp,
+				ROW_NUMBER() OVER (PARTITION BY sessionId ORDER BY timestamp ASC) as row_num_asc,
+				ROW_NUMBER() OVER (PARTITION BY sessionId ORDER BY timestamp DESC) as row_num_desc,
+				COUNT(*) OVER (PARTITION BY sessionId) as total_count
+			FROM %s
+			WHERE CAST(sessionId AS VARCHAR) IN (%s)
+		)
+		SELECT 
+			session_id,
+			type,
+			message_json,
+			timestamp,
+			CASE 
+				WHEN row_num_asc <= ? THEN 'first'
+				WHEN row_num_desc <= ? THEN 'last'
+			END as position,
+			total_count
+		FROM all_messages
+		WHERE row_num_asc <= ? OR row_num_desc <= ?
+		ORDER BY session_id, timestamp ASC
+	`, messageEventsSource(plan), placeholders)
+}
+
+// messageCountsQuery builds the query counting the messages of each of count
+// sessions, as FetchSessionDetail does. It binds the count session IDs.
+func messageCountsQuery(plan *scanPlan, count int) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", count), ",")
+	return fmt.Sprintf(`
+		SELECT 
+			session_id,
+			COUNT(DISTINCT %s) FILTER (WHERE %s) as message_count
+		FROM (
+			SELECT CAST(sessionId AS VARCHAR) as session_id, uuid, type, isSidechain, %s
+			FROM %s
+			WHERE CAST(sessionId AS VARCHAR) IN (%s)
+		)
+		GROUP BY session_id
+	`, messageKeyColumn, messageCountCondition(), messageColumns, jsonSource(plan), placeholders)
+}
+
+// messageEventsSource returns the events counted as messages as a subquery with
+// the columns sessionId, type, message_json and timestamp: the user and assistant
+// events of the selected message types, outside sidechains unless they are
+// included, and, when summaries are selected, each
+// summary event, placed in the session and at the time of the event it summarizes.
+func messageEventsSource(plan *scanPlan) string {
+	return messageEventsFrom(jsonSource(plan))
+}
+
+// messageEventsFrom is messageEventsSource over the events of source, a table or subquery
+func messageEventsFrom(source string) string {
+	events := fmt.Sprintf(`
+			SELECT sessionId, type, to_json(message) as message_json, timestamp
+			FROM src
+			WHERE %s
+			AND %s
+			AND message IS NOT NULL`, messageTypeCondition(), sidechainCondition())
+	if messageTypeSelected("summary") {
+		events += `
+			UNION ALL
+			SELECT leaf.sessionId, 'summary', to_json({'content': s.summary}), leaf.timestamp
+			FROM src s
+			JOIN src leaf ON CAST(leaf.uuid AS VARCHAR) = CAST(s.leafUuid AS VARCHAR)
+			WHERE s.type = 'summary'`
+	}
+	return fmt.Sprintf(`(
+			WITH src AS (SELECT * FROM %s)%s
+		)`, source, events)
+}
+
+// sessionDetailQuery builds the query aggregating the facts about a session
+// over source, its events, into a single row, see FetchSessionDetail. The
+// event_count column is 0 when the session has none.
+func sessionDetailQuery(plan *scanPlan, source string) string {
+	return fmt.Sprintf(`
+		WITH detail_events AS (
+			SELECT
+				NULLIF(cwd, '') as cwd,
+				filename,
+				parentUuid,
+				uuid,
+				type,
+				isSidechain,
+				timestamp,
+				%s,
+				%s as git_branch,
+				CASE WHEN type = 'assistant'
+					THEN len(list_filter(json_extract_string(to_json(message), '$.content[*].type'), x -> x = 'tool_use'))
+				END as tool_calls,
+				ROW_NUMBER() OVER (ORDER BY timestamp ASC) as rn
+			FROM %s AS src
+		)
+		SELECT
+			%s as project_path,
+			MIN(timestamp) as created_at,
+			MAX(timestamp) as last_activity,
+			COUNT(DISTINCT %s) FILTER (WHERE %s) as message_count,
+			COALESCE(SUM(tool_calls), 0) as tool_calls,
+			COALESCE(bool_or(rn = 1 AND parentUuid IS NOT NULL), false) as is_resumed,
+			arg_max(git_branch, timestamp) FILTER (WHERE git_branch <> '') as git_branch,
+			COUNT(*) as event_count
+		FROM detail_events
+	`, messageColumns, gitBranchColumn("src"), source, projectPathColumn(plan.glob),
+		messageKeyColumn, messageCountCondition())
+}
+
+// sessionPreviewQuery builds the query returning the detail of a session, see
+// sessionDetailQuery, along with its first and last N messages, see
+// recentMessagesQuery, reading the session files once. Every row repeats the
+// detail; a session without messages has a single row with NULL messages. It
+// binds the session ID followed by N four times, like recentMessagesQuery.
+func sessionPreviewQuery(plan *scanPlan) string {
+	// Summaries are kept for the summary messages, they record no session
+	return fmt.Sprintf(`
+		WITH session_events AS MATERIALIZED (
+			SELECT *
+			FROM %s
+			WHERE CAST(sessionId AS VARCHAR) = ? OR type = 'summary'
+		),
+		detail AS (%s),
+		all_messages AS (
+			SELECT 
+				type,
+				message_json,
+				timestamp,
+				ROW_NUMBER() OVER (ORDER BY timestamp ASC) as row_num_asc,
+				ROW_NUMBER() OVER (ORDER BY timestamp DESC) as row_num_desc,
+				COUNT(*) OVER () as total_count
+			FROM %s
+		),
+		preview AS (
+			SELECT 
+				type,
+				message_json,
+				timestamp,
+				CASE 
+					WHEN row_num_asc <= ? THEN 'first'
+					WHEN row_num_desc <= ? THEN 'last'
+				END as position,
+				total_count
+			FROM all_messages
+			WHERE row_num_asc <= ? OR row_num_desc <= ?
+		)
+		SELECT 
+			detail.*,
+			preview.*
+		FROM detail
+		LEFT JOIN preview ON true
+		ORDER BY preview.timestamp ASC
+	`, jsonSource(plan), sessionDetailQuery(plan, "(SELECT * FROM session_events WHERE sessionId IS NOT NULL)"),
+		messageEventsFrom("session_events"))
+}
+
+// resumedFromQuery builds the query mapping resumed sessions to the session they were
+// resumed from. A resumed session's first event points at the last event of its parent
+// through parentUuid. It binds count session IDs.
+func resumedFromQuery(plan *scanPlan, count int) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", count), ",")
+	return fmt.Sprintf(`
+		WITH %s
+		SELECT DISTINCT session_id, parent_session_id
+		FROM resumes
+	`, resumesCTE(eventsSource(plan), fmt.Sprintf("CAST(sessionId AS VARCHAR) IN (%s)", placeholders)))
+}
+
+// resumesCTE builds the CTEs ending in resumes, which lists each resumed session
+// among the events of source matching where, the session it was resumed from
+// and when, see resumedFromQuery
+func resumesCTE(source, where string) string {
+	return fmt.Sprintf(`first_events AS (
+			SELECT 
+				CAST(sessionId AS VARCHAR) as session_id,
+				CAST(parentUuid AS VARCHAR) as parent_uuid,
+				timestamp,
+				ROW_NUMBER() OVER (PARTITION BY sessionId ORDER BY timestamp ASC) as rn
+			FROM %s
+			WHERE %s
+		),
+		resumes AS (
+			SELECT DISTINCT
+				fe.session_id,
+				CAST(e.sessionId AS VARCHAR) as parent_session_id,
+				fe.timestamp as resumed_at
+			FROM first_events fe
+			JOIN %s e ON CAST(e.uuid AS VARCHAR) = fe.parent_uuid
+			WHERE fe.rn = 1
+			AND fe.parent_uuid IS NOT NULL
+			AND e.sessionId IS NOT NULL
+			AND CAST(e.sessionId AS VARCHAR) <> fe.session_id
+		)`, source, where, source)
+}
+
+// sessionChainQuery builds the query following the resumes of resumedFromQuery
+// from the session bound to its placeholder back to the session it was
+// originally started as, in one recursive query. It returns the sessions of
+// the chain in order, starting with the bound session.
+func sessionChainQuery(plan *scanPlan) string {
+	return fmt.Sprintf(`
+		WITH RECURSIVE %s,
+		parents AS (
+			-- A session is resumed from one session; pick one should the data say otherwise
+			SELECT session_id, MIN(parent_session_id) as parent_session_id
+			FROM resumes
+			GROUP BY session_id
+		),
+		chain AS (
+			SELECT session_id, 0 as depth, [session_id] as seen
+			FROM (SELECT CAST(? AS VARCHAR) as session_id)
+			UNION ALL
+			SELECT p.parent_session_id, c.depth + 1, list_append(c.seen, p.parent_session_id)
+			FROM chain c
+			JOIN parents p ON p.session_id = c.session_id
+			-- Guard against cycles in corrupted data
+			WHERE NOT list_contains(c.seen, p.parent_session_id)
+		)
+		SELECT session_id
+		FROM chain
+		ORDER BY depth
+	`, resumesCTE(eventsSource(plan), "sessionId IS NOT NULL"))
+}
+
+// sinceLastResumeQuery builds the query listing the sessions with activity since
+// they were last resumed. A session is resumed when a session starts that was
+// resumed from it, see resumedFromQuery; the new session counts as resumed at
+// that moment too. The time of each session's last resume is compared with
+// that of its last event. Sessions never resumed are left out.
+func sinceLastResumeQuery(plan *scanPlan) string {
+	source := eventsSource(plan)
+	return fmt.Sprintf(`
+		WITH %s,
+		last_resumes AS (
+			SELECT session_id, MAX(resumed_at) as last_resumed
+			FROM (
+				SELECT session_id, resumed_at FROM resumes
+				UNION ALL
+				SELECT parent_session_id, resumed_at FROM resumes
+			)
+			GROUP BY session_id
+		),
+		last_events AS (
+			SELECT CAST(sessionId AS VARCHAR) as session_id, MAX(timestamp) as last_event
+			FROM %s
+			WHERE sessionId IS NOT NULL
+			GROUP BY CAST(sessionId AS VARCHAR)
+		)
+		SELECT e.session_id
+		FROM last_events e
+		JOIN last_resumes r ON r.session_id = e.session_id
+		WHERE e.last_event > r.last_resumed
+	`, resumesCTE(source, "sessionId IS NOT NULL"), source)
+}
+
+// firstMessagesQuery builds the query returning the first messages of role in
+// each session, oldest first, to find the prompt the session was started with or
+// the assistant's first reply. It binds count session IDs and returns at most
+// limit messages per session. When a session index applies to the pattern, only
+// the files it doesn't cover are read, as the titles of the others are kept in
+// the index.
+func firstMessagesQuery(plan *scanPlan, role string, count, limit int) string {
+	source := jsonSource(plan)
+	if plan.index != nil {
+		source = readJSON(listLiteral(plan.index.live))
+	}
+	return firstMessagesFrom(source, role, count, limit)
+}
+
+// firstMessagesFrom builds the query of firstMessagesQuery over source
+func firstMessagesFrom(source, role string, count, limit int) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", count), ",")
+	return fmt.Sprintf(`
+		WITH role_events AS (
+			SELECT 
+				CAST(sessionId AS VARCHAR) as session_id,
+				to_json(message) as message_json,
+				ROW_NUMBER() OVER (PARTITION BY sessionId ORDER BY timestamp ASC) as rn
+			FROM %s
+			WHERE CAST(sessionId AS VARCHAR) IN (%s)
+			AND type = %s
+		)
+		SELECT session_id, message_json
+		FROM role_events
+		WHERE rn <= %d
+		ORDER BY session_id, rn
+	`, source, placeholders, quoteLiteral(role), limit)
+}
+
+// finalRepliesQuery builds the query returning the last assistant messages of
+// each of count sessions, at most limit per session, newest first. It binds the
+// count session IDs.
+func finalRepliesQuery(plan *scanPlan, count, limit int) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", count), ",")
+	return fmt.Sprintf(`
+		SELECT session_id, message_json
+		FROM (
+			SELECT 
+				CAST(sessionId AS VARCHAR) as session_id,
+				to_json(message) as message_json,
+				timestamp,
+				ROW_NUMBER() OVER (PARTITION BY sessionId ORDER BY timestamp DESC) as rn
+			FROM %s
+			WHERE CAST(sessionId AS VARCHAR) IN (%s)
+			AND type = 'assistant'
+			AND message IS NOT NULL
+		)
+		WHERE rn <= %d
+		ORDER BY session_id, timestamp DESC
+	`, jsonSource(plan), placeholders, limit)
+}
+
+// usageQuery builds the query summing the token usage of each of count sessions
+// by model. It binds the count session IDs.
+func usageQuery(plan *scanPlan, count int) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", count), ",")
+	// Claude Code writes one event per content block, repeating the same usage
+	// on each, so deduplicate by message id before summing
+	return fmt.Sprintf(`
+		WITH assistant_usage AS (
+			SELECT
+				CAST(sessionId AS VARCHAR) as session_id,
+				COALESCE(json_extract_string(to_json(message), '$.id'), CAST(uuid AS VARCHAR)) as message_id,
+				COALESCE(json_extract_string(to_json(message), '$.model'), 'unknown') as model,
+				CAST(json_extract(to_json(message), '$.usage.input_tokens') AS BIGINT) as input_tokens,
+				CAST(json_extract(to_json(message), '$.usage.output_tokens') AS BIGINT) as output_tokens
+			FROM %s
+			WHERE CAST(sessionId AS VARCHAR) IN (%s)
+			AND type = 'assistant'
+			AND message IS NOT NULL
+		),
+		per_message AS (
+			SELECT
+				session_id,
+				message_id,
+				MAX(model) as model,
+				MAX(input_tokens) as input_tokens,
+				MAX(output_tokens) as output_tokens
+			FROM assistant_usage
+			WHERE input_tokens IS NOT NULL OR output_tokens IS NOT NULL
+			GROUP BY session_id, message_id
+		)
+		SELECT
+			session_id,
+			model,
+			SUM(COALESCE(input_tokens, 0)) as input_tokens,
+			SUM(COALESCE(output_tokens, 0)) as output_tokens
+		FROM per_message
+		GROUP BY session_id, model
+	`, jsonSource(plan), placeholders)
+}
+
+// recentSessionsQuery builds the query listing the most recently active sessions across
+// every project. A session is attributed to its canonical project, see projectPathColumn.
+func recentSessionsQuery(plan *scanPlan, limit int) string {
+	return sessionsAcrossProjectsQuery(plan, "true", projectPathCondition(projectPathColumn(plan.glob)), limit)
+}
+
+// sessionsByPrefixQuery builds the query listing up to limit sessions, across all
+// projects, whose ID starts with the bound prefix
+func sessionsByPrefixQuery(plan *scanPlan, limit int) string {
+	return sessionsAcrossProjectsQuery(plan, "starts_with(CAST(sessionId AS VARCHAR), ?)", "true", limit)
+}
+
+// sessionsAcrossProjectsQuery builds the query listing up to limit sessions matching
+// filter across all projects, newest first, each with its canonical project and
+// whether it recorded other directories too. Sessions are kept only if their
+// project satisfies having.
+func sessionsAcrossProjectsQuery(plan *scanPlan, filter, having string, limit int) string {
+	return fmt.Sprintf(`
+		WITH events AS (
+			SELECT 
+				CAST(sessionId AS VARCHAR) as session_id,
+				NULLIF(cwd, '') as cwd,
+				filename,
+				parentUuid,
+				timestamp,
+				ROW_NUMBER() OVER (PARTITION BY sessionId ORDER BY timestamp ASC) as rn
+			FROM %s
+			WHERE sessionId IS NOT NULL
+			AND %s
+		)
+		SELECT 
+			session_id,
+			%s as project_path,
+			MAX(timestamp) as last_activity,
+			COALESCE(BOOL_OR(rn = 1 AND parentUuid IS NOT NULL), false) as is_resumed,
+			%s as multiple_dirs
+		FROM events
+		GROUP BY session_id
+		HAVING %s
+		ORDER BY MAX(timestamp) DESC, session_id
+		LIMIT %d
+	`, eventsSource(plan), filter, projectPathColumn(plan.glob), multipleDirsColumn, having, limit)
+}
+
+// statsQuery builds the query aggregating usage analytics in a single pass over the
+// session files, along with its bind arguments. An empty projectPath covers every
+// project. Token usage is repeated on each content block of an assistant message,
+// so it is deduplicated by message id before summing, and messages are counted once.
+func statsQuery(plan *scanPlan, projectPath string) (string, []interface{}) {
+	cwdFilter, args := "true", []interface{}(nil)
+	if projectPath != "" {
+		cwdFilter, args = projectFilter(plan.glob, projectPath)
+	}
+
+	return fmt.Sprintf(`
+		WITH events AS MATERIALIZED (
+			SELECT 
+				COALESCE(NULLIF(cwd, ''), %s, 'Unknown') as project_path,
+				CAST(sessionId AS VARCHAR) as session_id,
+				type,
+				uuid,
+				isSidechain,
+				TRY_CAST(timestamp AS TIMESTAMP) as ts,
+				%s,
+				CASE WHEN type = 'assistant' AND message IS NOT NULL
+					THEN COALESCE(json_extract_string(to_json(message), '$.id'), CAST(uuid AS VARCHAR))
+				END as message_id,
+				CAST(json_extract(to_json(message), '$.usage.input_tokens') AS BIGINT) as input_tokens,
+				CAST(json_extract(to_json(message), '$.usage.output_tokens') AS BIGINT) as output_tokens
+			FROM %s
+			WHERE sessionId IS NOT NULL
+			AND %s
+		),
+		per_session AS (
+			SELECT session_id, MIN(ts) as first_ts, MAX(ts) as last_ts
+			FROM events
+			GROUP BY session_id
+		),
+		per_message AS (
+			SELECT message_id, MAX(input_tokens) as input_tokens, MAX(output_tokens) as output_tokens
+			FROM events
+			WHERE message_id IS NOT NULL
+			GROUP BY message_id
+		),
+		messages AS (
+			SELECT %s as message_key, arg_min(project_path, ts) as project_path, MIN(ts) as ts
+			FROM events
+			WHERE %s
+			GROUP BY message_key
+		)
+		SELECT 
+			(SELECT COUNT(*) FROM per_session) as session_count,
+			(SELECT COUNT(*) FROM messages) as message_count,
+			(SELECT project_path FROM messages GROUP BY project_path ORDER BY COUNT(*) DESC, project_path LIMIT 1) as most_active_project,
+			(SELECT dayname(ts) FROM messages WHERE ts IS NOT NULL GROUP BY dayname(ts) ORDER BY COUNT(*) DESC, dayname(ts) LIMIT 1) as busiest_day,
+			(SELECT AVG(epoch(last_ts) - epoch(first_ts)) FROM per_session WHERE first_ts IS NOT NULL) as avg_session_seconds,
+			(SELECT SUM(COALESCE(input_tokens, 0) + COALESCE(output_tokens, 0)) FROM per_message) as total_tokens
+	`, projectDirColumn(plan.glob, "filename"), messageColumns, jsonSource(plan), cwdFilter,
+		messageKeyColumn, messageCountCondition()), args
+}
+
+// modelStatsQuery builds the query counting sessions and assistant messages per
+// model, along with its bind arguments. An empty projectPath covers every project.
+// A message written as several events is counted once, see messageKeyColumn.
+// Assistant events that don't record a model are counted as "unknown".
+func modelStatsQuery(plan *scanPlan, projectPath string) (string, []interface{}) {
+	cwdFilter, args := "true", []interface{}(nil)
+	if projectPath != "" {
+		cwdFilter, args = projectFilter(plan.glob, projectPath)
+	}
+
+	return fmt.Sprintf(`
+		SELECT 
+			model_name,
+			COUNT(DISTINCT session_id) as session_count,
+			COUNT(DISTINCT %s) as message_count
+		FROM (
+			SELECT 
+				COALESCE(json_extract_string(to_json(message), '$.model'), 'unknown') as model_name,
+				CAST(sessionId AS VARCHAR) as session_id,
+				uuid,
+				%s
+			FROM %s
+			WHERE sessionId IS NOT NULL
+			AND type = 'assistant'
+			AND %s
+		)
+		GROUP BY model_name
+		ORDER BY message_count DESC, model_name
+	`, messageKeyColumn, messageColumns, jsonSource(plan), cwdFilter), args
+}
+
+// lastUUIDQuery builds the query returning the uuid of a session's most recent event
+func lastUUIDQuery(plan *scanPlan) string {
+	return fmt.Sprintf(`
+		SELECT 
+			CAST(uuid AS VARCHAR) as uuid_str
+		FROM %s
+		WHERE CAST(sessionId AS VARCHAR) = ?
+		AND type <> 'summary'
+		ORDER BY timestamp DESC
+		LIMIT 1
+	`, eventsSource(plan))
+}
+
+// summaryByLeafQuery builds the query returning the summary attached to a leaf uuid
+func summaryByLeafQuery(plan *scanPlan) string {
+	return fmt.Sprintf(`
+		SELECT 
+			summary
+		FROM %s
+		WHERE type = 'summary'
+		AND CAST(leafUuid AS VARCHAR) = ?
+		LIMIT 1
+	`, eventsSource(plan))
+}
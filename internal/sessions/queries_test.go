@@ -0,0 +1,462 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// TestQueriesShareGlob tests that every query reads the files matched by listingPlan
+func TestQueriesShareGlob(t *testing.T) {
+	SetProjectsDir(t.TempDir())
+	t.Cleanup(func() { SetProjectsDir("") })
+
+	plan, err := listingPlan()
+	if err != nil {
+		t.Fatalf("listingPlan failed: %v", err)
+	}
+	source := jsonSource(plan)
+
+	unknownSessions, _ := sessionsQuery(plan, "Unknown", nil, 10, 0)
+	projectSessions, args := sessionsQuery(plan, "/some/project", nil, 10, 0)
+	if len(args) != strings.Count(projectSessions, "?") {
+		t.Errorf("sessions query has %d placeholders but %d args", strings.Count(projectSessions, "?"), len(args))
+	}
+	countSessions, countArgs := countSessionsQuery(plan, "/some/project")
+	if len(countArgs) != strings.Count(countSessions, "?") {
+		t.Errorf("count sessions query has %d placeholders but %d args", strings.Count(countSessions, "?"), len(countArgs))
+	}
+
+	modelStats, modelArgs := modelStatsQuery(plan, "/some/project")
+	if len(modelArgs) != strings.Count(modelStats, "?") {
+		t.Errorf("model stats query has %d placeholders but %d args", strings.Count(modelStats, "?"), len(modelArgs))
+	}
+
+	queries := map[string]string{
+		"projects":           projectsQuery(plan, 10, 0),
+		"unknown sessions":   unknownSessions,
+		"project sessions":   projectSessions,
+		"recent messages":    recentMessagesQuery(plan),
+		"session preview":    sessionPreviewQuery(plan),
+		"batch messages":     batchRecentMessagesQuery(plan, 3),
+		"message counts":     messageCountsQuery(plan, 3),
+		"last uuid":          lastUUIDQuery(plan),
+		"summary by leaf":    summaryByLeafQuery(plan),
+		"count projects":     countProjectsQuery(plan),
+		"count sessions":     countSessions,
+		"count totals":       countTotalsQuery(plan),
+		"model stats":        modelStats,
+		"sessions by prefix": sessionsByPrefixQuery(plan, 10),
+		"first prompts":      firstMessagesQuery(plan, "user", 2, firstPromptCandidates),
+		"first replies":      firstMessagesQuery(plan, "assistant", 2, firstPromptCandidates),
+		"final replies":      finalRepliesQuery(plan, 2, finalReplyCandidates),
+		"since last resume":  sinceLastResumeQuery(plan),
+		"resumed from":       resumedFromQuery(plan, 2),
+		"usage":              usageQuery(plan, 2),
+		"session chain":      sessionChainQuery(plan),
+	}
+	for name, query := range queries {
+		if !strings.Contains(query, source) {
+			t.Errorf("%s query does not read from %s", name, plan.glob)
+		}
+		if strings.Count(query, "read_json(") != strings.Count(query, source) {
+			t.Errorf("%s query reads from a source other than the shared glob", name)
+		}
+	}
+}
+
+// TestOptionalColumns tests that fields no session file records read as NULL,
+// while recorded ones keep their value and type
+func TestOptionalColumns(t *testing.T) {
+	database, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("failed to open DuckDB: %v", err)
+	}
+	defer database.Close()
+
+	source := withOptionalColumns("SELECT 's1' AS sessionId, uuid() AS parentUuid, '/p' AS cwd")
+	var sessionID, cwd string
+	var parentUUID, leafUUID, branch sql.NullString
+	query := "SELECT sessionId, CAST(parentUuid AS VARCHAR), cwd, leafUuid, " + gitBranchColumn("src") + " FROM " + source + " AS src"
+	if err := database.QueryRow(query).Scan(&sessionID, &parentUUID, &cwd, &leafUUID, &branch); err != nil {
+		t.Fatalf("query over missing columns failed: %v", err)
+	}
+	if sessionID != "s1" || !parentUUID.Valid || cwd != "/p" || leafUUID.Valid || branch.Valid {
+		t.Errorf("got %s %v %s %v %v, want the recorded columns and NULL for the others", sessionID, parentUUID, cwd, leafUUID, branch)
+	}
+}
+
+// TestEventsSourceColumns tests that without a session index the events source
+// still has the columns kept in one, which the listings select
+func TestEventsSourceColumns(t *testing.T) {
+	source := eventsSource(globPlan("/projects/**/*.jsonl"))
+	for _, column := range []string{"messageId", "contentTypes", "isSidechain"} {
+		if !strings.Contains(source, " as "+column) {
+			t.Errorf("events source has no %s column: %s", column, source)
+		}
+	}
+}
+
+// TestSidechains tests that sidechain events are left out of messages unless
+// included, also from files recording no isSidechain at all
+func TestSidechains(t *testing.T) {
+	t.Cleanup(func() { SetIncludeSidechains(false) })
+
+	database, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("failed to open DuckDB: %v", err)
+	}
+	defer database.Close()
+
+	count := func(events string) int {
+		t.Helper()
+		var n int
+		query := "SELECT COUNT(*) FROM " + withOptionalColumns(events) + " AS src WHERE " + sidechainCondition()
+		if err := database.QueryRow(query).Scan(&n); err != nil {
+			t.Fatalf("sidechain query failed: %v", err)
+		}
+		return n
+	}
+	recorded := "SELECT * FROM (VALUES ('a', true), ('b', false), ('c', NULL)) AS events(uuid, isSidechain)"
+	unrecorded := "SELECT * FROM (VALUES ('a'), ('b')) AS events(uuid)"
+
+	if n := count(recorded); n != 2 {
+		t.Errorf("got %d events, want the 2 outside sidechains", n)
+	}
+	if n := count(unrecorded); n != 2 {
+		t.Errorf("got %d events of a file without isSidechain, want all 2", n)
+	}
+	if !strings.Contains(messageEventsSource(globPlan("/g")), sidechainCondition()) || MessageTypesKey() != "" {
+		t.Error("messages should leave out sidechains by default")
+	}
+
+	SetIncludeSidechains(true)
+	if n := count(recorded); n != 3 {
+		t.Errorf("got %d events, want all 3 with sidechains included", n)
+	}
+	if MessageTypesKey() != "+sidechains" {
+		t.Errorf("key = %q, want cached previews told apart", MessageTypesKey())
+	}
+}
+
+// TestMessageCount tests that a message split over several events is counted
+// once, that tool results sent back as user events and sidechains are not
+// counted, and that the message types decide which events are
+func TestMessageCount(t *testing.T) {
+	t.Cleanup(func() { SetMessageTypes(DefaultMessageTypes) })
+
+	database, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("failed to open DuckDB: %v", err)
+	}
+	defer database.Close()
+
+	events := `SELECT * FROM (VALUES
+		('u1', 'user', NULL, NULL, NULL),
+		('u2', 'assistant', 'm1', ['text'], false),
+		('u3', 'assistant', 'm1', ['tool_use'], false),
+		('u4', 'user', NULL, ['tool_result'], false),
+		('u5', 'user', NULL, ['tool_result', 'text'], false),
+		('u6', 'system', NULL, NULL, false),
+		('u7', 'assistant', 'm2', ['tool_use'], false),
+		('u8', 'user', NULL, NULL, true)
+	) AS events(uuid, type, messageId, contentTypes, isSidechain)`
+
+	tests := []struct {
+		types []string
+		want  int
+	}{
+		{DefaultMessageTypes, 4},
+		{[]string{"assistant"}, 1},
+		{[]string{"tool_use"}, 2},
+		{[]string{"user", "tool_result"}, 2},
+		{[]string{"summary"}, 0},
+	}
+	for _, tt := range tests {
+		if err := SetMessageTypes(tt.types); err != nil {
+			t.Fatalf("SetMessageTypes(%v) failed: %v", tt.types, err)
+		}
+		query := fmt.Sprintf("SELECT COUNT(DISTINCT %s) FILTER (WHERE %s) FROM (%s)", messageKeyColumn, messageCountCondition(), events)
+		var n int
+		if err := database.QueryRow(query).Scan(&n); err != nil {
+			t.Fatalf("message count query failed: %v", err)
+		}
+		if n != tt.want {
+			t.Errorf("%v: got %d messages, want %d", tt.types, n, tt.want)
+		}
+	}
+}
+
+// TestProjectPathCondition tests the SQL conditions built from project filters
+func TestProjectPathCondition(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { SetProjectFilter("") })
+
+	tests := map[string]string{
+		"":                 "true",
+		"/work/*":          `cwd LIKE '/work/%' ESCAPE '\'`,
+		"~/src/a?c":        `cwd LIKE '` + home + `/src/a_c' ESCAPE '\'`,
+		"/work/50%_done*":  `cwd LIKE '/work/50\%\_done%' ESCAPE '\'`,
+		"/o'brien/**":      `cwd LIKE '/o''brien/%%' ESCAPE '\'`,
+		"re:^/work/(a|b)$": `regexp_matches(cwd, '^/work/(a|b)$')`,
+	}
+	for pattern, want := range tests {
+		if err := SetProjectFilter(pattern); err != nil {
+			t.Fatalf("SetProjectFilter(%q) failed: %v", pattern, err)
+		}
+		if got := projectPathCondition("cwd"); got != want {
+			t.Errorf("SetProjectFilter(%q): got %s, want %s", pattern, got, want)
+		}
+	}
+
+	if err := SetProjectFilter("re:(unclosed"); err == nil || !strings.Contains(err.Error(), "invalid project filter regex") {
+		t.Errorf("expected an invalid regex error, got %v", err)
+	}
+}
+
+// TestMessageTypes tests that the message types decide the event types queried
+// and the content previewed, and that unknown types are rejected
+func TestMessageTypes(t *testing.T) {
+	t.Cleanup(func() { SetMessageTypes(DefaultMessageTypes) })
+
+	tests := []struct {
+		types     []string
+		condition string
+		summaries bool
+		key       string
+	}{
+		{DefaultMessageTypes, "type IN ('user', 'assistant')", false, ""},
+		{[]string{"assistant", "tool_use"}, "type IN ('assistant')", false, "assistant,tool_use"},
+		{[]string{"summary", "user", "user"}, "type IN ('user')", true, "user,summary"},
+		{[]string{"tool_result"}, "type IN ('user')", false, "tool_result"},
+		{[]string{"user", "tool_use"}, "type IN ('user', 'assistant')", false, "user,tool_use"},
+	}
+	for _, tt := range tests {
+		if err := SetMessageTypes(tt.types); err != nil {
+			t.Fatalf("SetMessageTypes(%v) failed: %v", tt.types, err)
+		}
+		if got := messageTypeCondition(); got != tt.condition {
+			t.Errorf("%v: got condition %s, want %s", tt.types, got, tt.condition)
+		}
+		if got := strings.Contains(messageEventsSource(globPlan("/g")), "s.summary"); got != tt.summaries {
+			t.Errorf("%v: summaries queried %v, want %v", tt.types, got, tt.summaries)
+		}
+		if got := MessageTypesKey(); got != tt.key {
+			t.Errorf("%v: got key %q, want %q", tt.types, got, tt.key)
+		}
+	}
+
+	if err := SetMessageTypes([]string{"tool_use"}); err != nil {
+		t.Fatal(err)
+	}
+	options := previewFormatOptions()
+	if options.SkipToolUse || !options.SkipToolResults || !options.SkipUserText || !options.SkipAssistantText {
+		t.Errorf("expected everything but tool calls to be skipped, got %+v", options)
+	}
+
+	for _, types := range [][]string{{"user", "system"}, {}} {
+		if err := SetMessageTypes(types); err == nil {
+			t.Errorf("SetMessageTypes(%v): expected an error", types)
+		}
+	}
+}
+
+// TestQuoteLiteral tests escaping of paths embedded in queries
+func TestQuoteLiteral(t *testing.T) {
+	tests := map[string]string{
+		"/home/user/.claude":         "'/home/user/.claude'",
+		"/home/o'brien/.claude":      "'/home/o''brien/.claude'",
+		"/home/a b/'; DROP TABLE x;": "'/home/a b/''; DROP TABLE x;'",
+	}
+	for input, want := range tests {
+		if got := quoteLiteral(input); got != want {
+			t.Errorf("quoteLiteral(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// TestQueriesWithQuotedHome tests that queries still run when the home
+// directory contains a single quote and spaces
+func TestQueriesWithQuotedHome(t *testing.T) {
+	if _, err := db.GetDB(); err != nil {
+		t.Skipf("Skipping test, database unavailable: %v", err)
+	}
+
+	home := filepath.Join(t.TempDir(), "o'brien's home")
+	projectsDir := filepath.Join(home, ".claude", "projects", "-tmp-project")
+	if err := os.MkdirAll(projectsDir, 0o755); err != nil {
+		t.Fatalf("failed to create projects dir: %v", err)
+	}
+	event := `{"type":"user","sessionId":"quoted-session","uuid":"u1","cwd":"/tmp/project","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"hello"}}` + "\n"
+	if err := os.WriteFile(filepath.Join(projectsDir, "quoted-session.jsonl"), []byte(event), 0o644); err != nil {
+		t.Fatalf("failed to write session file: %v", err)
+	}
+
+	t.Setenv("HOME", home)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	SetProjectsDir("")
+
+	projects, err := FetchProjectsWithStats()
+	if err != nil {
+		t.Fatalf("FetchProjectsWithStats failed: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Path != "/tmp/project" {
+		t.Fatalf("expected the single project /tmp/project, got %+v", projects)
+	}
+	if count, err := CountProjects(); err != nil || count != 1 {
+		t.Errorf("expected CountProjects to return 1, got %d (%v)", count, err)
+	}
+	if count, err := CountSessions("/tmp/project"); err != nil || count != 1 {
+		t.Errorf("expected CountSessions to return 1, got %d (%v)", count, err)
+	}
+	if projectCount, sessionCount, err := CountTotals(context.Background()); err != nil || projectCount != 1 || sessionCount != 1 {
+		t.Errorf("expected CountTotals to return 1 project and 1 session, got %d and %d (%v)", projectCount, sessionCount, err)
+	}
+
+	sessions, err := FetchSessionsForProject("/tmp/project")
+	if err != nil {
+		t.Fatalf("FetchSessionsForProject failed: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].SessionID != "quoted-session" {
+		t.Errorf("expected session quoted-session, got %+v", sessions)
+	}
+}
+
+// TestPagingQueries tests that limit and offset reach the listing queries
+func TestPagingQueries(t *testing.T) {
+	plan := globPlan("/tmp/projects/**/*.jsonl")
+	if query := projectsQuery(plan, 25, 50); !strings.Contains(query, "LIMIT 25 OFFSET 50") {
+		t.Errorf("projects query does not page: %s", query)
+	}
+	if query, _ := sessionsQuery(plan, "/p", nil, 25, 50); !strings.Contains(query, "LIMIT 25 OFFSET 50") {
+		t.Errorf("sessions query does not page: %s", query)
+	}
+}
+
+// TestFavoritesOrderQuery tests that favorites lead the sessions query's order,
+// bound after the other arguments
+func TestFavoritesOrderQuery(t *testing.T) {
+	plan := globPlan("/tmp/projects/**/*.jsonl")
+	unordered, _ := sessionsQuery(plan, "/p", nil, 10, 0)
+	if strings.Contains(unordered, "CASE WHEN session_id IN") {
+		t.Errorf("sessions query orders by favorites without any: %s", unordered)
+	}
+
+	query, args := sessionsQuery(plan, "/p", []string{"a", "b"}, 10, 0)
+	if !strings.Contains(query, "ORDER BY CASE WHEN session_id IN (?,?) THEN 0 ELSE 1 END, MAX(timestamp) DESC") {
+		t.Errorf("sessions query does not list favorites first: %s", query)
+	}
+	if len(args) != strings.Count(query, "?") || args[len(args)-2] != "a" || args[len(args)-1] != "b" {
+		t.Errorf("sessions query has %d placeholders for args %v", strings.Count(query, "?"), args)
+	}
+}
+
+// TestBranchFilterQuery tests that the branch filter restricts the sessions query
+func TestBranchFilterQuery(t *testing.T) {
+	t.Cleanup(func() { SetBranchFilter("") })
+	plan := globPlan("/tmp/projects/**/*.jsonl")
+
+	// Sessions are always kept only in their canonical project; the branch is an extra condition
+	branchCondition := "AND arg_max(git_branch, timestamp)"
+	unfiltered, _ := sessionsQuery(plan, "/p", nil, 10, 0)
+	if strings.Contains(unfiltered, branchCondition) {
+		t.Errorf("sessions query filters by branch without a filter set: %s", unfiltered)
+	}
+
+	SetBranchFilter("main")
+	query, args := sessionsQuery(plan, "/p", nil, 10, 0)
+	if !strings.Contains(query, branchCondition) {
+		t.Errorf("sessions query does not filter by branch: %s", query)
+	}
+	if len(args) != strings.Count(query, "?") || args[len(args)-1] != "main" {
+		t.Errorf("sessions query has %d placeholders for args %v", strings.Count(query, "?"), args)
+	}
+}
+
+// TestSessionGitBranch tests that sessions carry their most recent branch and can be filtered by it
+func TestSessionGitBranch(t *testing.T) {
+	if _, err := db.GetDB(); err != nil {
+		t.Skipf("Skipping test, database unavailable: %v", err)
+	}
+	t.Cleanup(func() { SetBranchFilter("") })
+
+	writeSessionFixture(t, map[string]string{
+		"-b/s1.jsonl": jsonl(
+			`{"type":"user","sessionId":"s1","uuid":"a1","cwd":"/b","gitBranch":"main","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"hi"}}`,
+			`{"type":"user","sessionId":"s1","uuid":"a2","cwd":"/b","gitBranch":"feature","timestamp":"2025-01-01T00:10:00Z","message":{"role":"user","content":"hi"}}`,
+		),
+		// Written before branches were recorded
+		"-b/s2.jsonl": jsonl(
+			`{"type":"user","sessionId":"s2","uuid":"b1","cwd":"/b","timestamp":"2025-01-02T00:00:00Z","message":{"role":"user","content":"yo"}}`,
+		),
+	})
+
+	sessions, err := FetchSessionsForProject("/b")
+	if err != nil {
+		t.Fatalf("FetchSessionsForProject failed: %v", err)
+	}
+	branches := map[string]string{}
+	for _, session := range sessions {
+		branches[session.SessionID] = session.GitBranch
+	}
+	if branches["s1"] != "feature" || branches["s2"] != "" {
+		t.Errorf("expected s1 on feature and s2 without a branch, got %v", branches)
+	}
+
+	SetBranchFilter("feature")
+	filtered, err := FetchSessionsForProject("/b")
+	if err != nil {
+		t.Fatalf("FetchSessionsForProject failed: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].SessionID != "s1" {
+		t.Errorf("expected only s1 on feature, got %+v", filtered)
+	}
+}
+
+// TestSessionChangingDirectory tests that a session which moved to another
+// directory with cd is listed once, under the directory it started in, and
+// flagged as spanning several directories
+func TestSessionChangingDirectory(t *testing.T) {
+	if _, err := db.GetDB(); err != nil {
+		t.Skipf("Skipping test, database unavailable: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", "cwd_change.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeSessionFixture(t, map[string]string{"-tmp-start/c1.jsonl": string(data)})
+
+	projects, total, err := FetchProjectsPage(10, 0)
+	if err != nil {
+		t.Fatalf("FetchProjectsPage failed: %v", err)
+	}
+	if total != 1 || len(projects) != 1 || projects[0].Path != "/tmp/start" || projects[0].SessionCount != 1 {
+		t.Fatalf("expected the session under the directory it started in, got %+v", projects)
+	}
+
+	sessions, _, err := FetchSessionsPage("/tmp/start", 10, 0)
+	if err != nil {
+		t.Fatalf("FetchSessionsPage failed: %v", err)
+	}
+	if len(sessions) != 1 || !sessions[0].MultipleDirs || sessions[0].ProjectPath != "/tmp/start" {
+		t.Errorf("expected c1 flagged as spanning directories, got %+v", sessions)
+	}
+	if moved, _, err := FetchSessionsPage("/tmp/start/sub", 10, 0); err != nil || len(moved) != 0 {
+		t.Errorf("expected no sessions under the directory moved to, got %+v, %v", moved, err)
+	}
+
+	recent, err := FetchRecentSessionsGlobal(10)
+	if err != nil {
+		t.Fatalf("FetchRecentSessionsGlobal failed: %v", err)
+	}
+	if len(recent) != 1 || recent[0].ProjectPath != "/tmp/start" || !recent[0].MultipleDirs {
+		t.Errorf("expected c1 to resume in /tmp/start, got %+v", recent)
+	}
+}
@@ -0,0 +1,76 @@
+package sessions
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// WriteRawSession writes the untouched .jsonl lines of a session to w, merged
+// across every file the session is split over and ordered by timestamp
+func WriteRawSession(w io.Writer, sessionID string) error {
+	files, err := FetchSessionFiles(sessionID)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	return writeSessionLines(w, files, sessionID)
+}
+
+// rawLine is a line of a session file along with its position for ordering
+type rawLine struct {
+	timestamp time.Time
+	order     int // Position across all files, breaking timestamp ties
+	line      []byte
+}
+
+// writeSessionLines writes the lines of files belonging to sessionID to w in timestamp order.
+// Lines without a timestamp stay right after the line preceding them in their file.
+func writeSessionLines(w io.Writer, files []string, sessionID string) error {
+	var lines []rawLine
+	for _, file := range files {
+		var previous time.Time
+		err := forEachLine(file, func(line []byte) {
+			var event struct {
+				SessionID string `json:"sessionId"`
+				Timestamp string `json:"timestamp"`
+			}
+			if err := json.Unmarshal(line, &event); err != nil || event.SessionID != sessionID {
+				return
+			}
+			if ts, ok := parseTimestamp(event.Timestamp); ok {
+				previous = ts
+			}
+			lines = append(lines, rawLine{timestamp: previous, order: len(lines), line: line})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if !lines[i].timestamp.Equal(lines[j].timestamp) {
+			return lines[i].timestamp.Before(lines[j].timestamp)
+		}
+		return lines[i].order < lines[j].order
+	})
+
+	writer := bufio.NewWriter(w)
+	for _, l := range lines {
+		if _, err := writer.Write(l.line); err != nil {
+			return fmt.Errorf("failed to write session line: %w", err)
+		}
+		if err := writer.WriteByte('\n'); err != nil {
+			return fmt.Errorf("failed to write session line: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write session line: %w", err)
+	}
+	return nil
+}
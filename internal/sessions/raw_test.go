@@ -0,0 +1,43 @@
+package sessions
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteSessionLines tests that a session split across files is written verbatim in timestamp order
+func TestWriteSessionLines(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.jsonl")
+	second := filepath.Join(dir, "b.jsonl")
+	if err := os.WriteFile(first, []byte(`{"type":"user","sessionId":"s1","timestamp":"2025-01-01T10:00:00Z","uuid":"1"}
+{"type":"user","sessionId":"other","timestamp":"2025-01-01T10:00:01Z"}
+{"type":"assistant","sessionId":"s1","timestamp":"2025-01-01T10:00:04Z","uuid":"4"}
+{"type":"system","sessionId":"s1","uuid":"5"}
+{"type":"summary","summary":"x","leafUuid":"4"}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte(`{"type":"assistant","sessionId":"s1","timestamp":"2025-01-01T10:00:02Z","uuid":"2"}
+{"type":"user",  "sessionId":"s1","timestamp":"2025-01-01T10:00:03Z","uuid":"3"}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := writeSessionLines(&out, []string{first, second}, "s1"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	expected := `{"type":"user","sessionId":"s1","timestamp":"2025-01-01T10:00:00Z","uuid":"1"}
+{"type":"assistant","sessionId":"s1","timestamp":"2025-01-01T10:00:02Z","uuid":"2"}
+{"type":"user",  "sessionId":"s1","timestamp":"2025-01-01T10:00:03Z","uuid":"3"}
+{"type":"assistant","sessionId":"s1","timestamp":"2025-01-01T10:00:04Z","uuid":"4"}
+{"type":"system","sessionId":"s1","uuid":"5"}
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,75 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/strrl/claude-resume/internal/db"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// ProjectName returns the display name of a project path
+func ProjectName(projectPath string) string {
+	if projectPath == "Unknown" || projectPath == "" {
+		return "Unknown"
+	}
+	return filepath.Base(projectPath)
+}
+
+// FetchRecentSessionsGlobal fetches the limit most recently active sessions across
+// every project, newest first. Each session's ProjectPath is set to its project.
+// Results are cached until a session file changes.
+func FetchRecentSessionsGlobal(limit int) ([]models.Session, error) {
+	plan, err := listingPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	query := recentSessionsQuery(plan, limit)
+	sessions, err := cached(cacheKey("recent:"+query), func() ([]models.Session, error) {
+		return fetchRecentSessions(plan, query)
+	})
+	return slices.Clone(sessions), err
+}
+
+func fetchRecentSessions(plan *scanPlan, query string, args ...interface{}) ([]models.Session, error) {
+	database, err := db.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := database.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute recent sessions query: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+	var sessionIDs []string
+	for rows.Next() {
+		var session models.Session
+		var lastActivity sql.NullString
+
+		if err := rows.Scan(&session.SessionID, &session.ProjectPath, &lastActivity, &session.IsResumed, &session.MultipleDirs); err != nil {
+			continue
+		}
+
+		session.LastActivity = parseNullTimestamp(lastActivity)
+
+		sessions = append(sessions, session)
+		sessionIDs = append(sessionIDs, session.SessionID)
+	}
+
+	if len(sessionIDs) > 0 {
+		summaries, sources := sessionTitles(context.Background(), sessionIDs, plan, database)
+		for i := range sessions {
+			sessions[i].Summary = summaries[sessions[i].SessionID]
+			sessions[i].SummarySource = sources[sessions[i].SessionID]
+		}
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,53 @@
+package sessions
+
+import (
+	"testing"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+func TestProjectName(t *testing.T) {
+	tests := map[string]string{
+		"/home/user/src/app": "app",
+		"Unknown":            "Unknown",
+		"":                   "Unknown",
+	}
+	for path, want := range tests {
+		if got := ProjectName(path); got != want {
+			t.Errorf("ProjectName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+// TestFetchRecentSessionsGlobal tests that sessions from every project are listed newest first
+func TestFetchRecentSessionsGlobal(t *testing.T) {
+	if _, err := db.GetDB(); err != nil {
+		t.Skipf("Skipping test, database unavailable: %v", err)
+	}
+
+	writeSessionFixture(t, map[string]string{
+		"-tmp-one/a.jsonl": jsonl(
+			`{"type":"user","sessionId":"a","uuid":"a1","parentUuid":null,"cwd":"/tmp/one","timestamp":"2025-01-01T00:00:00Z"}`,
+		),
+		"-tmp-two/b.jsonl": jsonl(
+			`{"type":"user","sessionId":"b","uuid":"b1","parentUuid":null,"cwd":"/tmp/two","timestamp":"2025-01-03T00:00:00Z"}`,
+		),
+		"-tmp-one/c.jsonl": jsonl(
+			`{"type":"user","sessionId":"c","uuid":"c1","parentUuid":"a1","cwd":"/tmp/one","timestamp":"2025-01-02T00:00:00Z"}`,
+		),
+	})
+
+	recent, err := FetchRecentSessionsGlobal(2)
+	if err != nil {
+		t.Fatalf("FetchRecentSessionsGlobal failed: %v", err)
+	}
+	if len(recent) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(recent))
+	}
+	if recent[0].SessionID != "b" || recent[0].ProjectPath != "/tmp/two" {
+		t.Errorf("expected newest session b in /tmp/two, got %+v", recent[0])
+	}
+	if recent[1].SessionID != "c" || recent[1].ProjectPath != "/tmp/one" || !recent[1].IsResumed {
+		t.Errorf("expected resumed session c in /tmp/one, got %+v", recent[1])
+	}
+}
@@ -0,0 +1,88 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// finalReplyCandidates is how many of a session's last assistant messages are
+// searched for its final reply; the ones after it are usually bare tool calls
+const finalReplyCandidates = 10
+
+// FetchFinalReply returns the text of the last assistant message of a session
+// that has any, which usually tells how the session ended. It returns an empty
+// string when none of the session's last assistant messages hold text.
+func FetchFinalReply(ctx context.Context, sessionID string) (string, error) {
+	plan, err := lookupPlan()
+	if err != nil {
+		return "", err
+	}
+	replies, err := fetchFinalReplies(ctx, plan, []string{sessionID})
+	if err != nil {
+		return "", err
+	}
+	return replies[sessionID], nil
+}
+
+// FetchFinalRepliesForSessions returns the final reply of each of sessionIDs,
+// like FetchFinalReply, in a single query. Sessions without one are left out.
+func FetchFinalRepliesForSessions(ctx context.Context, sessionIDs []string) (map[string]string, error) {
+	if len(sessionIDs) == 0 {
+		return make(map[string]string), nil
+	}
+	plan, err := listingPlan()
+	if err != nil {
+		return nil, err
+	}
+	return fetchFinalReplies(ctx, plan, sessionIDs)
+}
+
+// fetchFinalReplies runs finalRepliesQuery for sessionIDs over plan
+func fetchFinalReplies(ctx context.Context, plan *scanPlan, sessionIDs []string) (map[string]string, error) {
+	database, err := db.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, len(sessionIDs))
+	for i, id := range sessionIDs {
+		args[i] = id
+	}
+	rows, err := database.QueryContext(ctx, finalRepliesQuery(plan, len(sessionIDs), finalReplyCandidates), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute final reply query: %w", err)
+	}
+	defer rows.Close()
+
+	// Each session's messages come newest first, so its first one with text is its final reply
+	replies := make(map[string]string)
+	for rows.Next() {
+		var sessionID, messageJSON sql.NullString
+		if err := rows.Scan(&sessionID, &messageJSON); err != nil {
+			continue
+		}
+		if _, found := replies[sessionID.String]; found {
+			continue
+		}
+		if reply, ok := replyText(messageJSON.String); ok {
+			replies[sessionID.String] = reply
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read final replies: %w", err)
+	}
+	return replies, nil
+}
+
+// replyText returns the text of an assistant message, or false when it holds
+// only tool calls
+func replyText(messageStr string) (string, bool) {
+	message, ok := parseMessage("assistant", messageStr)
+	if !ok || message.Content == "" {
+		return "", false
+	}
+	return message.Content, true
+}
@@ -0,0 +1,50 @@
+package sessions
+
+import "testing"
+
+// TestReplyText tests that only assistant messages with text count as a reply
+func TestReplyText(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		ok    bool
+		reply string
+	}{
+		{
+			name:  "text item",
+			json:  `{"role":"assistant","content":[{"type":"text","text":"All tests pass now."}]}`,
+			ok:    true,
+			reply: "All tests pass now.",
+		},
+		{
+			name:  "text alongside a tool call",
+			json:  `{"role":"assistant","content":[{"type":"text","text":"Committing."},{"type":"tool_use","name":"Bash","input":{"command":"git commit"}}]}`,
+			ok:    true,
+			reply: "Committing.",
+		},
+		{
+			name: "tool call only",
+			json: `{"role":"assistant","content":[{"type":"tool_use","name":"Read","input":{"file_path":"main.go"}}]}`,
+		},
+		{
+			name: "empty text",
+			json: `{"role":"assistant","content":[{"type":"text","text":""}]}`,
+		},
+		{
+			name: "invalid json",
+			json: `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, ok := replyText(tt.json)
+			if ok != tt.ok {
+				t.Fatalf("replyText() ok = %v, want %v", ok, tt.ok)
+			}
+			if reply != tt.reply {
+				t.Errorf("replyText() = %q, want %q", reply, tt.reply)
+			}
+		})
+	}
+}
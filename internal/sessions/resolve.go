@@ -0,0 +1,155 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// maxPrefixCandidates bounds how many matching sessions an ambiguous prefix reports
+const maxPrefixCandidates = 10
+
+// AmbiguousPrefixError is returned by ResolveSessionByPrefix when several sessions
+// match the prefix. Candidates holds the most recently active of them.
+type AmbiguousPrefixError struct {
+	Prefix     string
+	Candidates []models.Session
+}
+
+func (e *AmbiguousPrefixError) Error() string {
+	return fmt.Sprintf("session ID prefix '%s' is ambiguous", e.Prefix)
+}
+
+// ResolveSessionByPrefix finds the session, across all projects, whose ID starts
+// with prefix, like a git short hash. It returns an *AmbiguousPrefixError when
+// several sessions match. A label given to a single session, see SetLabel,
+// resolves to that session.
+func ResolveSessionByPrefix(prefix string) (*models.Session, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("session ID prefix must not be empty")
+	}
+	// Labels are best-effort; an unreadable file leaves only session IDs to match
+	if ids, err := labeledSessionIDs(prefix); err == nil && len(ids) == 1 {
+		prefix = ids[0]
+	}
+
+	plan, err := lookupPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	// One more than reported, to tell an ambiguous prefix from a unique one
+	matches, err := fetchRecentSessions(plan, sessionsByPrefixQuery(plan, maxPrefixCandidates+1), prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no session ID starts with '%s'", prefix)
+	case 1:
+		return &matches[0], nil
+	}
+	if len(matches) > maxPrefixCandidates {
+		matches = matches[:maxPrefixCandidates]
+	}
+	return nil, &AmbiguousPrefixError{Prefix: prefix, Candidates: matches}
+}
+
+// AmbiguousProjectError is returned by ResolveProject when several projects match
+// the name: projects in different directories sharing it, or, without an exact
+// match, several projects matching it loosely
+type AmbiguousProjectError struct {
+	Name       string
+	Candidates []models.Project
+}
+
+func (e *AmbiguousProjectError) Error() string {
+	return fmt.Sprintf("project name '%s' is ambiguous", e.Name)
+}
+
+// ResolveProject finds the project called name, or whose path is name, among all
+// projects regardless of the page limit. Without an exact match the name is
+// looked up loosely, see matchProjects. It returns an *AmbiguousProjectError when
+// several projects match; their paths tell them apart.
+func ResolveProject(name string) (*models.Project, error) {
+	return ResolveProjectContext(context.Background(), name)
+}
+
+// ResolveProjectContext is ResolveProject with a listing query that is abandoned once ctx is done
+func ResolveProjectContext(ctx context.Context, name string) (*models.Project, error) {
+	if name == "" {
+		return nil, fmt.Errorf("project name must not be empty")
+	}
+
+	total, err := CountProjects()
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("project '%s' not found", name)
+	}
+	projects, _, err := FetchProjectsPageContext(ctx, total, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+
+	matches := matchProjects(projects, name)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("project '%s' not found", name)
+	case 1:
+		return &matches[0], nil
+	}
+	return nil, &AmbiguousProjectError{Name: name, Candidates: matches}
+}
+
+// matchProjects returns the project whose path is name, or else every project
+// called name. Failing that, it tries ever looser matches, ignoring case: the
+// projects called name, then those whose name or path contains it, then those
+// whose name holds its characters in order, so that "clres" finds claude-resume.
+func matchProjects(projects []models.Project, name string) []models.Project {
+	lower := strings.ToLower(name)
+	tiers := []func(models.Project) bool{
+		func(p models.Project) bool { return p.Name == name },
+		func(p models.Project) bool { return strings.ToLower(p.Name) == lower },
+		func(p models.Project) bool {
+			return strings.Contains(strings.ToLower(p.Name), lower) || strings.Contains(strings.ToLower(p.Path), lower)
+		},
+		func(p models.Project) bool { return isSubsequence(lower, strings.ToLower(p.Name)) },
+	}
+
+	for _, project := range projects {
+		if project.Path == name {
+			return []models.Project{project}
+		}
+	}
+	for _, matches := range tiers {
+		var matched []models.Project
+		for _, project := range projects {
+			if matches(project) {
+				matched = append(matched, project)
+			}
+		}
+		if len(matched) > 0 {
+			return matched
+		}
+	}
+	return nil
+}
+
+// isSubsequence reports whether the characters of sub appear in s in order
+func isSubsequence(sub, s string) bool {
+	rest := []rune(sub)
+	for _, r := range s {
+		if len(rest) == 0 {
+			break
+		}
+		if r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
@@ -0,0 +1,85 @@
+package sessions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/strrl/claude-resume/internal/db"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// TestResolveEmptyPrefix tests that an empty prefix is rejected before querying
+func TestResolveEmptyPrefix(t *testing.T) {
+	if _, err := ResolveSessionByPrefix(""); err == nil {
+		t.Error("expected an error for an empty prefix")
+	}
+}
+
+// TestResolveSessionByPrefix tests unique, ambiguous and unknown prefixes
+func TestResolveSessionByPrefix(t *testing.T) {
+	if _, err := db.GetDB(); err != nil {
+		t.Skipf("Skipping test, database unavailable: %v", err)
+	}
+
+	writeSessionFixture(t, map[string]string{
+		"-tmp-one/a.jsonl": jsonl(`{"type":"user","sessionId":"3f2a91","uuid":"a1","cwd":"/tmp/one","timestamp":"2025-01-01T00:00:00Z"}`),
+		"-tmp-two/b.jsonl": jsonl(`{"type":"user","sessionId":"3f2b07","uuid":"b1","cwd":"/tmp/two","timestamp":"2025-01-02T00:00:00Z"}`),
+	})
+
+	session, err := ResolveSessionByPrefix("3f2a")
+	if err != nil {
+		t.Fatalf("ResolveSessionByPrefix failed: %v", err)
+	}
+	if session.SessionID != "3f2a91" || session.ProjectPath != "/tmp/one" {
+		t.Errorf("expected 3f2a91 in /tmp/one, got %+v", session)
+	}
+
+	_, err = ResolveSessionByPrefix("3f2")
+	var ambiguous *AmbiguousPrefixError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 || ambiguous.Candidates[0].SessionID != "3f2b07" {
+		t.Errorf("expected both sessions as candidates, newest first, got %v", err)
+	}
+
+	if _, err := ResolveSessionByPrefix("ffff"); err == nil || !strings.Contains(err.Error(), "ffff") {
+		t.Errorf("expected an error naming the unknown prefix, got %v", err)
+	}
+}
+
+// TestMatchProjects tests that a path picks one project while a shared name matches all of them
+func TestMatchProjects(t *testing.T) {
+	projects := []models.Project{
+		{Name: "api", Path: "/work/api"},
+		{Name: "web", Path: "/work/web"},
+		{Name: "api", Path: "/forks/api"},
+		{Name: "claude-resume", Path: "/src/claude-resume"},
+		{Name: "API-Gateway", Path: "/work/gateway"},
+		{Name: "webhooks", Path: "/work/webhooks"},
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+	}{
+		{"web", []string{"/work/web"}},
+		{"api", []string{"/work/api", "/forks/api"}},
+		{"/forks/api", []string{"/forks/api"}},
+		{"API", []string{"/work/api", "/forks/api"}},
+		{"Resume", []string{"/src/claude-resume"}},
+		{"gateway", []string{"/work/gateway"}},
+		{"forks", []string{"/forks/api"}},
+		{"hook", []string{"/work/webhooks"}},
+		{"clres", []string{"/src/claude-resume"}},
+		{"we", []string{"/work/web", "/work/webhooks"}},
+		{"docs", nil},
+	}
+	for _, tt := range tests {
+		var paths []string
+		for _, project := range matchProjects(projects, tt.name) {
+			paths = append(paths, project.Path)
+		}
+		if strings.Join(paths, ",") != strings.Join(tt.paths, ",") {
+			t.Errorf("matchProjects(%q) = %v, want %v", tt.name, paths, tt.paths)
+		}
+	}
+}
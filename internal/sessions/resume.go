@@ -0,0 +1,218 @@
+package sessions
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/paths"
+)
+
+// FindClaudeBinary locates the claude executable. A path configured with
+// SetClaudeBinary wins; otherwise PATH is checked first and then common
+// installation locations, falling back to plain "claude" if none exist.
+func FindClaudeBinary() string {
+	if path := getClaudeBinary(); path != "" {
+		return path
+	}
+
+	// Check if claude is in PATH
+	if _, err := exec.LookPath("claude"); err == nil {
+		return "claude"
+	}
+
+	// Check common installation locations
+	possiblePaths := []string{"/usr/local/bin/claude", "/opt/homebrew/bin/claude"}
+	if homeDir, err := paths.HomeDir(); err == nil {
+		possiblePaths = append([]string{filepath.Join(homeDir, ".claude", "local", "claude")}, possiblePaths...)
+	}
+
+	for _, path := range possiblePaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return "claude"
+}
+
+// ResumeArgs returns the arguments passed to claude to resume a session.
+// The --resume flag and session ID always come before any extra arguments.
+func ResumeArgs(sessionID string, extraArgs ...string) []string {
+	return append([]string{"--resume", sessionID}, extraArgs...)
+}
+
+// resumeArgs returns the arguments resuming a session in projectPath according
+// to the resume strategy: --continue in place of --resume <id> when continuing
+// in projectPath picks the session anyway
+func resumeArgs(sessionID, projectPath string, extraArgs []string) []string {
+	if ResumeStrategy() == "continue" && isLatestSessionOf(sessionID, projectPath) {
+		return append([]string{"--continue"}, extraArgs...)
+	}
+	return ResumeArgs(sessionID, extraArgs...)
+}
+
+// isLatestSessionOf reports whether the session was the last one written of
+// those claude files under projectPath, which is the one claude --continue
+// resumes there. Claude names the session files after the session IDs.
+func isLatestSessionOf(sessionID, projectPath string) bool {
+	if projectPath == "" || projectPath == "Unknown" || !ProjectDirExists(projectPath) {
+		return false
+	}
+	claudeDir, err := ProjectsDir()
+	if err != nil {
+		return false
+	}
+	entries, err := os.ReadDir(claudeDir)
+	if err != nil {
+		return false
+	}
+
+	latest := ""
+	var latestModTime time.Time
+	for _, entry := range entries {
+		if !entry.IsDir() || decodeProjectDir(entry.Name()) != projectPath {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(claudeDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if file.IsDir() || filepath.Ext(file.Name()) != ".jsonl" {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil {
+				continue
+			}
+			if latest == "" || info.ModTime().After(latestModTime) {
+				latest = strings.TrimSuffix(file.Name(), ".jsonl")
+				latestModTime = info.ModTime()
+			}
+		}
+	}
+	return latest == sessionID
+}
+
+// ResumeCommandLine returns the shell command equivalent to resuming a session,
+// e.g. "cd '/path/to/project' && claude --resume <id>"
+func ResumeCommandLine(sessionID string, projectPath string, extraArgs ...string) string {
+	parts := []string{shellQuote(FindClaudeBinary())}
+	for _, arg := range resumeArgs(sessionID, projectPath, extraArgs) {
+		parts = append(parts, shellQuote(arg))
+	}
+	command := strings.Join(parts, " ")
+
+	if projectPath != "" && projectPath != "Unknown" {
+		command = fmt.Sprintf("cd %s && %s", shellQuote(projectPath), command)
+	}
+	return command
+}
+
+// shellQuote quotes s for POSIX shells when it contains characters that need it
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !strings.ContainsAny(s, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ProjectDirExists reports whether a recorded project directory still exists.
+// The "Unknown" project has no directory to change to and counts as existing.
+func ProjectDirExists(projectPath string) bool {
+	if projectPath == "" || projectPath == "Unknown" {
+		return true
+	}
+	info, err := os.Stat(projectPath)
+	return err == nil && info.IsDir()
+}
+
+// ExecuteClaudeResume changes to project directory and executes claude --resume.
+// Any extraArgs are appended after the session ID and passed to claude verbatim.
+// If the directory has been moved or removed, claude is started in the current
+// directory with a warning rather than failing. SetResumeStrategy may replace
+// --resume <id> with --continue. With SetTerminal claude is
+// launched in a new terminal window instead, and with SetDryRun or
+// SetPrintCommand the command is only printed.
+func ExecuteClaudeResume(sessionID string, projectPath string, extraArgs ...string) error {
+	if DryRun() {
+		return writeDryRun(os.Stdout, sessionID, projectPath, extraArgs...)
+	}
+	if PrintCommand() {
+		_, err := fmt.Fprintln(os.Stdout, ResumeCommandLine(sessionID, projectPath, extraArgs...))
+		return err
+	}
+
+	if terminal := getTerminalCommand(); len(terminal) > 0 {
+		dir, _ := os.Getwd()
+		if projectPath != "" && projectPath != "Unknown" {
+			if ProjectDirExists(projectPath) {
+				dir = projectPath
+			} else {
+				fmt.Fprintf(os.Stderr, "Warning: project directory %s no longer exists, resuming in %s\n", projectPath, dir)
+			}
+		}
+		args := append([]string{FindClaudeBinary()}, resumeArgs(sessionID, dir, extraArgs)...)
+		return launchInTerminal(terminal, dir, args)
+	}
+
+	// Change to project directory first
+	if projectPath != "" && projectPath != "Unknown" {
+		if err := os.Chdir(projectPath); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to change to project directory %s: %w", projectPath, err)
+			}
+			cwd, _ := os.Getwd()
+			fmt.Fprintf(os.Stderr, "Warning: project directory %s no longer exists, resuming in %s\n", projectPath, cwd)
+		}
+	}
+
+	cmd := exec.Command(FindClaudeBinary(), resumeArgs(sessionID, projectPath, extraArgs)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// writeDryRun describes the claude invocation ExecuteClaudeResume would make:
+// the binary as discovered by FindClaudeBinary, the full argv and the directory
+func writeDryRun(w io.Writer, sessionID string, projectPath string, extraArgs ...string) error {
+	binary := FindClaudeBinary()
+	resolved := binary + " (not found)"
+	if path, err := exec.LookPath(binary); err == nil {
+		resolved = path
+	}
+
+	args := append([]string{binary}, resumeArgs(sessionID, projectPath, extraArgs)...)
+
+	dir := projectPath
+	launchDir := dir
+	if dir == "" || dir == "Unknown" {
+		dir, _ = os.Getwd()
+		launchDir = dir
+	} else if !ProjectDirExists(dir) {
+		launchDir, _ = os.Getwd()
+		dir = fmt.Sprintf("%s (missing, would resume in %s)", dir, launchDir)
+	}
+	if terminal := getTerminalCommand(); len(terminal) > 0 {
+		args = terminalArgv(terminal, launchDir, args)
+	}
+
+	argv := make([]string, len(args))
+	for i, arg := range args {
+		argv[i] = shellQuote(arg)
+	}
+
+	_, err := fmt.Fprintf(w, "Binary: %s\nCommand: %s\nDirectory: %s\n", resolved, strings.Join(argv, " "), dir)
+	return err
+}
@@ -0,0 +1,153 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProjectDirExists(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{dir, true},
+		{filepath.Join(dir, "moved"), false},
+		{"Unknown", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		if got := ProjectDirExists(tt.path); got != tt.want {
+			t.Errorf("ProjectDirExists(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestWriteDryRun tests that a dry run names the resolved binary, the argv and the directory
+func TestWriteDryRun(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "claude")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	SetClaudeBinary(binary)
+	t.Cleanup(func() { SetClaudeBinary("") })
+
+	var out strings.Builder
+	if err := writeDryRun(&out, "abc123", dir, "--model", "opus"); err != nil {
+		t.Fatalf("writeDryRun failed: %v", err)
+	}
+	want := "Binary: " + binary + "\n" +
+		"Command: " + binary + " --resume abc123 --model opus\n" +
+		"Directory: " + dir + "\n"
+	if out.String() != want {
+		t.Errorf("writeDryRun() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	SetClaudeBinary(filepath.Join(dir, "missing"))
+	if err := writeDryRun(&out, "abc123", filepath.Join(dir, "moved")); err != nil {
+		t.Fatalf("writeDryRun failed: %v", err)
+	}
+	for _, part := range []string{"missing (not found)", "moved (missing, would resume in"} {
+		if !strings.Contains(out.String(), part) {
+			t.Errorf("expected %q in:\n%s", part, out.String())
+		}
+	}
+}
+
+// TestResumeArgs tests that --resume <id> comes before the extra arguments,
+// which are passed on unchanged
+func TestResumeArgs(t *testing.T) {
+	tests := []struct {
+		extra []string
+		want  []string
+	}{
+		{nil, []string{"--resume", "s1"}},
+		{[]string{"--model", "opus"}, []string{"--resume", "s1", "--model", "opus"}},
+		{[]string{"-p", "fix the tests in pkg/a b", `say "hi" and 'bye'`},
+			[]string{"--resume", "s1", "-p", "fix the tests in pkg/a b", `say "hi" and 'bye'`}},
+	}
+	for _, tt := range tests {
+		if got := ResumeArgs("s1", tt.extra...); !slices.Equal(got, tt.want) {
+			t.Errorf("ResumeArgs(s1, %q) = %q, want %q", tt.extra, got, tt.want)
+		}
+	}
+}
+
+// TestResumeCommandLine tests that the printed command uses the discovered
+// binary and quotes what the shell would split
+func TestResumeCommandLine(t *testing.T) {
+	SetClaudeBinary("/opt/my tools/claude")
+	t.Cleanup(func() { SetClaudeBinary("") })
+
+	tests := []struct {
+		dir  string
+		args []string
+		want string
+	}{
+		{"/src/app", nil, "cd /src/app && '/opt/my tools/claude' --resume abc123"},
+		{"/src/it's here", []string{"--model", "opus"}, `cd '/src/it'\''s here' && '/opt/my tools/claude' --resume abc123 --model opus`},
+		{"Unknown", nil, "'/opt/my tools/claude' --resume abc123"},
+	}
+	for _, tt := range tests {
+		if got := ResumeCommandLine("abc123", tt.dir, tt.args...); got != tt.want {
+			t.Errorf("ResumeCommandLine(%q) = %s, want %s", tt.dir, got, tt.want)
+		}
+	}
+}
+
+// TestContinueStrategy tests that the continue strategy passes --continue only
+// for the latest session of the project, which is the one claude would pick
+func TestContinueStrategy(t *testing.T) {
+	projects := t.TempDir()
+	project := t.TempDir()
+	SetProjectsDir(projects)
+	t.Cleanup(func() {
+		SetProjectsDir("")
+		SetResumeStrategy("resume")
+	})
+
+	dir := filepath.Join(projects, strings.ReplaceAll(project, "/", "-"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i, id := range []string{"latest", "older"} {
+		path := filepath.Join(dir, id+".jsonl")
+		if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := now.Add(-time.Duration(i) * time.Hour)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := SetResumeStrategy("latest"); err == nil {
+		t.Error("an unknown strategy should be rejected")
+	}
+	if err := SetResumeStrategy("continue"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		sessionID   string
+		projectPath string
+		want        []string
+	}{
+		{"latest", project, []string{"--continue", "--model", "opus"}},
+		{"older", project, []string{"--resume", "older", "--model", "opus"}},
+		{"latest", "Unknown", []string{"--resume", "latest", "--model", "opus"}},
+	}
+	for _, tt := range tests {
+		got := resumeArgs(tt.sessionID, tt.projectPath, []string{"--model", "opus"})
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("resumeArgs(%s, %s) = %v, want %v", tt.sessionID, tt.projectPath, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ScanProgress reports how far the enumeration of the session files has got
+type ScanProgress struct {
+	Dirs      int   // Project directories enumerated
+	TotalDirs int   // Project directories to enumerate
+	Files     int   // Session files found so far
+	Bytes     int64 // Total size of the session files found so far
+}
+
+// Percent returns the share of project directories enumerated, from 0 to 100
+func (p ScanProgress) Percent() float64 {
+	if p.TotalDirs == 0 {
+		return 100
+	}
+	return float64(p.Dirs) * 100 / float64(p.TotalDirs)
+}
+
+// Message describes the enumeration for a loading indicator
+func (p ScanProgress) Message() string {
+	return fmt.Sprintf("Found %d session files (%s)...", p.Files, formatBytes(p.Bytes))
+}
+
+// ReadingMessage describes the query reading the enumerated session files
+func (p ScanProgress) ReadingMessage() string {
+	return fmt.Sprintf("Reading %d session files (%s)...", p.Files, formatBytes(p.Bytes))
+}
+
+// ScanSessionFiles enumerates the session files one project directory at a
+// time, calling report after each directory, and returns the final count. The
+// enumeration is cheap next to reading the files, so that its progress can be
+// shown before the query starts. Unreadable entries are skipped.
+func ScanSessionFiles(ctx context.Context, report func(ScanProgress)) (ScanProgress, error) {
+	claudeDir, err := ProjectsDir()
+	if err != nil {
+		return ScanProgress{}, err
+	}
+	entries, err := os.ReadDir(claudeDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ScanProgress{}, nil
+		}
+		return ScanProgress{}, fmt.Errorf("failed to read %s: %w", claudeDir, err)
+	}
+
+	var progress ScanProgress
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(claudeDir, entry.Name()))
+		} else {
+			progress.add(entry)
+		}
+	}
+	progress.TotalDirs = len(dirs)
+	report(progress)
+
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				progress.add(d)
+			}
+			return nil
+		})
+		progress.Dirs++
+		report(progress)
+	}
+	return progress, nil
+}
+
+// add counts entry when it is a session file
+func (p *ScanProgress) add(entry fs.DirEntry) {
+	if filepath.Ext(entry.Name()) != ".jsonl" {
+		return
+	}
+	info, err := entry.Info()
+	if err != nil {
+		return
+	}
+	p.Files++
+	p.Bytes += info.Size()
+}
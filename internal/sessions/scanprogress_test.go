@@ -0,0 +1,37 @@
+package sessions
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+// TestScanSessionFiles tests that the enumeration reports its progress after
+// each project directory and counts only session files
+func TestScanSessionFiles(t *testing.T) {
+	files := make(map[string]string)
+	for _, name := range []string{"-a/1.jsonl", "-a/2.jsonl", "-b/3.jsonl", "-b/notes.txt", "-c/sub/4.jsonl"} {
+		files[name] = "0123456789"
+	}
+	dir := writeSessionFixture(t, files)
+
+	var reports []ScanProgress
+	got, err := ScanSessionFiles(context.Background(), func(p ScanProgress) { reports = append(reports, p) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (ScanProgress{Dirs: 3, TotalDirs: 3, Files: 4, Bytes: 40}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if len(reports) != 4 {
+		t.Fatalf("got %d reports, want one before and one after each directory", len(reports))
+	}
+	if reports[0].Percent() != 0 || reports[len(reports)-1].Percent() != 100 {
+		t.Errorf("progress went from %.0f%% to %.0f%%, want 0%% to 100%%", reports[0].Percent(), reports[len(reports)-1].Percent())
+	}
+
+	SetProjectsDir(filepath.Join(dir, "missing"))
+	if got, err := ScanSessionFiles(context.Background(), func(ScanProgress) {}); err != nil || got.Files != 0 {
+		t.Errorf("missing directory gave %+v, %v, want no files and no error", got, err)
+	}
+}
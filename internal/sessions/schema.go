@@ -0,0 +1,151 @@
+package sessions
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// schemaSampleLines bounds how many lines DetectSchemaVersion reads from the start of a file
+const schemaSampleLines = 1000
+
+// schemaFields lists the event fields claude-resume reads, with what is lost when
+// a session file records none of them. Claude Code added fields over time, so
+// files written by older versions lack some.
+var schemaFields = []struct {
+	name    string
+	missing string // Consequence of the field missing, empty when it is harmless
+}{
+	{"sessionId", "events are not attributed to any session"},
+	{"uuid", "resumed sessions and summaries cannot be linked to the events they point at"},
+	{"parentUuid", "resume detection is unavailable: no session is shown as resumed"},
+	{"timestamp", "events cannot be ordered and activity times are unknown"},
+	{"type", "no event is recognized as a message"},
+	{"message", "message contents are unavailable"},
+	{"cwd", "sessions are attributed to the project their directory decodes to"},
+	{"gitBranch", "sessions show no git branch and --branch matches none of them"},
+	{"leafUuid", ""}, // Recorded by summary events only, see SchemaInfo.Notes
+	{"summary", ""},
+	{"version", ""},
+	{"isSidechain", ""}, // Recorded by newer versions only
+}
+
+// SchemaInfo describes the fields recorded by the events of a session file, see DetectSchemaVersion
+type SchemaInfo struct {
+	Path      string
+	Events    int            // Events sampled from the start of the file
+	Malformed int            // Sampled lines that are not JSON objects
+	Fields    map[string]int // Number of sampled events recording each field
+	Versions  []string       // Claude Code versions recorded by the sampled events, in order of appearance
+
+	summariesWithoutLeaf int
+}
+
+// Present returns the known fields recorded by at least one sampled event, see schemaFields
+func (s SchemaInfo) Present() []string {
+	var present []string
+	for _, field := range schemaFields {
+		if s.Fields[field.name] > 0 {
+			present = append(present, field.name)
+		}
+	}
+	return present
+}
+
+// Missing returns the known fields no sampled event records
+func (s SchemaInfo) Missing() []string {
+	var missing []string
+	for _, field := range schemaFields {
+		if s.Fields[field.name] == 0 {
+			missing = append(missing, field.name)
+		}
+	}
+	return missing
+}
+
+// Notes describes what claude-resume cannot show for the file because of the
+// fields it lacks, empty when it records all it needs
+func (s SchemaInfo) Notes() []string {
+	if s.Events == 0 {
+		return nil
+	}
+	var notes []string
+	for _, field := range schemaFields {
+		if field.missing != "" && s.Fields[field.name] == 0 {
+			notes = append(notes, fmt.Sprintf("no %s: %s", field.name, field.missing))
+		}
+	}
+	if s.summariesWithoutLeaf > 0 {
+		notes = append(notes, fmt.Sprintf("%d summaries without leafUuid: they cannot be matched to their sessions", s.summariesWithoutLeaf))
+	}
+	return notes
+}
+
+// Version describes the Claude Code versions that wrote the sampled events
+func (s SchemaInfo) Version() string {
+	switch len(s.Versions) {
+	case 0:
+		return "not recorded (written before Claude Code recorded its version)"
+	case 1:
+		return s.Versions[0]
+	}
+	return fmt.Sprintf("%s to %s", s.Versions[0], s.Versions[len(s.Versions)-1])
+}
+
+// DetectSchemaVersion samples the events at the start of a session file and
+// reports which fields they record and the Claude Code versions that wrote
+// them, to tell what the file's schema lets claude-resume show.
+func DetectSchemaVersion(path string) (*SchemaInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	info := &SchemaInfo{Path: path, Fields: make(map[string]int)}
+	seenVersions := make(map[string]bool)
+	reader := bufio.NewReader(f)
+	for lines := 0; lines < schemaSampleLines; {
+		line, err := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			lines++
+			sampleEvent(info, trimmed, seenVersions)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	return info, nil
+}
+
+// sampleEvent adds the fields and version of an event line to info
+func sampleEvent(info *SchemaInfo, line []byte, seenVersions map[string]bool) {
+	var event map[string]json.RawMessage
+	if err := json.Unmarshal(line, &event); err != nil || event == nil {
+		info.Malformed++
+		return
+	}
+	info.Events++
+	for field, value := range event {
+		if string(value) != "null" {
+			info.Fields[field]++
+		}
+	}
+
+	var version, eventType, leafUUID string
+	if json.Unmarshal(event["version"], &version) == nil && version != "" && !seenVersions[version] {
+		seenVersions[version] = true
+		info.Versions = append(info.Versions, version)
+	}
+	json.Unmarshal(event["type"], &eventType)
+	json.Unmarshal(event["leafUuid"], &leafUUID)
+	if eventType == "summary" && leafUUID == "" {
+		info.summariesWithoutLeaf++
+	}
+}
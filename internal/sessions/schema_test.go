@@ -0,0 +1,63 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDetectSchemaVersion tests that the fields and versions of a session file
+// are detected, along with what the fields it lacks cost
+func TestDetectSchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	current := filepath.Join(dir, "current.jsonl")
+	lines := []string{
+		`{"type":"summary","summary":"Fix login","leafUuid":"u2"}`,
+		`{"type":"user","sessionId":"s","uuid":"u1","parentUuid":null,"timestamp":"2025-01-01T00:00:00Z","cwd":"/p","gitBranch":"main","version":"1.0.24","isSidechain":false,"message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","sessionId":"s","uuid":"u2","parentUuid":"u1","timestamp":"2025-01-01T00:00:01Z","cwd":"/p","gitBranch":"main","version":"1.0.30","message":{"role":"assistant","content":"hello"}}`,
+		`{"type":"user","sessionId":"s","uuid":"u3","parentUuid":"u2","timestam`,
+	}
+	if err := os.WriteFile(current, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := DetectSchemaVersion(current)
+	if err != nil {
+		t.Fatalf("DetectSchemaVersion failed: %v", err)
+	}
+	if schema.Events != 3 || schema.Malformed != 1 {
+		t.Errorf("sampled %d events and %d malformed lines, want 3 and 1", schema.Events, schema.Malformed)
+	}
+	if schema.Version() != "1.0.24 to 1.0.30" {
+		t.Errorf("version = %q", schema.Version())
+	}
+	if len(schema.Missing()) != 0 || len(schema.Notes()) != 0 {
+		t.Errorf("missing = %v, notes = %v, want none", schema.Missing(), schema.Notes())
+	}
+
+	old := filepath.Join(dir, "old.jsonl")
+	lines = []string{
+		`{"type":"summary","summary":"Fix login"}`,
+		`{"type":"user","sessionId":"s","uuid":"u1","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"hi"}}`,
+	}
+	if err := os.WriteFile(old, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if schema, err = DetectSchemaVersion(old); err != nil {
+		t.Fatalf("DetectSchemaVersion failed: %v", err)
+	}
+	if want := []string{"parentUuid", "cwd", "gitBranch", "leafUuid", "version", "isSidechain"}; !reflect.DeepEqual(schema.Missing(), want) {
+		t.Errorf("missing = %v, want %v", schema.Missing(), want)
+	}
+	notes := strings.Join(schema.Notes(), "\n")
+	for _, want := range []string{"no parentUuid: resume detection is unavailable", "no cwd", "1 summaries without leafUuid"} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("notes should mention %q:\n%s", want, notes)
+		}
+	}
+	if !strings.HasPrefix(schema.Version(), "not recorded") {
+		t.Errorf("version = %q, want it not recorded", schema.Version())
+	}
+}
@@ -0,0 +1,427 @@
+package sessions
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/strrl/claude-resume/internal/paths"
+)
+
+// Package-wide settings applied by the CLI before any query runs
+var (
+	settingsMu       sync.RWMutex
+	pageLimit        = 100
+	previewCount     = 10
+	sortOrder        = "recent"
+	claudeBinary     string
+	messagesOnly     bool
+	sidechains       bool
+	projectSummaries bool
+	branchFilter     string
+	sinceLastResume  bool
+	dryRun           bool
+	printCommand     bool
+	resumeStrategy   = "resume"
+	projectLike      string // LIKE pattern project paths must match, see SetProjectFilter
+	projectRegex     string // Regular expression project paths must match
+	messageTypes     = DefaultMessageTypes
+)
+
+// SortOrders lists the supported project sort orders
+var SortOrders = []string{"recent", "name", "sessions"}
+
+// SetPageLimit sets the maximum number of projects and sessions returned by listing queries
+func SetPageLimit(limit int) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	if limit > 0 {
+		pageLimit = limit
+	}
+}
+
+// PageLimit returns the maximum number of projects and sessions returned by listing queries
+func PageLimit() int {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return pageLimit
+}
+
+// SetPreviewCount sets how many messages from the start and end of a
+// session are included in message previews
+func SetPreviewCount(count int) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	if count > 0 {
+		previewCount = count
+	}
+}
+
+func getPreviewCount() int {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return previewCount
+}
+
+// SetMessagesOnly sets whether message previews leave out tool calls and tool
+// results, keeping only the text exchanged between user and assistant
+func SetMessagesOnly(only bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	messagesOnly = only
+}
+
+// MessagesOnly reports whether message previews leave out tool calls and tool results
+func MessagesOnly() bool {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return messagesOnly
+}
+
+// SetIncludeSidechains sets whether message previews and conversations include
+// the events of sidechains, such as the conversations of subagents, which Claude
+// Code records in the session with isSidechain set
+func SetIncludeSidechains(include bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	sidechains = include
+}
+
+// IncludeSidechains reports whether message previews and conversations include sidechain events
+func IncludeSidechains() bool {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return sidechains
+}
+
+// sidechainCondition returns the SQL condition selecting the events shown as
+// messages by whether they belong to a sidechain, see SetIncludeSidechains
+func sidechainCondition() string {
+	if IncludeSidechains() {
+		return "true"
+	}
+	return "(isSidechain IS NULL OR isSidechain = false)"
+}
+
+// SetProjectSummaries sets whether project listings include the summary of
+// each project's most recent session, which costs an extra query
+func SetProjectSummaries(enabled bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	projectSummaries = enabled
+}
+
+// ProjectSummaries reports whether project listings include the summary of each project's most recent session
+func ProjectSummaries() bool {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return projectSummaries
+}
+
+// SetBranchFilter restricts session listings to sessions on a git branch, or
+// lifts the restriction when branch is empty
+func SetBranchFilter(branch string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	branchFilter = branch
+}
+
+// BranchFilter returns the git branch session listings are restricted to, empty for all branches
+func BranchFilter() string {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return branchFilter
+}
+
+// SetSinceLastResume restricts session listings to the sessions with activity
+// since they were last resumed, see sinceLastResumeQuery
+func SetSinceLastResume(enabled bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	sinceLastResume = enabled
+}
+
+// SinceLastResume reports whether session listings are restricted to the sessions with activity since their last resume
+func SinceLastResume() bool {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return sinceLastResume
+}
+
+// SetDryRun makes ExecuteClaudeResume print the command it would run instead of running it
+func SetDryRun(enabled bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	dryRun = enabled
+}
+
+// DryRun reports whether resuming only prints the command
+func DryRun() bool {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return dryRun
+}
+
+// ResumeStrategies lists how claude can be told which session to resume, see SetResumeStrategy
+var ResumeStrategies = []string{"resume", "continue"}
+
+// SetResumeStrategy sets how ExecuteClaudeResume tells claude which session to
+// resume: "resume" always passes --resume <id>, while "continue" passes
+// --continue when the session is the most recent one of the directory claude
+// starts in, which is the session --continue picks, and --resume <id> otherwise
+func SetResumeStrategy(strategy string) error {
+	if !slices.Contains(ResumeStrategies, strategy) {
+		return fmt.Errorf("invalid strategy '%s': must be one of %s", strategy, strings.Join(ResumeStrategies, ", "))
+	}
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	resumeStrategy = strategy
+	return nil
+}
+
+// ResumeStrategy returns how claude is told which session to resume, see SetResumeStrategy
+func ResumeStrategy() string {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return resumeStrategy
+}
+
+// SetPrintCommand makes ExecuteClaudeResume print the shell command resuming
+// the session, ready to paste into another terminal, instead of running it
+func SetPrintCommand(enabled bool) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	printCommand = enabled
+}
+
+// PrintCommand reports whether resuming only prints the shell command
+func PrintCommand() bool {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return printCommand
+}
+
+// SetProjectFilter scopes project listings and recent sessions to the projects
+// whose path matches pattern: a glob, where * matches any run of characters
+// including /, or a regular expression prefixed with "re:". A leading ~ stands
+// for the home directory. An empty pattern lifts the scope.
+func SetProjectFilter(pattern string) error {
+	like, regex := "", ""
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		// DuckDB's regexp_matches uses RE2 syntax, like Go's regexp
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid project filter regex '%s': %w", expr, err)
+		}
+		regex = expr
+	} else if pattern != "" {
+		like = globToLike(expandHome(pattern))
+	}
+
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	projectLike, projectRegex = like, regex
+	return nil
+}
+
+// projectPathCondition returns the SQL condition selecting the project paths in
+// column that match the project filter, "true" without one
+func projectPathCondition(column string) string {
+	settingsMu.RLock()
+	like, regex := projectLike, projectRegex
+	settingsMu.RUnlock()
+
+	switch {
+	case regex != "":
+		return fmt.Sprintf("regexp_matches(%s, %s)", column, quoteLiteral(regex))
+	case like != "":
+		return fmt.Sprintf("%s LIKE %s ESCAPE '\\'", column, quoteLiteral(like))
+	default:
+		return "true"
+	}
+}
+
+// globToLike converts a glob to a LIKE pattern: * becomes %, ? becomes _, and
+// LIKE's own wildcards are escaped with a backslash
+func globToLike(glob string) string {
+	var like strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '*':
+			like.WriteRune('%')
+		case '?':
+			like.WriteRune('_')
+		case '%', '_', '\\':
+			like.WriteRune('\\')
+			like.WriteRune(r)
+		default:
+			like.WriteRune(r)
+		}
+	}
+	return like.String()
+}
+
+// expandHome replaces a leading ~ in path with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := paths.HomeDir()
+	if err != nil {
+		return path
+	}
+	return homeDir + path[1:]
+}
+
+// SetSortOrder sets how project listings are ordered: recent, name or sessions
+func SetSortOrder(order string) error {
+	for _, valid := range SortOrders {
+		if order == valid {
+			settingsMu.Lock()
+			sortOrder = order
+			settingsMu.Unlock()
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid sort order '%s': must be one of %s", order, strings.Join(SortOrders, ", "))
+}
+
+// MessageTypes lists the kinds of content that can count as messages: user and
+// assistant events, the tool calls and results inside them, and summaries
+var MessageTypes = []string{"user", "assistant", "tool_use", "tool_result", "summary"}
+
+// DefaultMessageTypes are the message types counted and previewed unless
+// configured otherwise
+var DefaultMessageTypes = []string{"user", "assistant", "tool_use", "tool_result"}
+
+// SetMessageTypes sets which kinds of content are counted as messages and
+// previewed, see MessageTypes. Summaries are previewed at the point in the
+// session they were written, and tool results along with the user events
+// carrying them, but neither is counted.
+func SetMessageTypes(types []string) error {
+	selected := make([]string, 0, len(types))
+	for _, t := range types {
+		if !slices.Contains(MessageTypes, t) {
+			return fmt.Errorf("invalid message type '%s': must be one of %s", t, strings.Join(MessageTypes, ", "))
+		}
+		if !slices.Contains(selected, t) {
+			selected = append(selected, t)
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no message types given: use some of %s", strings.Join(MessageTypes, ", "))
+	}
+
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	messageTypes = selected
+	return nil
+}
+
+// messageTypeSelected reports whether a kind of content counts as a message
+func messageTypeSelected(t string) bool {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return slices.Contains(messageTypes, t)
+}
+
+// MessageTypesKey identifies the selected message types, and whether sidechains
+// are included, in cached previews, which depend on them. It is empty for the
+// defaults.
+func MessageTypesKey() string {
+	var selected []string
+	for _, t := range MessageTypes {
+		if messageTypeSelected(t) {
+			selected = append(selected, t)
+		}
+	}
+	key := strings.Join(selected, ",")
+	if key == strings.Join(DefaultMessageTypes, ",") {
+		key = ""
+	}
+	if IncludeSidechains() {
+		key += "+sidechains"
+	}
+	return key
+}
+
+// messageTypeCondition returns the SQL condition selecting the user and assistant
+// events holding content of the selected message types, "false" when none do.
+// Tool calls are made in assistant events and tool results sent back in user
+// events; which of their content blocks to show is left to the caller.
+func messageTypeCondition() string {
+	var quoted []string
+	if messageTypeSelected("user") || messageTypeSelected("tool_result") {
+		quoted = append(quoted, quoteLiteral("user"))
+	}
+	if messageTypeSelected("assistant") || messageTypeSelected("tool_use") {
+		quoted = append(quoted, quoteLiteral("assistant"))
+	}
+	if len(quoted) == 0 {
+		return "false"
+	}
+	return fmt.Sprintf("type IN (%s)", strings.Join(quoted, ", "))
+}
+
+// onlyBlocksCondition selects the events whose content is made of blocks of
+// kind only, such as the user events carrying tool results back to the
+// assistant. Expects the contentTypes column, see messageColumns.
+func onlyBlocksCondition(kind string) string {
+	return fmt.Sprintf("COALESCE(len(contentTypes) > 0 AND len(list_filter(contentTypes, x -> x <> %s)) = 0, false)",
+		quoteLiteral(kind))
+}
+
+// messageCountCondition returns the SQL condition selecting the events counted
+// as messages, "false" when none are: user events unless they only carry tool
+// results, which nobody typed, and assistant events with content of a selected
+// type, outside sidechains unless they are included. Expects the contentTypes
+// column, see messageColumns.
+func messageCountCondition() string {
+	var conditions []string
+	if messageTypeSelected("user") {
+		conditions = append(conditions, "(type = 'user' AND NOT "+onlyBlocksCondition("tool_result")+")")
+	}
+	switch assistant, toolUse := messageTypeSelected("assistant"), messageTypeSelected("tool_use"); {
+	case assistant && toolUse:
+		conditions = append(conditions, "type = 'assistant'")
+	case assistant:
+		conditions = append(conditions, "(type = 'assistant' AND NOT "+onlyBlocksCondition("tool_use")+")")
+	case toolUse:
+		conditions = append(conditions, "(type = 'assistant' AND COALESCE(list_contains(contentTypes, 'tool_use'), false))")
+	}
+	if len(conditions) == 0 {
+		return "false"
+	}
+	return fmt.Sprintf("((%s) AND %s)", strings.Join(conditions, " OR "), sidechainCondition())
+}
+
+// SetClaudeBinary overrides the auto-detected path of the claude executable
+func SetClaudeBinary(path string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	claudeBinary = path
+}
+
+func getClaudeBinary() string {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return claudeBinary
+}
+
+// projectsOrderBy returns the ORDER BY clause of the projects query for the configured
+// sort order. Sorting happens in SQL so that pages are consistent with each other.
+func projectsOrderBy() string {
+	settingsMu.RLock()
+	order := sortOrder
+	settingsMu.RUnlock()
+
+	switch order {
+	case "name":
+		return "lower(regexp_extract(project_path, '[^/]*$')) ASC, project_path"
+	case "sessions":
+		return "COUNT(*) DESC, MAX(last_activity) DESC, project_path"
+	default:
+		return "MAX(last_activity) DESC, project_path"
+	}
+}
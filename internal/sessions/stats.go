@@ -0,0 +1,104 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// GlobalStats holds usage analytics aggregated over sessions
+type GlobalStats struct {
+	Sessions             int
+	Messages             int           // Messages, counted as in models.SessionDetail
+	MostActiveProject    string        // Project with the most messages, empty when there are none
+	BusiestDay           string        // Day of the week with the most messages, empty when there are none
+	AverageSessionLength time.Duration // Mean time between a session's first and last event
+	TotalTokens          int64         // Input and output tokens recorded on assistant messages
+	Models               []ModelStats  // Per-model breakdown, most used first
+}
+
+// ModelStats holds the usage of one model
+type ModelStats struct {
+	Model    string // Model name, "unknown" for assistant messages that don't record one
+	Sessions int    // Sessions with at least one message from the model
+	Messages int    // Assistant messages from the model
+}
+
+// ComputeGlobalStats aggregates usage analytics across all projects
+func ComputeGlobalStats() (*GlobalStats, error) {
+	return ComputeProjectStats("")
+}
+
+// ComputeProjectStats aggregates usage analytics for one project, or across all
+// projects when projectPath is empty
+func ComputeProjectStats(projectPath string) (*GlobalStats, error) {
+	return ComputeProjectStatsContext(context.Background(), projectPath)
+}
+
+// ComputeProjectStatsContext is ComputeProjectStats with queries that are abandoned once ctx is done
+func ComputeProjectStatsContext(ctx context.Context, projectPath string) (*GlobalStats, error) {
+	plan, err := listingPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	query, args := statsQuery(plan, projectPath)
+
+	var stats GlobalStats
+	var mostActive, busiestDay sql.NullString
+	var avgSeconds sql.NullFloat64
+	var totalTokens sql.NullInt64
+	row, err := db.QueryRowContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	err = row.Scan(
+		&stats.Sessions,
+		&stats.Messages,
+		&mostActive,
+		&busiestDay,
+		&avgSeconds,
+		&totalTokens,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute stats query: %w", err)
+	}
+
+	stats.MostActiveProject = mostActive.String
+	stats.BusiestDay = busiestDay.String
+	stats.AverageSessionLength = time.Duration(avgSeconds.Float64 * float64(time.Second)).Round(time.Second)
+	stats.TotalTokens = totalTokens.Int64
+
+	stats.Models, err = fetchModelStats(ctx, plan, projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// fetchModelStats counts sessions and assistant messages per model
+func fetchModelStats(ctx context.Context, plan *scanPlan, projectPath string) ([]ModelStats, error) {
+	query, args := modelStatsQuery(plan, projectPath)
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute model stats query: %w", err)
+	}
+	defer rows.Close()
+
+	var models []ModelStats
+	for rows.Next() {
+		var ms ModelStats
+		if err := rows.Scan(&ms.Model, &ms.Sessions, &ms.Messages); err != nil {
+			return nil, fmt.Errorf("failed to scan model stats: %w", err)
+		}
+		models = append(models, ms)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read model stats: %w", err)
+	}
+	return models, nil
+}
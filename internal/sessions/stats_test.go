@@ -0,0 +1,66 @@
+package sessions
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// TestComputeProjectStats tests the aggregate statistics over a small fixture
+func TestComputeProjectStats(t *testing.T) {
+	if _, err := db.GetDB(); err != nil {
+		t.Skipf("Skipping test, database unavailable: %v", err)
+	}
+
+	writeSessionFixture(t, map[string]string{
+		"-p/a.jsonl": jsonl(
+			`{"type":"user","sessionId":"a","uuid":"a1","cwd":"/p","timestamp":"2025-01-01T00:00:00Z","message":{"role":"user","content":"hi"}}`,
+			// One assistant message split over two content blocks repeats its usage
+			`{"type":"assistant","sessionId":"a","uuid":"a2","cwd":"/p","timestamp":"2025-01-01T00:10:00Z","message":{"id":"m1","model":"claude-sonnet-4","role":"assistant","usage":{"input_tokens":100,"output_tokens":20}}}`,
+			`{"type":"assistant","sessionId":"a","uuid":"a3","cwd":"/p","timestamp":"2025-01-01T00:20:00Z","message":{"id":"m1","model":"claude-sonnet-4","role":"assistant","usage":{"input_tokens":100,"output_tokens":20}}}`,
+			`{"type":"user","sessionId":"a","uuid":"a4","cwd":"/p","timestamp":"2025-01-01T00:15:00Z","message":{"role":"user","content":"thanks"}}`,
+		),
+		"-q/b.jsonl": jsonl(
+			`{"type":"user","sessionId":"b","uuid":"b1","cwd":"/q","timestamp":"2025-01-02T00:00:00Z","message":{"role":"user","content":"yo"}}`,
+			// A tool result is not a message of its own
+			`{"type":"user","sessionId":"b","uuid":"b3","cwd":"/q","timestamp":"2025-01-02T00:00:00Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"done"}]}}`,
+			// No model recorded
+			`{"type":"assistant","sessionId":"b","uuid":"b2","cwd":"/q","timestamp":"2025-01-02T00:00:00Z","message":{"role":"assistant","content":"ok"}}`,
+		),
+	})
+
+	stats, err := ComputeGlobalStats()
+	if err != nil {
+		t.Fatalf("ComputeGlobalStats failed: %v", err)
+	}
+	if stats.Sessions != 2 || stats.Messages != 5 {
+		t.Errorf("expected 2 sessions and 5 messages, got %d and %d", stats.Sessions, stats.Messages)
+	}
+	if stats.MostActiveProject != "/p" || stats.BusiestDay != "Wednesday" {
+		t.Errorf("expected /p on Wednesday, got %q on %q", stats.MostActiveProject, stats.BusiestDay)
+	}
+	if stats.AverageSessionLength != 10*time.Minute {
+		t.Errorf("expected an average session of 10m, got %v", stats.AverageSessionLength)
+	}
+	if stats.TotalTokens != 120 {
+		t.Errorf("expected 120 tokens, got %d", stats.TotalTokens)
+	}
+
+	wantModels := []ModelStats{
+		{Model: "claude-sonnet-4", Sessions: 1, Messages: 1},
+		{Model: "unknown", Sessions: 1, Messages: 1},
+	}
+	if !reflect.DeepEqual(stats.Models, wantModels) {
+		t.Errorf("expected models %+v, got %+v", wantModels, stats.Models)
+	}
+
+	scoped, err := ComputeProjectStats("/q")
+	if err != nil {
+		t.Fatalf("ComputeProjectStats failed: %v", err)
+	}
+	if scoped.Sessions != 1 || scoped.Messages != 2 || scoped.MostActiveProject != "/q" {
+		t.Errorf("expected stats scoped to /q, got %+v", scoped)
+	}
+}
@@ -0,0 +1,84 @@
+package sessions
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// dirPlaceholder is replaced by the project directory in a terminal command
+const dirPlaceholder = "{dir}"
+
+// terminalPresets are the launch commands of common terminals. The claude
+// command line is appended to them.
+var terminalPresets = map[string]string{
+	"alacritty":      "alacritty --working-directory {dir} -e",
+	"ghostty":        "ghostty --working-directory={dir} -e",
+	"gnome-terminal": "gnome-terminal --working-directory={dir} --",
+	"kitty":          "kitty --directory {dir}",
+	"konsole":        "konsole --workdir {dir} -e",
+	"tmux":           "tmux new-window -c {dir}",
+	"wezterm":        "wezterm start --cwd {dir} --",
+	"wt":             "wt -d {dir}",
+}
+
+// TerminalPresets returns the names of the terminals with a built-in launch command
+func TerminalPresets() []string {
+	names := make([]string, 0, len(terminalPresets))
+	for name := range terminalPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var terminalCommand []string
+
+// SetTerminal makes ExecuteClaudeResume launch claude in a new terminal window
+// instead of in place. terminal is a preset name or a command, such as
+// "wezterm start --cwd {dir} --", to which the claude command line is
+// appended; {dir} is replaced by the project directory. An empty terminal
+// resumes in place.
+func SetTerminal(terminal string) {
+	if preset, ok := terminalPresets[terminal]; ok {
+		terminal = preset
+	}
+
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	terminalCommand = strings.Fields(terminal)
+}
+
+func getTerminalCommand() []string {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return terminalCommand
+}
+
+// terminalArgv returns the command launching claude with args in dir through
+// terminal, or nil when terminal is empty
+func terminalArgv(terminal []string, dir string, args []string) []string {
+	if len(terminal) == 0 {
+		return nil
+	}
+	argv := make([]string, 0, len(terminal)+len(args))
+	for _, field := range terminal {
+		argv = append(argv, strings.ReplaceAll(field, dirPlaceholder, dir))
+	}
+	return append(argv, args...)
+}
+
+// launchInTerminal starts claude with args in dir in a new terminal window and
+// returns without waiting for it
+func launchInTerminal(terminal []string, dir string, args []string) error {
+	argv := terminalArgv(terminal, dir, args)
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Dir = dir
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to launch terminal %s: %w", argv[0], err)
+	}
+	fmt.Fprintf(os.Stderr, "Resuming in a new %s window\n", argv[0])
+	return cmd.Process.Release()
+}
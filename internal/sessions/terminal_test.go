@@ -0,0 +1,60 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTerminalArgv tests that claude's command line is appended to the terminal
+// command with the project directory filled in
+func TestTerminalArgv(t *testing.T) {
+	args := []string{"claude", "--resume", "abc123"}
+
+	if argv := terminalArgv(nil, "/tmp/project", args); argv != nil {
+		t.Errorf("got %v without a terminal, want nil", argv)
+	}
+
+	tests := map[string]string{
+		"wezterm":                        "wezterm start --cwd /tmp/project -- claude --resume abc123",
+		"gnome-terminal":                 "gnome-terminal --working-directory=/tmp/project -- claude --resume abc123",
+		"foot --working-directory={dir}": "foot --working-directory=/tmp/project claude --resume abc123",
+		"xterm -e":                       "xterm -e claude --resume abc123",
+	}
+	for terminal, want := range tests {
+		SetTerminal(terminal)
+		argv := terminalArgv(getTerminalCommand(), "/tmp/project", args)
+		if got := strings.Join(argv, " "); got != want {
+			t.Errorf("terminal %q: got %q, want %q", terminal, got, want)
+		}
+	}
+	SetTerminal("")
+	if len(getTerminalCommand()) != 0 {
+		t.Error("empty terminal should resume in place")
+	}
+}
+
+// TestWriteDryRunTerminal tests that a dry run shows the terminal launch command
+func TestWriteDryRunTerminal(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "claude")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	SetClaudeBinary(binary)
+	SetTerminal("kitty")
+	t.Cleanup(func() {
+		SetClaudeBinary("")
+		SetTerminal("")
+	})
+
+	var out strings.Builder
+	if err := writeDryRun(&out, "abc123", dir); err != nil {
+		t.Fatalf("writeDryRun failed: %v", err)
+	}
+	want := "Command: kitty --directory " + dir + " " + binary + " --resume abc123\n"
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("expected %q in:\n%s", want, out.String())
+	}
+}
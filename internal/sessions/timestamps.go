@@ -0,0 +1,48 @@
+package sessions
+
+import (
+	"database/sql"
+	"time"
+)
+
+// timestampLayouts lists the layouts accepted for event timestamps. Claude Code
+// writes RFC3339 with millisecond precision, while DuckDB renders timestamps it
+// inferred itself with a space separator and an optional short zone offset.
+var timestampLayouts = []string{
+	time.RFC3339Nano, // Also accepts RFC3339 without fractional seconds
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseTimestamp parses an event timestamp in local time. Timestamps without a
+// zone are taken as UTC. It returns false when no known layout matches.
+func parseTimestamp(s string) (time.Time, bool) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Local(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseNullTimestamp parses a nullable event timestamp, returning the zero time
+// when it is missing or unparseable so that it is displayed as unknown
+func parseNullTimestamp(timestamp sql.NullString) time.Time {
+	if !timestamp.Valid {
+		return time.Time{}
+	}
+	t, _ := parseTimestamp(timestamp.String)
+	return t
+}
+
+// sessionDuration returns the time from the first event of a session to its last
+// one, or zero when either is unknown
+func sessionDuration(firstActivity sql.NullString, lastActivity time.Time) time.Duration {
+	first := parseNullTimestamp(firstActivity)
+	if first.IsZero() || lastActivity.IsZero() || lastActivity.Before(first) {
+		return 0
+	}
+	return lastActivity.Sub(first)
+}
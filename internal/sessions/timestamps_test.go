@@ -0,0 +1,56 @@
+package sessions
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// TestParseTimestamp tests the timestamp formats observed in session files and DuckDB output
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2025, 3, 14, 9, 26, 53, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2025-03-14T09:26:53Z", want},
+		{"2025-03-14T09:26:53.589Z", want.Add(589 * time.Millisecond)},
+		{"2025-03-14T09:26:53.589793238Z", want.Add(589793238 * time.Nanosecond)},
+		{"2025-03-14T11:26:53.5+02:00", want.Add(500 * time.Millisecond)},
+		{"2025-03-14T09:26:53.589", want.Add(589 * time.Millisecond)},
+		{"2025-03-14 09:26:53.589", want.Add(589 * time.Millisecond)},
+		{"2025-03-14 09:26:53+00", want},
+		{"2025-03-14 10:26:53.589+01:00", want.Add(589 * time.Millisecond)},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseTimestamp(tt.input)
+		if !ok {
+			t.Errorf("parseTimestamp(%q) failed", tt.input)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseTimestamp(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if got.Location() != time.Local {
+			t.Errorf("parseTimestamp(%q) should return local time, got %v", tt.input, got.Location())
+		}
+	}
+}
+
+// TestParseTimestampInvalid tests that unparseable timestamps yield the zero time instead of now
+func TestParseTimestampInvalid(t *testing.T) {
+	for _, input := range []string{"", "yesterday", "14/03/2025 09:26"} {
+		if got, ok := parseTimestamp(input); ok || !got.IsZero() {
+			t.Errorf("parseTimestamp(%q) = %v, %v; want zero time and false", input, got, ok)
+		}
+	}
+
+	if got := parseNullTimestamp(sql.NullString{}); !got.IsZero() {
+		t.Errorf("expected zero time for NULL, got %v", got)
+	}
+	if got := parseNullTimestamp(sql.NullString{String: "garbage", Valid: true}); !got.IsZero() {
+		t.Errorf("expected zero time for garbage, got %v", got)
+	}
+}
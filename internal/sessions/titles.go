@@ -0,0 +1,163 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// firstPromptCandidates is how many of a session's first user messages are searched
+// for its prompt; the ones before it are usually tool results or command output.
+// As many of its first assistant messages are searched for a reply with text.
+const firstPromptCandidates = 5
+
+// sessionTitles fetches the summaries of sessionIDs like batchFetchSummaries,
+// along with where each comes from. Sessions without a summary are titled with
+// their first prompt instead, and sessions without one either with the first
+// reply of the assistant, so that every session in a list has a human-readable
+// title. The titles of sessions in the session index are taken from it.
+func sessionTitles(ctx context.Context, sessionIDs []string, plan *scanPlan, database *sql.DB) (map[string]string, map[string]models.SummarySource) {
+	summaries := make(map[string]string, len(sessionIDs))
+	sources := make(map[string]models.SummarySource, len(sessionIDs))
+	sessionTitlesByPhase(ctx, sessionIDs, plan, database, func(phase map[string]string, phaseSources map[string]models.SummarySource) bool {
+		for id, title := range phase {
+			summaries[id] = title
+		}
+		for id, source := range phaseSources {
+			sources[id] = source
+		}
+		return true
+	})
+	return summaries, sources
+}
+
+// titlePhases is how many times sessionTitlesByPhase calls send
+const titlePhases = 3
+
+// sessionTitlesByPhase titles sessionIDs like sessionTitles in three phases:
+// summaries and the session index, then first prompts, then first replies.
+// Each phase runs once over every session it still has to title, and send is
+// called with what it found. It stops early if send returns false.
+func sessionTitlesByPhase(ctx context.Context, sessionIDs []string, plan *scanPlan, database *sql.DB, send func(map[string]string, map[string]models.SummarySource) bool) {
+	summaries := batchFetchSummaries(ctx, sessionIDs, plan, database)
+	sources := make(map[string]models.SummarySource, len(sessionIDs))
+	for id, summary := range summaries {
+		if summary != "" {
+			sources[id] = models.ClaudeSummary
+		}
+	}
+
+	var untitled []string
+	for _, id := range sessionIDs {
+		if summaries[id] != "" {
+			continue
+		}
+		if title, source := plan.index.title(id); source != models.NoSummary {
+			summaries[id], sources[id] = title, source
+			continue
+		}
+		untitled = append(untitled, id)
+	}
+	if !send(summaries, sources) {
+		return
+	}
+
+	prompts := batchFetchFirstTitles(ctx, untitled, "user", plan, database)
+	var promptless []string
+	for _, id := range untitled {
+		if _, ok := prompts[id]; !ok {
+			promptless = append(promptless, id)
+		}
+	}
+	if !send(prompts, sourcesOf(prompts, models.FirstPrompt)) {
+		return
+	}
+
+	replies := batchFetchFirstTitles(ctx, promptless, "assistant", plan, database)
+	send(replies, sourcesOf(replies, models.FirstReply))
+}
+
+// sourcesOf maps every session titled in titles to source
+func sourcesOf(titles map[string]string, source models.SummarySource) map[string]models.SummarySource {
+	sources := make(map[string]models.SummarySource, len(titles))
+	for id := range titles {
+		sources[id] = source
+	}
+	return sources
+}
+
+// batchFetchFirstTitles maps each session among sessionIDs to a title made of
+// the first text of role: the prompt the user typed, or the reply of the
+// assistant. Sessions without one are left out.
+func batchFetchFirstTitles(ctx context.Context, sessionIDs []string, role string, plan *scanPlan, database *sql.DB) map[string]string {
+	// The files covered by the session index are not read, see firstMessagesQuery
+	if len(sessionIDs) == 0 || (plan.index != nil && len(plan.index.live) == 0) {
+		return make(map[string]string)
+	}
+	return fetchFirstTitles(ctx, sessionIDs, role, firstMessagesQuery(plan, role, len(sessionIDs), firstPromptCandidates), database)
+}
+
+// fetchFirstTitles runs query, a firstMessagesQuery of role binding sessionIDs,
+// and maps each session to the title of its first message with text
+func fetchFirstTitles(ctx context.Context, sessionIDs []string, role, query string, database *sql.DB) map[string]string {
+	title := promptTitle
+	if role == "assistant" {
+		title = replyTitle
+	}
+
+	titles := make(map[string]string)
+	args := make([]interface{}, len(sessionIDs))
+	for i, id := range sessionIDs {
+		args[i] = id
+	}
+
+	rows, err := database.QueryContext(ctx, query, args...)
+	if err != nil {
+		return titles
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sessionID, messageJSON sql.NullString
+		if err := rows.Scan(&sessionID, &messageJSON); err != nil {
+			continue
+		}
+		if _, ok := titles[sessionID.String]; ok {
+			continue
+		}
+		if text, ok := title(messageJSON.String); ok {
+			titles[sessionID.String] = text
+		}
+	}
+
+	return titles
+}
+
+// promptTitle turns a user message into a one-line title. It returns false for
+// messages the user didn't type: tool results, system reminders and the output
+// of slash commands, which Claude Code wraps in tags such as <command-name>.
+func promptTitle(messageStr string) (string, bool) {
+	message, ok := parseMessage("user", messageStr)
+	if !ok || message.Content == "" || strings.HasPrefix(message.Content, "<") {
+		return "", false
+	}
+	return truncateString(message.Content, previewMaxLength), true
+}
+
+// replyTitle turns an assistant message into a one-line title: its first
+// non-empty line, without Markdown heading or list markers. It returns false
+// for messages without text, such as those only calling tools.
+func replyTitle(messageStr string) (string, bool) {
+	message, ok := parseMessage("assistant", messageStr)
+	if !ok {
+		return "", false
+	}
+	for _, line := range strings.Split(message.Content, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(line, "#*-> ")); line != "" {
+			return truncateString(line, previewMaxLength), true
+		}
+	}
+	return "", false
+}
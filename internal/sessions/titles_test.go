@@ -0,0 +1,119 @@
+package sessions
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestPromptTitle tests that only text the user typed becomes a session title
+func TestPromptTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		ok    bool
+		title string
+	}{
+		{
+			name:  "string content",
+			json:  `{"role":"user","content":"Fix the login bug"}`,
+			ok:    true,
+			title: "Fix the login bug",
+		},
+		{
+			name:  "multi-line prompt",
+			json:  `{"role":"user","content":"Refactor the parser\n\n  so errors are clearer"}`,
+			ok:    true,
+			title: "Refactor the parser so errors are clearer",
+		},
+		{
+			name:  "text item",
+			json:  `{"role":"user","content":[{"type":"text","text":"Add a --json flag"}]}`,
+			ok:    true,
+			title: "Add a --json flag",
+		},
+		{
+			name: "tool result",
+			json: `{"role":"user","content":[{"type":"tool_result","content":"ok"}]}`,
+		},
+		{
+			name: "system reminder",
+			json: `{"role":"user","content":"<system-reminder>Be brief</system-reminder>"}`,
+		},
+		{
+			name: "slash command",
+			json: `{"role":"user","content":"<command-name>/clear</command-name>"}`,
+		},
+		{
+			name: "invalid json",
+			json: `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, ok := promptTitle(tt.json)
+			if ok != tt.ok {
+				t.Fatalf("promptTitle() ok = %v, want %v", ok, tt.ok)
+			}
+			if title != tt.title {
+				t.Errorf("promptTitle() = %q, want %q", title, tt.title)
+			}
+		})
+	}
+}
+
+// TestReplyTitle tests that the first line of text the assistant replied
+// becomes a session title
+func TestReplyTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		ok    bool
+		title string
+	}{
+		{
+			name:  "first line",
+			json:  `{"role":"assistant","content":[{"type":"text","text":"I'll fix the login bug.\n\nFirst, the handler."}]}`,
+			ok:    true,
+			title: "I'll fix the login bug.",
+		},
+		{
+			name:  "markdown heading",
+			json:  `{"role":"assistant","content":[{"type":"text","text":"\n## Plan for the parser"}]}`,
+			ok:    true,
+			title: "Plan for the parser",
+		},
+		{
+			name: "tool call only",
+			json: `{"role":"assistant","content":[{"type":"tool_use","name":"Bash","input":{"command":"ls"}}]}`,
+		},
+		{
+			name: "invalid json",
+			json: `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, ok := replyTitle(tt.json)
+			if ok != tt.ok {
+				t.Fatalf("replyTitle() ok = %v, want %v", ok, tt.ok)
+			}
+			if title != tt.title {
+				t.Errorf("replyTitle() = %q, want %q", title, tt.title)
+			}
+		})
+	}
+}
+
+// TestPromptTitleTruncated tests that long prompts are cut to the preview length
+func TestPromptTitleTruncated(t *testing.T) {
+	title, ok := promptTitle(`{"role":"user","content":"` + strings.Repeat("word ", 100) + `"}`)
+	if !ok {
+		t.Fatal("promptTitle() rejected a long prompt")
+	}
+	if got := utf8.RuneCountInString(title); got != previewMaxLength+len("...") {
+		t.Errorf("title has %d runes, want %d", got, previewMaxLength+len("..."))
+	}
+}
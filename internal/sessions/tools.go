@@ -0,0 +1,52 @@
+package sessions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// FetchToolUsage returns every tool invocation of a session in chronological order
+func FetchToolUsage(sessionID string) ([]models.ToolCall, error) {
+	messages, err := FetchMessages(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []models.ToolCall
+	for _, message := range messages {
+		for _, call := range message.ToolCalls {
+			call.Timestamp = message.Timestamp
+			calls = append(calls, call)
+		}
+	}
+	return calls, nil
+}
+
+// toolInputKeys lists, in order of preference, the input fields that best describe a tool call
+var toolInputKeys = []string{"command", "file_path", "notebook_path", "pattern", "path", "query", "url", "description", "prompt"}
+
+// DescribeToolCall returns a one-line description of what a tool call did,
+// e.g. the command for Bash or the file for Edit
+func DescribeToolCall(call models.ToolCall) string {
+	var input map[string]interface{}
+	if err := json.Unmarshal([]byte(call.Input), &input); err != nil {
+		return ""
+	}
+
+	for _, key := range toolInputKeys {
+		if value, ok := input[key].(string); ok && value != "" {
+			description := strings.Join(strings.Fields(value), " ")
+			// Searches are more useful with their scope
+			if key == "pattern" {
+				if path, ok := input["path"].(string); ok && path != "" {
+					description = fmt.Sprintf("%s in %s", description, path)
+				}
+			}
+			return description
+		}
+	}
+	return ""
+}
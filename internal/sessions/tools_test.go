@@ -0,0 +1,30 @@
+package sessions
+
+import (
+	"testing"
+
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// TestDescribeToolCall tests the one-line descriptions of tool calls
+func TestDescribeToolCall(t *testing.T) {
+	tests := []struct {
+		name string
+		call models.ToolCall
+		want string
+	}{
+		{"bash", models.ToolCall{Name: "Bash", Input: `{"command": "go test\n./..."}`}, "go test ./..."},
+		{"edit", models.ToolCall{Name: "Edit", Input: `{"file_path": "/src/main.go", "old_string": "a"}`}, "/src/main.go"},
+		{"grep", models.ToolCall{Name: "Grep", Input: `{"pattern": "TODO", "path": "internal"}`}, "TODO in internal"},
+		{"no known field", models.ToolCall{Name: "Custom", Input: `{"x": 1}`}, ""},
+		{"invalid input", models.ToolCall{Name: "Bash", Input: ``}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescribeToolCall(tt.call); got != tt.want {
+				t.Errorf("DescribeToolCall() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
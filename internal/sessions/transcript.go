@@ -0,0 +1,164 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/strrl/claude-resume/internal/db"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// FetchMessages fetches every message of a session as structured messages in
+// chronological order, without the first/last truncation used for previews. The
+// message types decide which events, tool calls and tool results are included,
+// see SetMessageTypes. Formatting is left to the caller.
+func FetchMessages(sessionID string) ([]models.Message, error) {
+	return FetchMessagesContext(context.Background(), sessionID)
+}
+
+// FetchMessagesContext is FetchMessages with a query that is abandoned once ctx is done
+func FetchMessagesContext(ctx context.Context, sessionID string) ([]models.Message, error) {
+	plan, err := lookupPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	messagesQuery := fmt.Sprintf(`
+		SELECT
+			type,
+			message_json,
+			timestamp
+		FROM %s
+		WHERE CAST(sessionId AS VARCHAR) = ?
+		ORDER BY timestamp ASC
+	`, messageEventsSource(plan))
+
+	rows, err := db.QueryContext(ctx, messagesQuery, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute messages query: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []models.Message
+	for rows.Next() {
+		var messageType sql.NullString
+		var messageJSON sql.NullString
+		var timestamp sql.NullString
+
+		if err := rows.Scan(&messageType, &messageJSON, &timestamp); err != nil {
+			continue
+		}
+
+		if !messageType.Valid || !messageJSON.Valid || messageJSON.String == "" {
+			continue
+		}
+
+		message, ok := parseMessage(messageType.String, messageJSON.String)
+		if !ok {
+			continue
+		}
+		if !messageTypeSelected(messageType.String) {
+			message.Content = ""
+		}
+		if !messageTypeSelected("tool_use") {
+			message.ToolCalls = nil
+		}
+		if !messageTypeSelected("tool_result") {
+			message.ToolResults = nil
+		}
+		if message.Content == "" && len(message.ToolCalls) == 0 && len(message.ToolResults) == 0 {
+			continue
+		}
+
+		message.Timestamp = parseNullTimestamp(timestamp)
+
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// parseMessage parses the JSON message payload of an event into a structured message.
+// It returns false when the message has no displayable content.
+func parseMessage(messageType, messageStr string) (models.Message, bool) {
+	message := models.Message{Role: messageType}
+
+	payload, ok := decodeMessage(messageStr)
+	if !ok {
+		return message, false
+	}
+
+	var texts []string
+	switch content := payload["content"].(type) {
+	case string:
+		if !strings.Contains(content, "system-reminder") {
+			texts = append(texts, content)
+		}
+
+	case []interface{}:
+		for _, item := range content {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			switch itemMap["type"] {
+			case "text":
+				if text, ok := itemMap["text"].(string); ok && text != "" {
+					// Skip system reminders
+					if !strings.Contains(text, "system-reminder") {
+						texts = append(texts, text)
+					}
+				}
+
+			case "tool_use":
+				toolCall := models.ToolCall{Name: "unknown"}
+				if name, ok := itemMap["name"].(string); ok {
+					toolCall.Name = name
+				}
+				if input, ok := itemMap["input"]; ok {
+					if inputBytes, err := json.MarshalIndent(input, "", "  "); err == nil {
+						toolCall.Input = string(inputBytes)
+					}
+				}
+				message.ToolCalls = append(message.ToolCalls, toolCall)
+
+			case "tool_result":
+				message.ToolResults = append(message.ToolResults, toolResultText(itemMap["content"]))
+			}
+		}
+	}
+
+	message.Content = strings.TrimSpace(strings.Join(texts, "\n\n"))
+
+	if message.Content == "" && len(message.ToolCalls) == 0 && len(message.ToolResults) == 0 {
+		return message, false
+	}
+
+	return message, true
+}
+
+// toolResultText flattens the content of a tool_result item into plain text
+func toolResultText(content interface{}) string {
+	switch c := content.(type) {
+	case string:
+		return c
+	case []interface{}:
+		var parts []string
+		for _, item := range c {
+			if itemMap, ok := item.(map[string]interface{}); ok {
+				if text, ok := itemMap["text"].(string); ok {
+					parts = append(parts, text)
+				}
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+	return ""
+}
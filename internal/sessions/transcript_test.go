@@ -0,0 +1,90 @@
+package sessions
+
+import (
+	"testing"
+)
+
+// TestParseMessage tests parsing of the different message content formats
+func TestParseMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		messageType string
+		json        string
+		ok          bool
+		content     string
+		toolCalls   int
+		toolResults int
+	}{
+		{
+			name:        "string content",
+			messageType: "user",
+			json:        `{"role":"user","content":"Hello\nworld"}`,
+			ok:          true,
+			content:     "Hello\nworld",
+		},
+		{
+			name:        "double encoded string",
+			messageType: "user",
+			json:        `"{\"role\":\"user\",\"content\":\"Hi\"}"`,
+			ok:          true,
+			content:     "Hi",
+		},
+		{
+			name:        "text array with code block",
+			messageType: "assistant",
+			json:        `{"content":[{"type":"text","text":"Here:\n` + "```go\\nfmt.Println()\\n```" + `"}]}`,
+			ok:          true,
+			content:     "Here:\n```go\nfmt.Println()\n```",
+		},
+		{
+			name:        "tool use",
+			messageType: "assistant",
+			json:        `{"content":[{"type":"tool_use","name":"Bash","input":{"command":"ls"}}]}`,
+			ok:          true,
+			toolCalls:   1,
+		},
+		{
+			name:        "tool result",
+			messageType: "user",
+			json:        `{"content":[{"type":"tool_result","content":"file.go"}]}`,
+			ok:          true,
+			toolResults: 1,
+		},
+		{
+			name:        "system reminder only",
+			messageType: "user",
+			json:        `{"content":[{"type":"text","text":"<system-reminder>x</system-reminder>"}]}`,
+			ok:          false,
+		},
+		{
+			name:        "invalid json",
+			messageType: "user",
+			json:        `not json`,
+			ok:          false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, ok := parseMessage(tt.messageType, tt.json)
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%v, got %v", tt.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if msg.Role != tt.messageType {
+				t.Errorf("expected role %q, got %q", tt.messageType, msg.Role)
+			}
+			if msg.Content != tt.content {
+				t.Errorf("expected content %q, got %q", tt.content, msg.Content)
+			}
+			if len(msg.ToolCalls) != tt.toolCalls {
+				t.Errorf("expected %d tool calls, got %d", tt.toolCalls, len(msg.ToolCalls))
+			}
+			if len(msg.ToolResults) != tt.toolResults {
+				t.Errorf("expected %d tool results, got %d", tt.toolResults, len(msg.ToolResults))
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package sessions
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/strrl/claude-resume/internal/paths"
+)
+
+// The trash holds a directory per deleted session, named after its ID, with
+// the lines removed from each of its files in <n>.jsonl and a manifest
+// recording where they came from.
+
+// trashManifestFile names the manifest of a session in the trash
+const trashManifestFile = "manifest.json"
+
+var trashDirOverride string
+
+// SetTrashDir overrides the directory holding deleted sessions, empty for the default
+func SetTrashDir(dir string) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	trashDirOverride = dir
+}
+
+// TrashDir returns the directory holding deleted sessions, in paths.DataDir
+// unless overridden
+func TrashDir() (string, error) {
+	settingsMu.RLock()
+	override := trashDirOverride
+	settingsMu.RUnlock()
+
+	if override != "" {
+		return override, nil
+	}
+
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trash"), nil
+}
+
+// TrashedSession describes a session moved to the trash by DeleteSession
+type TrashedSession struct {
+	SessionID   string        `json:"sessionId"`
+	ProjectPath string        `json:"projectPath,omitempty"` // First cwd the session recorded
+	Deleted     time.Time     `json:"deleted"`
+	Files       []TrashedFile `json:"files"`
+}
+
+// TrashedFile records the lines removed from one session file
+type TrashedFile struct {
+	Path  string      `json:"path"`
+	Lines int         `json:"lines"`
+	Mode  os.FileMode `json:"mode"` // Permissions to recreate the file with once it was removed
+}
+
+// Lines returns the number of lines removed from the session files
+func (s TrashedSession) Lines() int {
+	lines := 0
+	for _, file := range s.Files {
+		lines += file.Lines
+	}
+	return lines
+}
+
+// trashEntryDir returns the directory of a session in the trash
+func trashEntryDir(sessionID string) (string, error) {
+	if sessionID == "" || sessionID == "." || sessionID == ".." || strings.ContainsAny(sessionID, `/\`) {
+		return "", fmt.Errorf("invalid session ID %q", sessionID)
+	}
+	dir, err := TrashDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sessionID), nil
+}
+
+// trashLines copies the lines of files selected by drop to the trash entry of
+// a session. A session deleted again, e.g. after it was continued, gets the
+// new lines added to its entry.
+func trashLines(sessionID, projectPath string, files []string, drop func(line []byte) bool) error {
+	dir, err := trashEntryDir(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	entry, err := loadTrashEntry(dir)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		entry = &TrashedSession{SessionID: sessionID}
+	}
+	if projectPath != "" {
+		entry.ProjectPath = projectPath
+	}
+	entry.Deleted = time.Now()
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		target := trashLinesPath(dir, len(entry.Files))
+		count, err := copyLines(file, target, drop)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			os.Remove(target)
+			continue
+		}
+		entry.Files = append(entry.Files, TrashedFile{Path: file, Lines: count, Mode: info.Mode().Perm()})
+	}
+	return writeTrashManifest(dir, *entry)
+}
+
+// trashLinesPath returns the file holding the lines of the nth file of a trash entry
+func trashLinesPath(dir string, n int) string {
+	return filepath.Join(dir, fmt.Sprintf("%d.jsonl", n))
+}
+
+// copyLines writes the lines of src selected by keep to dst, returning their number
+func copyLines(src, dst string, keep func(line []byte) bool) (int, error) {
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	writer := bufio.NewWriter(f)
+	count := 0
+	var writeErr error
+	err = forEachLine(src, func(line []byte) {
+		if writeErr != nil || !keep(line) {
+			return
+		}
+		count++
+		if _, err := writer.Write(line); err != nil {
+			writeErr = err
+			return
+		}
+		writeErr = writer.WriteByte('\n')
+	})
+	if err == nil {
+		err = writeErr
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy %s to the trash: %w", src, err)
+	}
+	return count, nil
+}
+
+// loadTrashEntry reads the manifest of a trash entry, nil when there is none
+func loadTrashEntry(dir string) (*TrashedSession, error) {
+	data, err := os.ReadFile(filepath.Join(dir, trashManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+	var entry TrashedSession
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse trash manifest %s: %w", dir, err)
+	}
+	return &entry, nil
+}
+
+// writeTrashManifest atomically replaces the manifest of a trash entry
+func writeTrashManifest(dir string, entry TrashedSession) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trash manifest: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".manifest-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write trash manifest: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(dir, trashManifestFile)); err != nil {
+		return fmt.Errorf("failed to replace trash manifest: %w", err)
+	}
+	return nil
+}
+
+// ListTrash returns the sessions in the trash, most recently deleted first.
+// Entries without a readable manifest are skipped.
+func ListTrash() ([]TrashedSession, error) {
+	dir, err := TrashDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var trashed []TrashedSession
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		session, err := loadTrashEntry(filepath.Join(dir, entry.Name()))
+		if err != nil || session == nil {
+			continue
+		}
+		trashed = append(trashed, *session)
+	}
+	sort.SliceStable(trashed, func(i, j int) bool {
+		return trashed[i].Deleted.After(trashed[j].Deleted)
+	})
+	return trashed, nil
+}
+
+// RestoreSession moves a session from the trash back into its session files,
+// appending the lines that are not there already and recreating removed files.
+// Like ResolveSessionByPrefix, it accepts a unique prefix of the session ID.
+func RestoreSession(prefix string) (*TrashedSession, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("session ID prefix must not be empty")
+	}
+	trashed, err := ListTrash()
+	if err != nil {
+		return nil, err
+	}
+	var matches []TrashedSession
+	for _, session := range trashed {
+		if session.SessionID == prefix {
+			matches = []TrashedSession{session}
+			break
+		}
+		if strings.HasPrefix(session.SessionID, prefix) {
+			matches = append(matches, session)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no session ID in the trash starts with '%s'", prefix)
+	case 1:
+	default:
+		return nil, fmt.Errorf("session ID prefix '%s' is ambiguous in the trash", prefix)
+	}
+
+	session := matches[0]
+	dir, err := trashEntryDir(session.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	for i, file := range session.Files {
+		if err := restoreLines(trashLinesPath(dir, i), file); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to remove %s from the trash: %w", session.SessionID, err)
+	}
+	return &session, nil
+}
+
+// restoreLines appends the lines of src missing from the session file back to it
+func restoreLines(src string, file TrashedFile) error {
+	present := make(map[string]bool)
+	if _, err := os.Stat(file.Path); err == nil {
+		if err := forEachLine(file.Path, func(line []byte) { present[string(line)] = true }); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file.Path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(file.Path), err)
+	}
+	mode := file.Mode
+	if mode == 0 {
+		mode = 0o600
+	}
+	f, err := os.OpenFile(file.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, mode)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file.Path, err)
+	}
+
+	writer := bufio.NewWriter(f)
+	var writeErr error
+	err = forEachLine(src, func(line []byte) {
+		if writeErr != nil || present[string(line)] {
+			return
+		}
+		if _, err := writer.Write(line); err != nil {
+			writeErr = err
+			return
+		}
+		writeErr = writer.WriteByte('\n')
+	})
+	if err == nil {
+		err = writeErr
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", file.Path, err)
+	}
+	return nil
+}
+
+// EmptyTrash erases the sessions in the trash for good, returning their number
+func EmptyTrash() (int, error) {
+	trashed, err := ListTrash()
+	if err != nil {
+		return 0, err
+	}
+	dir, err := TrashDir()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("failed to empty the trash: %w", err)
+	}
+	return len(trashed), nil
+}
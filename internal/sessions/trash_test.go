@@ -0,0 +1,100 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTrashRoundTrip tests that a deleted session is kept in the trash until it
+// is restored into its files, including a file the delete removed
+func TestTrashRoundTrip(t *testing.T) {
+	SetTrashDir(filepath.Join(t.TempDir(), "trash"))
+	t.Cleanup(func() { SetTrashDir("") })
+
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "shared.jsonl")
+	own := filepath.Join(dir, "own.jsonl")
+	sharedContent := `{"type":"user","sessionId":"keep","uuid":"1"}
+{"type":"user","sessionId":"drop","uuid":"2","cwd":"/proj"}
+{"type":"summary","summary":"x","leafUuid":"2"}
+`
+	ownContent := `{"type":"assistant","sessionId":"drop","uuid":"3"}
+`
+	if err := os.WriteFile(shared, []byte(sharedContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(own, []byte(ownContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := deleteFromFiles("drop", []string{shared, own}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if got, _ := os.ReadFile(shared); string(got) != `{"type":"user","sessionId":"keep","uuid":"1"}`+"\n" {
+		t.Errorf("shared file after delete:\n%s", got)
+	}
+	if _, err := os.Stat(own); !os.IsNotExist(err) {
+		t.Error("a file left empty should be removed")
+	}
+
+	trashed, err := ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	if len(trashed) != 1 || trashed[0].SessionID != "drop" || trashed[0].ProjectPath != "/proj" || trashed[0].Lines() != 3 {
+		t.Fatalf("trash = %+v, want the three lines of drop", trashed)
+	}
+
+	if _, err := RestoreSession("x"); err == nil || !strings.Contains(err.Error(), "no session ID") {
+		t.Errorf("restoring an unknown prefix: %v", err)
+	}
+	restored, err := RestoreSession("dr")
+	if err != nil {
+		t.Fatalf("RestoreSession failed: %v", err)
+	}
+	if restored.SessionID != "drop" {
+		t.Errorf("restored %s, want drop", restored.SessionID)
+	}
+	if got, _ := os.ReadFile(shared); string(got) != sharedContent {
+		t.Errorf("shared file after restore:\n%s", got)
+	}
+	info, err := os.Stat(own)
+	if err != nil {
+		t.Fatalf("removed file was not recreated: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("recreated file has permissions %o, want 600", info.Mode().Perm())
+	}
+	if trashed, _ := ListTrash(); len(trashed) != 0 {
+		t.Errorf("trash after restore = %+v, want it empty", trashed)
+	}
+}
+
+// TestEmptyTrash tests that emptying the trash erases every deleted session
+func TestEmptyTrash(t *testing.T) {
+	SetTrashDir(filepath.Join(t.TempDir(), "trash"))
+	t.Cleanup(func() { SetTrashDir("") })
+
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	for _, id := range []string{"a", "b"} {
+		if err := os.WriteFile(path, []byte(`{"type":"user","sessionId":"`+id+`","uuid":"1"}`+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := deleteFromFiles(id, []string{path}); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+	}
+
+	if _, err := trashEntryDir("../b"); err == nil {
+		t.Error("a session ID naming another directory should be rejected")
+	}
+	count, err := EmptyTrash()
+	if err != nil || count != 2 {
+		t.Fatalf("EmptyTrash() = %d, %v, want 2", count, err)
+	}
+	if trashed, _ := ListTrash(); len(trashed) != 0 {
+		t.Errorf("trash = %+v, want it empty", trashed)
+	}
+}
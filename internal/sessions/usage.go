@@ -0,0 +1,202 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/strrl/claude-resume/internal/db"
+)
+
+// ModelRate is the price of a model in USD per million tokens
+type ModelRate struct {
+	InputPerMTok  float64
+	OutputPerMTok float64
+}
+
+// defaultModelRate is used for models that don't match any configured rate
+var defaultModelRate = ModelRate{InputPerMTok: 3, OutputPerMTok: 15}
+
+var (
+	modelRatesMu sync.RWMutex
+	// modelRates maps a model family (matched as a substring of the model name) to its rate
+	modelRates = map[string]ModelRate{
+		"opus":   {InputPerMTok: 15, OutputPerMTok: 75},
+		"sonnet": {InputPerMTok: 3, OutputPerMTok: 15},
+		"haiku":  {InputPerMTok: 0.8, OutputPerMTok: 4},
+	}
+)
+
+// SetModelRate overrides the rate used for models whose name contains family
+func SetModelRate(family string, rate ModelRate) {
+	modelRatesMu.Lock()
+	defer modelRatesMu.Unlock()
+	modelRates[strings.ToLower(family)] = rate
+}
+
+// rateForModel returns the configured rate for a model name
+func rateForModel(model string) ModelRate {
+	modelRatesMu.RLock()
+	defer modelRatesMu.RUnlock()
+
+	// Prefer the longest matching family so specific overrides win
+	best := ""
+	lower := strings.ToLower(model)
+	for family := range modelRates {
+		if strings.Contains(lower, family) && len(family) > len(best) {
+			best = family
+		}
+	}
+	if best == "" {
+		return defaultModelRate
+	}
+	return modelRates[best]
+}
+
+// TokenCounts holds input and output token totals
+type TokenCounts struct {
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// SessionUsage contains token usage aggregated over a session's assistant messages
+type SessionUsage struct {
+	TokenCounts
+	ByModel   map[string]TokenCounts
+	Available bool // False when no assistant message recorded usage
+}
+
+// TotalTokens returns the sum of input and output tokens
+func (u *SessionUsage) TotalTokens() int64 {
+	return u.InputTokens + u.OutputTokens
+}
+
+// EstimatedCost returns a rough cost estimate in USD based on the configured model rates
+func (u *SessionUsage) EstimatedCost() float64 {
+	var cost float64
+	for model, counts := range u.ByModel {
+		rate := rateForModel(model)
+		cost += float64(counts.InputTokens) / 1e6 * rate.InputPerMTok
+		cost += float64(counts.OutputTokens) / 1e6 * rate.OutputPerMTok
+	}
+	return cost
+}
+
+// Models returns the models that answered in the session, the one with the most tokens first
+func (u *SessionUsage) Models() []string {
+	models := make([]string, 0, len(u.ByModel))
+	for model := range u.ByModel {
+		models = append(models, model)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		a := u.ByModel[models[i]].InputTokens + u.ByModel[models[i]].OutputTokens
+		b := u.ByModel[models[j]].InputTokens + u.ByModel[models[j]].OutputTokens
+		if a != b {
+			return a > b
+		}
+		return models[i] < models[j]
+	})
+	return models
+}
+
+// FormatModels renders the models of a session as a comma-separated list, or "n/a" when unavailable
+func FormatModels(u *SessionUsage) string {
+	if u == nil || !u.Available {
+		return "n/a"
+	}
+	return strings.Join(u.Models(), ", ")
+}
+
+// FormatUsage renders usage as a short human-readable string, or "n/a" when unavailable
+func FormatUsage(u *SessionUsage) string {
+	if u == nil || !u.Available {
+		return "n/a"
+	}
+	return fmt.Sprintf("%s tokens (%s in / %s out) ~$%.2f",
+		formatTokenCount(u.TotalTokens()),
+		formatTokenCount(u.InputTokens),
+		formatTokenCount(u.OutputTokens),
+		u.EstimatedCost())
+}
+
+// formatTokenCount abbreviates large token counts, e.g. 12345 -> "12.3k"
+func formatTokenCount(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// FetchSessionUsage sums the token usage recorded on a session's assistant messages
+func FetchSessionUsage(sessionID string) (*SessionUsage, error) {
+	plan, err := lookupPlan()
+	if err != nil {
+		return nil, err
+	}
+	usages, err := fetchUsages(context.Background(), plan, []string{sessionID})
+	if err != nil {
+		return nil, err
+	}
+	return usages[sessionID], nil
+}
+
+// FetchUsageForSessions returns the token usage of each of sessionIDs, like
+// FetchSessionUsage, in a single query. Every session gets an entry, unavailable
+// when none of its messages recorded usage.
+func FetchUsageForSessions(ctx context.Context, sessionIDs []string) (map[string]*SessionUsage, error) {
+	if len(sessionIDs) == 0 {
+		return make(map[string]*SessionUsage), nil
+	}
+	plan, err := listingPlan()
+	if err != nil {
+		return nil, err
+	}
+	return fetchUsages(ctx, plan, sessionIDs)
+}
+
+// fetchUsages runs usageQuery for sessionIDs over plan
+func fetchUsages(ctx context.Context, plan *scanPlan, sessionIDs []string) (map[string]*SessionUsage, error) {
+	args := make([]interface{}, len(sessionIDs))
+	usages := make(map[string]*SessionUsage, len(sessionIDs))
+	for i, id := range sessionIDs {
+		args[i] = id
+		usages[id] = &SessionUsage{ByModel: make(map[string]TokenCounts)}
+	}
+
+	rows, err := db.QueryContext(ctx, usageQuery(plan, len(sessionIDs)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute usage query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sessionID, model sql.NullString
+		var inputTokens, outputTokens sql.NullInt64
+
+		if err := rows.Scan(&sessionID, &model, &inputTokens, &outputTokens); err != nil {
+			continue
+		}
+		usage, ok := usages[sessionID.String]
+		if !ok {
+			continue
+		}
+
+		counts := TokenCounts{InputTokens: inputTokens.Int64, OutputTokens: outputTokens.Int64}
+		usage.ByModel[model.String] = counts
+		usage.InputTokens += counts.InputTokens
+		usage.OutputTokens += counts.OutputTokens
+		usage.Available = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+
+	return usages, nil
+}
@@ -0,0 +1,68 @@
+package sessions
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestFormatUsageUnavailable tests that missing usage is reported as n/a
+func TestFormatUsageUnavailable(t *testing.T) {
+	if got := FormatUsage(nil); got != "n/a" {
+		t.Errorf("expected n/a for nil usage, got %q", got)
+	}
+	if got := FormatUsage(&SessionUsage{}); got != "n/a" {
+		t.Errorf("expected n/a for unavailable usage, got %q", got)
+	}
+}
+
+// TestEstimatedCost tests cost estimation across models
+func TestEstimatedCost(t *testing.T) {
+	usage := &SessionUsage{
+		ByModel: map[string]TokenCounts{
+			"claude-opus-4-20250514":   {InputTokens: 1_000_000, OutputTokens: 1_000_000},
+			"claude-sonnet-4-20250514": {InputTokens: 1_000_000},
+		},
+		Available: true,
+	}
+
+	// opus: 15 + 75, sonnet: 3
+	if cost := usage.EstimatedCost(); math.Abs(cost-93) > 0.001 {
+		t.Errorf("expected cost 93, got %f", cost)
+	}
+}
+
+// TestFormatModels tests that a session's models are listed by token usage
+func TestFormatModels(t *testing.T) {
+	if got := FormatModels(nil); got != "n/a" {
+		t.Errorf("expected n/a for nil usage, got %q", got)
+	}
+
+	usage := &SessionUsage{
+		ByModel: map[string]TokenCounts{
+			"claude-haiku-3-5":         {InputTokens: 10},
+			"claude-opus-4-20250514":   {InputTokens: 500, OutputTokens: 500},
+			"claude-sonnet-4-20250514": {OutputTokens: 100},
+		},
+		Available: true,
+	}
+	want := "claude-opus-4-20250514, claude-sonnet-4-20250514, claude-haiku-3-5"
+	if got := FormatModels(usage); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestBatchedQueries tests that the usage and final reply queries bind one
+// placeholder per session, so a listing takes one query for all its sessions
+func TestBatchedQueries(t *testing.T) {
+	plan := globPlan("/tmp/projects/**/*.jsonl")
+	queries := map[string]string{
+		"usage":         usageQuery(plan, 3),
+		"final replies": finalRepliesQuery(plan, 3, finalReplyCandidates),
+	}
+	for name, query := range queries {
+		if strings.Count(query, "?") != 3 || !strings.Contains(query, "IN (?,?,?)") {
+			t.Errorf("%s query does not bind three sessions:\n%s", name, query)
+		}
+	}
+}
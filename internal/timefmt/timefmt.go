@@ -0,0 +1,60 @@
+package timefmt
+
+import (
+	"fmt"
+	"time"
+)
+
+// Modes lists the supported time display modes
+var Modes = []string{"absolute", "relative", "both"}
+
+// Valid reports whether mode is one of Modes
+func Valid(mode string) bool {
+	for _, m := range Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders t for display. Absolute times use the Go layout; relative
+// times read like "5m ago"; both shows the absolute time followed by the
+// relative one. An unknown mode is treated as absolute and the zero time is
+// shown as "unknown".
+func Format(t time.Time, layout, mode string) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	switch mode {
+	case "relative":
+		return humanizeTime(t)
+	case "both":
+		return fmt.Sprintf("%s (%s)", t.Format(layout), humanizeTime(t))
+	default:
+		return t.Format(layout)
+	}
+}
+
+// humanizeTime describes how long ago t was, e.g. "just now", "5m ago" or "2d ago"
+func humanizeTime(t time.Time) string {
+	return humanizeSince(t, time.Now())
+}
+
+func humanizeSince(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute: // Includes times slightly in the future due to clock skew
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d/(30*24*time.Hour)))
+	default:
+		return fmt.Sprintf("%dy ago", int(d/(365*24*time.Hour)))
+	}
+}
@@ -0,0 +1,53 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+)
+
+// TestHumanizeSince tests the relative time buckets
+func TestHumanizeSince(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{-5 * time.Second, "just now"},
+		{0, "just now"},
+		{59 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{59 * time.Minute, "59m ago"},
+		{3 * time.Hour, "3h ago"},
+		{47 * time.Hour, "1d ago"},
+		{2 * 24 * time.Hour, "2d ago"},
+		{45 * 24 * time.Hour, "1mo ago"},
+		{400 * 24 * time.Hour, "1y ago"},
+	}
+
+	for _, tt := range tests {
+		if got := humanizeSince(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("humanizeSince(-%v) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
+
+// TestFormat tests the display modes
+func TestFormat(t *testing.T) {
+	layout := "2006-01-02 15:04"
+	ts := time.Now().Add(-3 * time.Hour)
+	absolute := ts.Format(layout)
+
+	if got := Format(ts, layout, "absolute"); got != absolute {
+		t.Errorf("absolute: got %q, want %q", got, absolute)
+	}
+	if got := Format(ts, layout, "relative"); got != "3h ago" {
+		t.Errorf("relative: got %q, want %q", got, "3h ago")
+	}
+	if got, want := Format(ts, layout, "both"), absolute+" (3h ago)"; got != want {
+		t.Errorf("both: got %q, want %q", got, want)
+	}
+	if got := Format(time.Time{}, layout, "both"); got != "unknown" {
+		t.Errorf("zero time: got %q, want unknown", got)
+	}
+}
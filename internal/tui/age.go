@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/strrl/claude-resume/internal/config"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// Colors of session activity by age, see styleForAge
+const (
+	freshColor  = lipgloss.Color("42")  // Green
+	recentColor = lipgloss.Color("220") // Yellow
+	staleColor  = lipgloss.Color("240") // Dim
+)
+
+// activeMarker prefixes the summary of a session that is still being written,
+// see markActiveCmd
+const activeMarker = "🟢 active "
+
+// multipleDirsMarker flags a session that moved to other directories with cd.
+// It is listed under the directory it started in, where it is resumed.
+const multipleDirsMarker = "↗ multiple dirs"
+
+// applyActive sets the Active flag of the probed sessions, wherever they are listed
+func (m *model) applyActive(msg ActiveSessionsMsg) {
+	probed := make(map[string]bool, len(msg.SessionIDs))
+	for _, id := range msg.SessionIDs {
+		probed[id] = true
+	}
+	mark := func(list []models.Session) {
+		for i := range list {
+			if probed[list[i].SessionID] {
+				list[i].Active = msg.Active[list[i].SessionID]
+			}
+		}
+	}
+	if m.selectedProject != nil {
+		mark(m.selectedProject.Sessions)
+	}
+	mark(m.recentSessions)
+	m.updateViewport()
+}
+
+// styleForAge returns the style of a session last active at t: green while it
+// is fresh, yellow while it is recent and dim once older. The thresholds come
+// from Options.FreshAge and Options.RecentAge. Like every style it renders as
+// plain text when color is disabled.
+func (m model) styleForAge(t time.Time) lipgloss.Style {
+	fresh, recent := m.opts.FreshAge, m.opts.RecentAge
+	if fresh <= 0 {
+		fresh = config.Default().FreshAge
+	}
+	if recent <= 0 {
+		recent = config.Default().RecentAge
+	}
+
+	age := time.Since(t)
+	switch {
+	case age < fresh:
+		return m.newStyle().Foreground(freshColor)
+	case age < recent:
+		return m.newStyle().Foreground(recentColor)
+	default:
+		return m.newStyle().Foreground(staleColor)
+	}
+}
@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// newRenderer returns the renderer behind every TUI style. With color disabled
+// all styles render as plain text.
+func newRenderer(color bool) *lipgloss.Renderer {
+	renderer := lipgloss.NewRenderer(os.Stdout)
+	if !color {
+		renderer.SetColorProfile(termenv.Ascii)
+		return renderer
+	}
+	// The caller has already resolved NO_COLOR, so only ask the terminal
+	// what it supports rather than letting lipgloss check the environment again
+	renderer.SetColorProfile(termenv.NewOutput(os.Stdout).ColorProfile())
+	return renderer
+}
+
+// newStyle returns a style bound to the model's renderer
+func (m model) newStyle() lipgloss.Style {
+	return m.renderer.NewStyle()
+}
@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+// renderConversation renders the full conversation of the viewed session for messageView
+func (m model) renderConversation() string {
+	var s strings.Builder
+
+	width := m.viewport.Width - 2
+	if width < 20 {
+		width = 20
+	}
+
+	if m.conversationErr != nil {
+		return fmt.Sprintf("Error loading conversation: %v", m.conversationErr)
+	}
+	if m.conversation == nil {
+		loadingStyle := m.newStyle().
+			Foreground(m.theme.Accent)
+		return loadingStyle.Render(m.loadingIndicator.View())
+	}
+	if len(m.conversation) == 0 {
+		emptyStyle := m.newStyle().
+			Foreground(lipgloss.Color("240")).
+			Italic(true)
+		return emptyStyle.Render("No messages found")
+	}
+
+	timeStyle := m.newStyle().
+		Foreground(lipgloss.Color("240"))
+	toolStyle := m.newStyle().
+		Foreground(m.theme.Tool)
+	resultStyle := m.newStyle().
+		Foreground(lipgloss.Color("243"))
+	moreStyle := timeStyle.Italic(true)
+	limit := m.opts.MessageLines
+	if m.expandMessages {
+		limit = 0
+	}
+	writeMore := func(hidden int, indent string) {
+		if hidden > 0 {
+			s.WriteString(indent + moreStyle.Render("… "+sessions.Pluralize(hidden, "more line")+" (e to expand)") + "\n")
+		}
+	}
+
+	messages := m.conversation
+	if m.opts.NewestFirst {
+		messages = slices.Clone(messages)
+		slices.Reverse(messages)
+	}
+	for i, msg := range messages {
+		if i > 0 {
+			s.WriteString("\n" + strings.Repeat("─", width) + "\n")
+			divider := sessions.GapDivider(messages[i-1].Timestamp, msg.Timestamp)
+			if m.opts.NewestFirst {
+				divider = sessions.EarlierGapDivider(msg.Timestamp, messages[i-1].Timestamp)
+			}
+			if divider != "" {
+				s.WriteString(timeStyle.Italic(true).Render(divider) + "\n")
+			}
+			s.WriteString("\n")
+		}
+
+		roleStyle, contentStyle := m.roleStyles(msg.Role)
+		s.WriteString(roleStyle.Render(roleTitle(msg.Role)))
+		if !msg.Timestamp.IsZero() {
+			s.WriteString(" " + timeStyle.Render(m.formatTime(msg.Timestamp)))
+		}
+		s.WriteString("\n")
+
+		content, hidden := capLines(msg.Content, limit, width)
+		if content != "" && m.opts.NoMarkdown {
+			for _, line := range wrapParagraphs(content, width) {
+				s.WriteString(contentStyle.Render(line) + "\n")
+			}
+		} else if content != "" {
+			for _, line := range m.renderMarkdown(content, width, contentStyle) {
+				s.WriteString(line + "\n")
+			}
+		}
+		writeMore(hidden, "")
+
+		for _, call := range msg.ToolCalls {
+			s.WriteString(toolStyle.Render("🔧 "+call.Name) + "\n")
+			input, hidden := capLines(call.Input, limit, width-2)
+			for _, line := range wrapParagraphs(input, width-2) {
+				s.WriteString("  " + toolStyle.Render(line) + "\n")
+			}
+			writeMore(hidden, "  ")
+		}
+
+		for _, result := range msg.ToolResults {
+			s.WriteString(resultStyle.Render("↩ Tool result") + "\n")
+			result, hidden := capLines(result, limit, width-2)
+			for _, line := range wrapParagraphs(result, width-2) {
+				s.WriteString("  " + resultStyle.Render(line) + "\n")
+			}
+			writeMore(hidden, "  ")
+		}
+	}
+
+	return s.String()
+}
+
+// toggleNewestFirst reverses the order of the conversation in messageView and
+// scrolls back to the top, where the first message in the new order is
+func (m model) toggleNewestFirst() (tea.Model, tea.Cmd) {
+	m.opts.NewestFirst = !m.opts.NewestFirst
+	m.updateViewport()
+	m.viewport.GotoTop()
+	if m.opts.NewestFirst {
+		return m.flashStatus("Showing the newest messages first")
+	}
+	return m.flashStatus("Showing the oldest messages first")
+}
+
+// toggleExpandMessages shows the messages cut to Options.MessageLines in full,
+// or cuts them again
+func (m model) toggleExpandMessages() (tea.Model, tea.Cmd) {
+	if m.opts.MessageLines <= 0 {
+		return m.flashStatus("Messages are shown in full (see --message-lines)")
+	}
+	m.expandMessages = !m.expandMessages
+	m.updateViewport()
+	if m.expandMessages {
+		return m.flashStatus("Showing long messages in full")
+	}
+	return m.flashStatus(fmt.Sprintf("Showing the first %d lines of long messages", m.opts.MessageLines))
+}
+
+// capLines cuts text to its first limit lines, returning how many lines were
+// left out; 0 for limit keeps all of them. A single line longer than limit
+// wrapped lines of width is cut as well, so that a huge message is never
+// rendered in full.
+func capLines(text string, limit, width int) (string, int) {
+	if limit <= 0 {
+		return text, 0
+	}
+	text = strings.TrimRight(text, "\n")
+	end := 0
+	for i := 0; i < limit && end < len(text); i++ {
+		next := strings.IndexByte(text[end:], '\n')
+		if next < 0 {
+			end = len(text)
+			break
+		}
+		end += next + 1
+	}
+	kept := strings.TrimSuffix(text[:end], "\n")
+	if maxBytes := limit * width; len(kept) > maxBytes {
+		for maxBytes > 0 && !utf8.RuneStart(kept[maxBytes]) {
+			maxBytes--
+		}
+		kept = kept[:maxBytes]
+	}
+
+	rest := strings.TrimPrefix(text[len(kept):], "\n")
+	if rest == "" {
+		return kept, 0
+	}
+	return kept, strings.Count(rest, "\n") + 1
+}
+
+// renderToolTimeline renders the chronological tool invocations of the viewed session for toolView
+func (m model) renderToolTimeline() string {
+	if m.conversationErr != nil {
+		return fmt.Sprintf("Error loading tool usage: %v", m.conversationErr)
+	}
+	if m.toolCalls == nil {
+		loadingStyle := m.newStyle().
+			Foreground(m.theme.Accent)
+		return loadingStyle.Render(m.loadingIndicator.View())
+	}
+	if len(m.toolCalls) == 0 {
+		emptyStyle := m.newStyle().
+			Foreground(lipgloss.Color("240")).
+			Italic(true)
+		return emptyStyle.Render("No tool calls in this session")
+	}
+
+	timeStyle := m.newStyle().
+		Foreground(lipgloss.Color("240"))
+	nameStyle := m.newStyle().
+		Foreground(m.theme.Tool).
+		Bold(true)
+	descriptionStyle := m.newStyle().
+		Foreground(lipgloss.Color("250"))
+
+	nameWidth := 0
+	for _, call := range m.toolCalls {
+		if len(call.Name) > nameWidth {
+			nameWidth = len(call.Name)
+		}
+	}
+
+	var s strings.Builder
+	for _, call := range m.toolCalls {
+		line := timeStyle.Render(m.formatTime(call.Timestamp)) + "  " +
+			nameStyle.Render(fmt.Sprintf("%-*s", nameWidth, call.Name))
+		if description := sessions.DescribeToolCall(call); description != "" {
+			line += "  " + descriptionStyle.Render(description)
+		}
+		s.WriteString(line + "\n")
+	}
+	return s.String()
+}
+
+// roleStyles returns the styles for the role label and the content of a message
+func (m model) roleStyles(role string) (lipgloss.Style, lipgloss.Style) {
+	switch role {
+	case "user":
+		return m.newStyle().Foreground(m.theme.User).Bold(true),
+			m.newStyle().Foreground(lipgloss.Color("252"))
+	case "assistant":
+		return m.newStyle().Foreground(m.theme.Assistant).Bold(true),
+			m.newStyle().Foreground(lipgloss.Color("250"))
+	default:
+		return m.newStyle().Foreground(lipgloss.Color("243")),
+			m.newStyle().Foreground(lipgloss.Color("248"))
+	}
+}
+
+// roleTitle returns the display name of a message role
+func roleTitle(role string) string {
+	switch role {
+	case "user":
+		return "User"
+	case "assistant":
+		return "Assistant"
+	case "summary":
+		return "Summary"
+	default:
+		return role
+	}
+}
+
+// wrapParagraphs wraps text to width line by line, keeping the original line breaks
+func wrapParagraphs(text string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		lines = append(lines, wrapText(line, width)...)
+	}
+	return lines
+}
+
+// conversationFor returns whether the conversation of sessionID is the one being viewed
+func (m model) conversationFor(sessionID string) bool {
+	return m.currentMode == messageView && m.conversationID == sessionID
+}
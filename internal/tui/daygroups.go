@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dayLabel names the day of t relative to now: "Today", "Yesterday" or its date
+func dayLabel(t, now time.Time) string {
+	t, now = t.Local(), now.Local()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	switch {
+	case day.Equal(today):
+		return "Today"
+	case day.Equal(today.AddDate(0, 0, -1)):
+		return "Yesterday"
+	}
+	return day.Format("2006-01-02")
+}
+
+// sessionDayHeader returns the day label shown above the session at index when
+// the session list is grouped by day, or "" when it continues the day of the
+// session before it. Headers are only rendered, so the cursor never lands on them.
+func (m model) sessionDayHeader(index int) string {
+	if !m.opts.GroupByDay || m.selectedProject == nil {
+		return ""
+	}
+	list := m.selectedProject.Sessions
+	now := time.Now()
+	label := dayLabel(list[index].LastActivity, now)
+	if index > 0 && dayLabel(list[index-1].LastActivity, now) == label {
+		return ""
+	}
+	return label
+}
+
+// toggleGroupByDay switches the session list between a flat list and one
+// separated into days
+func (m model) toggleGroupByDay() (tea.Model, tea.Cmd) {
+	m.opts.GroupByDay = !m.opts.GroupByDay
+	m.updateViewport()
+	if m.opts.GroupByDay {
+		return m.flashStatus("Grouping sessions by day")
+	}
+	return m.flashStatus("Listing sessions without day headers")
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// renderSessionDetail renders the facts about a session shown above its preview
+func (m model) renderSessionDetail(detail *models.SessionDetail) string {
+	labelStyle := m.newStyle().
+		Foreground(lipgloss.Color("240"))
+	valueStyle := m.newStyle().
+		Foreground(lipgloss.Color("250"))
+
+	resumed := "no"
+	if detail.IsResumed {
+		resumed = "yes"
+	}
+	rows := [][2]string{
+		{"Session", detail.SessionID},
+		{"Project", detail.ProjectPath},
+		{"Branch", detail.GitBranch},
+		{"Created", m.formatTime(detail.CreatedAt)},
+		{"Active", m.formatTime(detail.LastActivity)},
+		{"Messages", fmt.Sprintf("%d (%s)", detail.MessageCount, sessions.Pluralize(detail.ToolCalls, "tool call"))},
+		{"Resumed", resumed},
+	}
+
+	var s strings.Builder
+	for _, row := range rows {
+		if row[1] == "" {
+			continue // The branch is only recorded by recent versions of Claude Code
+		}
+		label := fmt.Sprintf("%-9s", row[0])
+		value := truncateSummary(row[1], m.rightViewport.Width-len(label)-2)
+		s.WriteString(labelStyle.Render(label) + valueStyle.Render(value) + "\n")
+	}
+	return s.String()
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/strrl/claude-resume/internal/cache"
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// openDiskCache loads the on-disk preview cache and drops entries whose
+// session file has changed since they were stored
+func openDiskCache() (*cache.Store, error) {
+	path, err := cache.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	// A malformed file still yields an empty store, so report the error but keep going
+	store, err := cache.Load(path)
+
+	modTimes, walkErr := sessions.SessionFileModTimes()
+	if walkErr != nil {
+		// Without modification times no entry can be trusted
+		modTimes = nil
+	}
+	store.Prune(modTimes)
+	return store, err
+}
+
+// warmFromDiskCache fills the in-memory caches with the previews stored on disk
+func (m *model) warmFromDiskCache() {
+	if m.diskCache == nil {
+		return
+	}
+	messagesOnly := sessions.MessagesOnly()
+	messageTypes := sessions.MessageTypesKey()
+	for sessionID, entry := range m.diskCache.Entries() {
+		if entry.MessagesOnly != messagesOnly || entry.MessageTypes != messageTypes {
+			continue // Formatted for the other preview mode or other message types
+		}
+		if entry.Messages == nil && entry.MessageCount > 0 {
+			continue // Stored by an older version, without the times of the messages
+		}
+		m.cacheMessages(sessionID, entry.Messages, entry.MessageCount, entry.Usage, entry.Detail)
+	}
+}
+
+// cacheMessages stores a loaded preview in the in-memory caches
+func (m *model) cacheMessages(sessionID string, messages []sessions.PreviewLine, count int, usage *sessions.SessionUsage, detail *models.SessionDetail) {
+	m.usageCache[sessionID] = usage
+	m.detailCache[sessionID] = detail
+	m.messageCounts[sessionID] = count
+	if len(messages) == 0 {
+		m.messageCache[sessionID] = []sessions.PreviewLine{{Text: "No messages found for this session"}}
+	} else {
+		m.messageCache[sessionID] = messages
+	}
+}
+
+// toggleMessagesOnly switches previews between every message and text exchanges
+// only, then reloads the preview of the selected session
+func (m model) toggleMessagesOnly() (tea.Model, tea.Cmd) {
+	messagesOnly := !sessions.MessagesOnly()
+	sessions.SetMessagesOnly(messagesOnly)
+
+	// Previews are formatted when loaded, so the cached ones no longer apply
+	m.messageCache = make(map[string][]sessions.PreviewLine)
+	m.warmFromDiskCache()
+
+	var cmd tea.Cmd
+	if m.sessionCursor < len(m.selectedProject.Sessions) {
+		var updated tea.Model
+		updated, cmd = m.loadCurrentSessionMessages()
+		m = updated.(model)
+	}
+
+	status := "Showing tool calls"
+	if messagesOnly {
+		status = "Hiding tool calls"
+	}
+	updated, flash := m.flashStatus(status)
+	return updated, tea.Batch(cmd, flash)
+}
+
+// forgetMessages drops the cached preview of a session, in memory and on disk
+func (m *model) forgetMessages(sessionID string) {
+	delete(m.messageCache, sessionID)
+	delete(m.usageCache, sessionID)
+	delete(m.detailCache, sessionID)
+	delete(m.messageCounts, sessionID)
+	if m.diskCache != nil {
+		m.diskCache.Delete(sessionID)
+	}
+}
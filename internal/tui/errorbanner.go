@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+// retryFunc repeats a load that failed
+type retryFunc func(m model) (tea.Model, tea.Cmd)
+
+// showError shows err in the error banner until it is dismissed with esc or
+// retried with r. The current view stays usable underneath.
+func (m *model) showError(err error, retry retryFunc) {
+	m.err = err
+	m.retry = retry
+}
+
+// updateErrorBanner handles the keys of the error banner. It returns false for
+// keys that belong to the view underneath.
+func (m model) updateErrorBanner(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc":
+		m.err, m.retry = nil, nil
+		return m, nil, true
+	case "r":
+		retry := m.retry
+		m.err, m.retry = nil, nil
+		if retry == nil {
+			return m, nil, true
+		}
+		updated, cmd := retry(m)
+		return updated, cmd, true
+	}
+	return m, nil, false
+}
+
+// renderErrorBanner renders the error banner, which takes the place of the header
+func (m model) renderErrorBanner() string {
+	style := m.newStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("231")).
+		Background(lipgloss.Color("124"))
+
+	// Multi-line DuckDB errors would push the list down, so only the first line is shown
+	text, _, _ := strings.Cut(m.err.Error(), "\n")
+	return style.Render(runewidth.Truncate("Error: "+text, m.width, "…"))
+}
+
+// retryProjects reloads the page of projects starting at offset
+func retryProjects(offset int) retryFunc {
+	return func(m model) (tea.Model, tea.Cmd) {
+		m.loadingState = sessions.StateLoadingProjects
+		m.loadingIndicator.SetMessage("Loading projects...")
+
+		ctx, cancel := context.WithCancel(m.ctx)
+		m.activeRequests["projects"] = cancel
+		return m, tea.Batch(loadProjectsCmd(ctx, offset), tickCmd())
+	}
+}
+
+// retrySessions reloads the page of a project's sessions starting at offset,
+// unless another project has been selected since
+func retrySessions(projectPath string, offset int) retryFunc {
+	return func(m model) (tea.Model, tea.Cmd) {
+		if m.currentMode != sessionView || m.selectedProject == nil || m.selectedProject.Path != projectPath {
+			return m, nil
+		}
+		m.loadingState = sessions.StateLoadingSessions
+		m.loadingIndicator.SetMessage("Loading sessions...")
+		m.updateViewport()
+
+		ctx, cancel := context.WithCancel(m.ctx)
+		m.activeRequests["sessions"] = cancel
+		return m, tea.Batch(loadSessionsCmd(ctx, projectPath, offset), tickCmd())
+	}
+}
+
+// retryRecent reloads the recent sessions if their view is still shown
+func retryRecent(m model) (tea.Model, tea.Cmd) {
+	if m.currentMode != recentView {
+		return m, nil
+	}
+	return m.enterRecentView()
+}
+
+// retryPreview reloads the preview of the session under the cursor
+func retryPreview(m model) (tea.Model, tea.Cmd) {
+	if m.currentMode != sessionView || m.selectedProject == nil || m.sessionCursor >= len(m.selectedProject.Sessions) {
+		return m, nil
+	}
+	return m.loadCurrentSessionMessages()
+}
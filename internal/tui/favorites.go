@@ -0,0 +1,38 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleFavoriteToggled updates the star of a session in every list showing it.
+// Favorites are listed first, so the current page of sessions is reloaded in
+// the background to take its new place; the cursor follows the session it was on.
+func (m model) handleFavoriteToggled(msg FavoriteToggledMsg) (tea.Model, tea.Cmd) {
+	if msg.Error != nil {
+		return m.flashStatus("Favorite failed: " + msg.Error.Error())
+	}
+
+	for i := range m.recentSessions {
+		if m.recentSessions[i].SessionID == msg.SessionID {
+			m.recentSessions[i].Favorite = msg.Favorite
+		}
+	}
+
+	var refresh tea.Cmd
+	if m.selectedProject != nil {
+		for i := range m.selectedProject.Sessions {
+			if m.selectedProject.Sessions[i].SessionID == msg.SessionID {
+				m.selectedProject.Sessions[i].Favorite = msg.Favorite
+			}
+		}
+		refresh = refreshSessionsCmd(m.ctx, m.selectedProject.Path, m.sessionOffset)
+	}
+
+	m.updateViewport()
+	status := "Removed from favorites"
+	if msg.Favorite {
+		status = "Added to favorites"
+	}
+	updated, flash := m.flashStatus(status)
+	return updated, tea.Batch(flash, refresh)
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// ansiPattern matches the escape sequences lipgloss emits for styling
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// renderHelp renders every keybinding of keyMap grouped by context, in two columns.
+// Bindings shared by most views are listed once under General.
+func (m model) renderHelp() string {
+	titleStyle := m.newStyle().
+		Bold(true).
+		Foreground(m.theme.Title)
+	keyStyle := m.newStyle().
+		Foreground(m.theme.Accent)
+	helpStyle := m.newStyle().
+		Foreground(lipgloss.Color("252"))
+
+	keyWidth := 0
+	for _, binding := range keyMap {
+		if w := runewidth.StringWidth(binding.keys); w > keyWidth {
+			keyWidth = w
+		}
+	}
+
+	group := func(title string, include func(keyBinding) bool) string {
+		var s strings.Builder
+		s.WriteString(titleStyle.Render(title) + "\n")
+		for _, binding := range keyMap {
+			if !include(binding) {
+				continue
+			}
+			keys := binding.keys + strings.Repeat(" ", keyWidth-runewidth.StringWidth(binding.keys))
+			s.WriteString(keyStyle.Render(keys) + "  " + helpStyle.Render(binding.help) + "\n")
+		}
+		return s.String()
+	}
+
+	groups := []string{group("General", func(b keyBinding) bool { return b.general })}
+	for _, kc := range keyContexts {
+		context := kc.context
+		groups = append(groups, group(kc.title, func(b keyBinding) bool {
+			return !b.general && b.has(context)
+		}))
+	}
+
+	// General, projects and sessions go on the left, the rest on the right
+	half := 3
+	left := lipgloss.JoinVertical(lipgloss.Left, groups[:half]...)
+	right := lipgloss.JoinVertical(lipgloss.Left, groups[half:]...)
+	columns := lipgloss.JoinHorizontal(lipgloss.Top, left, "    ", right)
+
+	hint := m.newStyle().
+		Foreground(lipgloss.Color("241")).
+		Render("Press any key to close")
+	return fmt.Sprintf("%s\n%s", columns, hint)
+}
+
+// renderHelpOverlay draws the help box centered over a dimmed copy of background
+func (m model) renderHelpOverlay(background string) string {
+	box := m.newStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.TitleBackground).
+		Padding(1, 2).
+		Render(m.renderHelp())
+	boxLines := strings.Split(box, "\n")
+	boxWidth := lipgloss.Width(box)
+
+	dimStyle := m.newStyle().
+		Foreground(lipgloss.Color("238"))
+
+	bgLines := strings.Split(ansiPattern.ReplaceAllString(background, ""), "\n")
+	for len(bgLines) < m.height {
+		bgLines = append(bgLines, "")
+	}
+
+	top := (len(bgLines) - len(boxLines)) / 2
+	if top < 0 {
+		top = 0
+	}
+	left := (m.width - boxWidth) / 2
+	if left < 0 {
+		left = 0
+	}
+
+	out := make([]string, len(bgLines))
+	for i, line := range bgLines {
+		if i < top || i >= top+len(boxLines) {
+			out[i] = dimStyle.Render(line)
+			continue
+		}
+		before := padToWidth(runewidth.Truncate(line, left, ""), left)
+		after := cutLeft(line, left+boxWidth)
+		out[i] = dimStyle.Render(before) + boxLines[i-top] + dimStyle.Render(after)
+	}
+	return strings.Join(out, "\n")
+}
+
+// padToWidth pads s with spaces to width cells
+func padToWidth(s string, width int) string {
+	if w := runewidth.StringWidth(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// cutLeft drops the first width cells of s
+func cutLeft(s string, width int) string {
+	w := 0
+	for i, r := range s {
+		if w >= width {
+			return s[i:]
+		}
+		w += runewidth.RuneWidth(r)
+	}
+	return ""
+}
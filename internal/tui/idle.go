@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// IdleTimeoutMsg is sent when Options.IdleTimeout may have elapsed since the last keypress
+type IdleTimeoutMsg time.Time
+
+// idleTimeoutCmd checks for inactivity once wait has passed
+func idleTimeoutCmd(wait time.Duration) tea.Cmd {
+	return tea.Tick(wait, func(t time.Time) tea.Msg {
+		return IdleTimeoutMsg(t)
+	})
+}
+
+// handleIdleTimeout quits without resuming anything when no key was pressed for
+// Options.IdleTimeout, and otherwise checks again when it would next elapse.
+// Keypresses only move lastKey, so a single timer runs at any time.
+func (m model) handleIdleTimeout(msg IdleTimeoutMsg) (tea.Model, tea.Cmd) {
+	idle := time.Time(msg).Sub(m.lastKey)
+	if idle < m.opts.IdleTimeout {
+		return m, idleTimeoutCmd(m.opts.IdleTimeout - idle)
+	}
+	m.cancel()
+	m.selectedSession = nil
+	m.stderrLines = append(m.stderrLines, fmt.Sprintf("Quit after %s without a keypress", m.opts.IdleTimeout))
+	return m, tea.Quit
+}
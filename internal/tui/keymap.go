@@ -0,0 +1,126 @@
+package tui
+
+import "strings"
+
+// keyContext identifies a set of keybindings that are active together
+type keyContext int
+
+const (
+	projectKeys keyContext = iota
+	sessionKeys
+	recentKeys
+	conversationKeys // Conversation and tool timeline views
+	confirmKeys
+	directoryKeys // Confirmation screen asking for an alternate directory
+	loadingKeys
+	errorKeys // Error banner shown over a view after a failed load
+)
+
+// keyContexts lists the contexts in the order the help overlay shows them
+var keyContexts = []struct {
+	context keyContext
+	title   string
+}{
+	{projectKeys, "Projects"},
+	{sessionKeys, "Sessions"},
+	{recentKeys, "Recent Sessions"},
+	{conversationKeys, "Conversation & Tools"},
+	{confirmKeys, "Resume Confirmation"},
+	{directoryKeys, "Missing Directory"},
+	{loadingKeys, "While Loading"},
+	{errorKeys, "Error Banner"},
+}
+
+// keyBinding documents a key for the help overlay and the footer
+type keyBinding struct {
+	keys     string // Keys as shown to the user
+	help     string // Description in the help overlay
+	short    string // Description in the footer, empty to leave it out
+	paging   bool   // Only shown in the footer while the list spans several pages
+	general  bool   // Listed once under General in the help overlay rather than per context
+	contexts []keyContext
+}
+
+// keyMap is the single list of keybindings; the help overlay and the footer are generated from it
+var keyMap = []keyBinding{
+	{keys: "↑/↓", help: "Move the cursor in lists (also k/j)", short: "navigate", general: true, contexts: []keyContext{projectKeys, sessionKeys, recentKeys}},
+	{keys: "g/G", help: "Jump to the first / last item (also home/end)", general: true, contexts: []keyContext{projectKeys, sessionKeys, recentKeys}},
+	{keys: "ctrl+u/ctrl+d", help: "Move half a page up / down", general: true, contexts: []keyContext{projectKeys, sessionKeys, recentKeys}},
+	{keys: "1-9", help: "Jump to the numbered item; type two digits quickly for 10 and up", general: true, contexts: []keyContext{projectKeys, sessionKeys}},
+	{keys: "pgup/pgdn", help: "Previous / next page", short: "page", paging: true, contexts: []keyContext{projectKeys, sessionKeys}},
+	{keys: "enter", help: "Show the project's sessions", short: "select", contexts: []keyContext{projectKeys}},
+	{keys: "enter", help: "Resume the session", short: "resume", contexts: []keyContext{sessionKeys, recentKeys}},
+	{keys: "r", help: "Show the most recent sessions across all projects", short: "recent", contexts: []keyContext{projectKeys}},
+	{keys: "ctrl+r", help: "Reload the projects and sessions, keeping the cursor on the same item", contexts: []keyContext{projectKeys, sessionKeys}},
+	{keys: "o", help: "Open the project directory in $EDITOR or the file manager (see --open-cmd)", contexts: []keyContext{projectKeys, sessionKeys, recentKeys}},
+	{keys: "v", help: "Read the full conversation", short: "view", contexts: []keyContext{sessionKeys}},
+	{keys: "t", help: "Show the timeline of tool calls", short: "tools", contexts: []keyContext{sessionKeys}},
+	{keys: "m", help: "Hide or show tool calls and results in previews", contexts: []keyContext{sessionKeys}},
+	{keys: "D", help: "Group the sessions by day, or list them without day headers", contexts: []keyContext{sessionKeys}},
+	{keys: "I", help: "Show whole session IDs when they fit, or shortened ones", contexts: []keyContext{sessionKeys}},
+	{keys: "s", help: "Star or unstar the session", short: "star", contexts: []keyContext{sessionKeys, recentKeys}},
+	{keys: "n", help: "Label the session with a nickname, shown in brackets and accepted by resume", contexts: []keyContext{sessionKeys}},
+	{keys: "y", help: "Copy the session ID to the clipboard", short: "copy ID", contexts: []keyContext{sessionKeys}},
+	{keys: "c", help: "Copy the command resuming the session to the clipboard", contexts: []keyContext{sessionKeys, recentKeys}},
+	{keys: "d", help: "Delete the session, moving it to the trash", short: "delete", contexts: []keyContext{sessionKeys}},
+	{keys: "u", help: "Undo the last delete, restoring the session from the trash", contexts: []keyContext{projectKeys, sessionKeys}},
+	{keys: "esc", help: "Back to the projects (also backspace)", short: "back", contexts: []keyContext{sessionKeys}},
+	{keys: "r/esc", help: "Back to the projects", short: "projects", contexts: []keyContext{recentKeys}},
+	{keys: "↑/↓/pgup/pgdn", help: "Scroll", short: "scroll", contexts: []keyContext{conversationKeys}},
+	{keys: "O", help: "Show the newest messages of the conversation first, or the oldest", contexts: []keyContext{conversationKeys}},
+	{keys: "e", help: "Expand the messages cut to --message-lines, or cut them again", contexts: []keyContext{conversationKeys}},
+	{keys: "esc", help: "Back to the sessions (also v/t)", short: "back", contexts: []keyContext{conversationKeys}},
+	{keys: "enter/y", help: "Resume the session", short: "resume", contexts: []keyContext{confirmKeys}},
+	{keys: "esc/n", help: "Back to the list", short: "cancel", contexts: []keyContext{confirmKeys}},
+	{keys: "enter", help: "Resume in the entered directory", short: "resume in directory", contexts: []keyContext{directoryKeys}},
+	{keys: "esc", help: "Back to the list", short: "cancel", contexts: []keyContext{directoryKeys}},
+	{keys: "ctrl+c", help: "Quit without resuming", short: "quit", contexts: []keyContext{directoryKeys}},
+	{keys: "esc", help: "Cancel loading", short: "cancel", contexts: []keyContext{loadingKeys}},
+	{keys: "r", help: "Retry the failed load", short: "retry", contexts: []keyContext{errorKeys}},
+	{keys: "esc", help: "Dismiss the error", short: "dismiss", contexts: []keyContext{errorKeys}},
+	{keys: "?", help: "Toggle this help", short: "help", general: true, contexts: []keyContext{projectKeys, sessionKeys, recentKeys, conversationKeys, loadingKeys, errorKeys}},
+	{keys: "q", help: "Quit (also ctrl+c)", short: "quit", general: true, contexts: []keyContext{projectKeys, sessionKeys, recentKeys, conversationKeys, confirmKeys, loadingKeys, errorKeys}},
+}
+
+// keyContext returns the keybindings active in the current state
+func (m model) keyContext() keyContext {
+	switch {
+	case m.currentMode == confirmView && m.dirPrompt:
+		return directoryKeys
+	case m.currentMode == confirmView:
+		return confirmKeys
+	case m.err != nil:
+		return errorKeys
+	case m.blockingLoad():
+		return loadingKeys
+	case m.currentMode == messageView || m.currentMode == toolView:
+		return conversationKeys
+	case m.currentMode == recentView:
+		return recentKeys
+	case m.currentMode == sessionView:
+		return sessionKeys
+	}
+	return projectKeys
+}
+
+// has reports whether the binding is active in context
+func (b keyBinding) has(keys keyContext) bool {
+	for _, c := range b.contexts {
+		if c == keys {
+			return true
+		}
+	}
+	return false
+}
+
+// footerHints returns the footer hints of a context, e.g. "enter: select • q: quit"
+func footerHints(keys keyContext, paged bool) string {
+	var hints []string
+	for _, binding := range keyMap {
+		if binding.short == "" || !binding.has(keys) || (binding.paging && !paged) {
+			continue
+		}
+		hints = append(hints, binding.keys+": "+binding.short)
+	}
+	return strings.Join(hints, " • ")
+}
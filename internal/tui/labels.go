@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// startLabel asks for a label of session in the footer, prefilled with its current one
+func (m model) startLabel(session models.Session) (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Prompt = "Label: "
+	input.Placeholder = "nickname, empty to remove"
+	input.CharLimit = 80
+	input.SetValue(session.Label)
+	input.Focus()
+
+	m.labelTarget = &session
+	m.labelInput = input
+	return m, textinput.Blink
+}
+
+// updateLabelPrompt handles keys while asking for a label
+func (m model) updateLabelPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		sessionID := m.labelTarget.SessionID
+		m.labelTarget = nil
+		return m, setLabelCmd(sessionID, m.labelInput.Value())
+	case "esc":
+		m.labelTarget = nil
+		m.statusMessage = "Label cancelled"
+		return m, nil
+	case "ctrl+c":
+		m.cancel()
+		return m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.labelInput, cmd = m.labelInput.Update(msg)
+	return m, cmd
+}
+
+// handleLabelSet shows the new label of a session in every list showing it
+func (m model) handleLabelSet(msg LabelSetMsg) (tea.Model, tea.Cmd) {
+	if msg.Error != nil {
+		return m.flashStatus("Label failed: " + msg.Error.Error())
+	}
+
+	for i := range m.recentSessions {
+		if m.recentSessions[i].SessionID == msg.SessionID {
+			m.recentSessions[i].Label = msg.Label
+		}
+	}
+	if m.selectedProject != nil {
+		for i := range m.selectedProject.Sessions {
+			if m.selectedProject.Sessions[i].SessionID == msg.SessionID {
+				m.selectedProject.Sessions[i].Label = msg.Label
+			}
+		}
+	}
+
+	m.updateViewport()
+	if msg.Label == "" {
+		return m.flashStatus("Label removed")
+	}
+	return m.flashStatus("Labeled " + msg.Label)
+}
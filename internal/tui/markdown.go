@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	headingPattern   = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	listItemPattern  = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	blockquotePrefix = regexp.MustCompile(`^\s*>\s?`)
+)
+
+// renderMarkdown renders the basic Markdown used in assistant answers: headings,
+// lists, block quotes, **bold**, `inline code` and fenced code blocks.
+// Text is wrapped to width; code blocks keep their indentation and are never wrapped.
+func (m model) renderMarkdown(text string, width int, base lipgloss.Style) []string {
+	var lines []string
+	var code []string
+	inCode := false
+	fence, lang := "", ""
+
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if inCode {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				lines = append(lines, m.renderCodeBlock(code, lang, width)...)
+				inCode, code = false, nil
+				continue
+			}
+			code = append(code, line)
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = true
+			fence = trimmed[:3]
+			lang = strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1]))
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			lines = append(lines, "")
+
+		case headingPattern.MatchString(trimmed):
+			match := headingPattern.FindStringSubmatch(trimmed)
+			style := base.Copy().Bold(true).Foreground(m.theme.Title)
+			if len(match[1]) == 1 {
+				style = style.Underline(true)
+			}
+			for _, wrapped := range wrapText(match[2], width) {
+				lines = append(lines, style.Render(stripInlineMarkers(wrapped)))
+			}
+
+		case listItemPattern.MatchString(line):
+			match := listItemPattern.FindStringSubmatch(line)
+			indent := strings.Repeat(" ", len(strings.ReplaceAll(match[1], "\t", "  ")))
+			marker := match[2]
+			if marker == "-" || marker == "*" || marker == "+" {
+				marker = "•"
+			}
+			prefix := indent + marker + " "
+			hanging := strings.Repeat(" ", lipgloss.Width(prefix))
+			for i, wrapped := range m.renderInline(match[3], width-lipgloss.Width(prefix), base) {
+				if i == 0 {
+					lines = append(lines, base.Render(prefix)+wrapped)
+				} else {
+					lines = append(lines, hanging+wrapped)
+				}
+			}
+
+		case blockquotePrefix.MatchString(line):
+			quote := blockquotePrefix.ReplaceAllString(line, "")
+			quoteStyle := base.Copy().Faint(true).Italic(true)
+			for _, wrapped := range wrapText(quote, width-2) {
+				lines = append(lines, quoteStyle.Render("│ "+stripInlineMarkers(wrapped)))
+			}
+
+		default:
+			lines = append(lines, m.renderInline(line, width, base)...)
+		}
+	}
+
+	// An unterminated fence still renders as code
+	if inCode {
+		lines = append(lines, m.renderCodeBlock(code, lang, width)...)
+	}
+
+	return lines
+}
+
+// renderCodeBlock renders a fenced code block inside a border. Lines keep their
+// indentation and are truncated rather than wrapped when wider than width.
+func (m model) renderCodeBlock(code []string, lang string, width int) []string {
+	maxWidth := width - 4 // Border and padding
+	if maxWidth < 10 {
+		maxWidth = 10
+	}
+
+	body := make([]string, len(code))
+	for i, line := range code {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		runes := []rune(line)
+		if len(runes) > maxWidth {
+			line = string(runes[:maxWidth-1]) + "…"
+		}
+		body[i] = line
+	}
+
+	style := m.newStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Foreground(lipgloss.Color("252")).
+		Padding(0, 1)
+
+	rendered := strings.Split(style.Render(strings.Join(body, "\n")), "\n")
+	if lang != "" {
+		labelStyle := m.newStyle().
+			Foreground(lipgloss.Color("240")).
+			Italic(true)
+		rendered = append([]string{labelStyle.Render(lang)}, rendered...)
+	}
+	return rendered
+}
+
+// renderInline wraps a paragraph line and styles its **bold** and `code` spans
+func (m model) renderInline(text string, width int, base lipgloss.Style) []string {
+	boldStyle := base.Copy().Bold(true)
+	codeStyle := m.newStyle().
+		Foreground(m.theme.Tool)
+
+	var lines []string
+	bold, code := false, false
+	for _, wrapped := range wrapText(text, width) {
+		var line strings.Builder
+		var span strings.Builder
+		flush := func() {
+			if span.Len() == 0 {
+				return
+			}
+			style := base
+			if code {
+				style = codeStyle
+			} else if bold {
+				style = boldStyle
+			}
+			line.WriteString(style.Render(span.String()))
+			span.Reset()
+		}
+
+		for i := 0; i < len(wrapped); i++ {
+			switch {
+			case wrapped[i] == '`':
+				flush()
+				code = !code
+			case !code && strings.HasPrefix(wrapped[i:], "**"):
+				flush()
+				bold = !bold
+				i++
+			default:
+				span.WriteByte(wrapped[i])
+			}
+		}
+		flush()
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// stripInlineMarkers removes bold and inline code markers from text
+func stripInlineMarkers(text string) string {
+	return strings.NewReplacer("**", "", "`", "").Replace(text)
+}
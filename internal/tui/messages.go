@@ -2,8 +2,11 @@ package tui
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/strrl/claude-resume/internal/sessions"
 	"github.com/strrl/claude-resume/pkg/models"
@@ -21,8 +24,9 @@ type (
 	// SQLProgressMsg provides progress updates for long-running queries
 	SQLProgressMsg struct {
 		RequestID string
-		Progress  float64
+		Progress  float64 // Percent done, negative when it can't be told
 		Message   string
+		next      tea.Cmd // Waits for the next message of the operation
 	}
 
 	// SQLCompletedMsg indicates a SQL operation has completed
@@ -38,65 +42,299 @@ type (
 		RequestID string
 	}
 
-	// ProjectsLoadedMsg contains loaded projects
+	// ProjectsLoadedMsg contains a loaded page of projects
 	ProjectsLoadedMsg struct {
-		Projects []models.Project
-		Error    error
+		Projects      []models.Project
+		Offset        int    // Position of the first project in the full listing
+		Total         int    // Number of projects across all pages
+		Refresh       bool   // Background reload in watch mode
+		EmptyGuidance string // Advice shown when there are no projects at all
+		ScanLimit     *sessions.ScanLimit // Set when only the newest session files were read
+		Error         error
 	}
 
-	// SessionsLoadedMsg contains loaded sessions
+	// SessionsLoadedMsg contains a loaded page of sessions
 	SessionsLoadedMsg struct {
+		ProjectPath string
+		Sessions    []models.Session
+		Offset      int  // Position of the first session in the full listing
+		Total       int  // Number of sessions across all pages
+		Refresh     bool // Background reload in watch mode
+		Error       error
+	}
+
+	// RecentSessionsLoadedMsg contains the most recent sessions across all projects
+	RecentSessionsLoadedMsg struct {
 		Sessions []models.Session
 		Error    error
 	}
 
-	// SummariesLoadedMsg contains loaded session summaries
+	// SummariesLoadedMsg contains a chunk of loaded session summaries. Resume
+	// links come with the last message, once all summaries have arrived.
 	SummariesLoadedMsg struct {
 		ProjectPath string
 		Summaries   map[string]string
+		Sources     map[string]models.SummarySource // sessionID -> where its summary comes from
+		ResumedFrom map[string]string               // sessionID -> session it was resumed from
 		Error       error
+		Next        tea.Cmd // Waits for the next chunk, nil after the last one
+	}
+
+	// ActiveSessionsMsg tells which of the listed sessions are still being
+	// written, see markActiveCmd
+	ActiveSessionsMsg struct {
+		SessionIDs []string        // Sessions probed
+		Active     map[string]bool // Those of SessionIDs found active
+		Error      error
 	}
 
 	// MessagesLoadedMsg contains loaded messages
 	MessagesLoadedMsg struct {
+		SessionID    string
+		Messages     []sessions.PreviewLine
+		MessageCount int
+		Usage        *sessions.SessionUsage
+		Detail       *models.SessionDetail
+		ModTime      time.Time // Session file modification time before loading, zero if unknown
+		MessagesOnly bool      // Preview was formatted without tool calls and results
+		Error        error
+	}
+
+	// ConversationLoadedMsg contains the full conversation of a session
+	ConversationLoadedMsg struct {
 		SessionID string
-		Messages  []string
+		Messages  []models.Message
+		Error     error
+	}
+
+	// ToolUsageLoadedMsg contains the tool invocations of a session
+	ToolUsageLoadedMsg struct {
+		SessionID string
+		ToolCalls []models.ToolCall
+		Error     error
+	}
+
+	// FavoriteToggledMsg reports the result of starring or unstarring a session
+	FavoriteToggledMsg struct {
+		SessionID string
+		Favorite  bool
+		Error     error
+	}
+
+	// LabelSetMsg reports the result of labeling a session
+	LabelSetMsg struct {
+		SessionID string
+		Label     string // Empty when the label was removed
+		Error     error
+	}
+
+	// SessionDeletedMsg reports the result of deleting a session
+	SessionDeletedMsg struct {
+		SessionID string
+		Error     error
+	}
+
+	// SessionRestoredMsg reports the result of restoring a session from the trash
+	SessionRestoredMsg struct {
+		SessionID string
+		Error     error
+	}
+
+	// ClipboardMsg reports the result of copying text to the clipboard
+	ClipboardMsg struct {
+		Text  string
+		Error error
+	}
+
+	// ClearStatusMsg clears the footer status if it is still the one identified by ID
+	ClearStatusMsg struct {
+		ID int
+	}
+
+	// WatchTickMsg triggers a check of the session files in watch mode
+	WatchTickMsg time.Time
+
+	// FilesCheckedMsg reports the latest modification time of the session files
+	FilesCheckedMsg struct {
+		ModTime   time.Time
+		CheckedAt time.Time
 		Error     error
 	}
 
+	// PreviewDebounceMsg is sent previewDebounce after the cursor moved onto a session
+	PreviewDebounceMsg struct {
+		SessionID string
+		Seq       int // Value of previewSeq when the cursor moved; stale if it changed since
+	}
+
+	// JumpTimeoutMsg is sent jumpTimeout after a digit was typed for a quick jump
+	JumpTimeoutMsg struct {
+		Seq int // Value of jumpSeq when the digit was typed; stale if another followed
+	}
+
 	// TickMsg is sent periodically for spinner animation
 	TickMsg time.Time
 )
 
+const (
+	// watchInterval is how often session files are checked in watch mode
+	watchInterval = time.Second
+	// watchMaxDelay bounds how long a refresh waits for writes to settle
+	watchMaxDelay = 5 * time.Second
+)
+
 // Commands for async operations
 
-// loadProjectsCmd loads projects asynchronously
-func loadProjectsCmd(ctx context.Context) tea.Cmd {
-	return func() tea.Msg {
-		projects, err := sessions.FetchProjectsWithStatsAsync(ctx)
-		return ProjectsLoadedMsg{
-			Projects: projects,
-			Error:    err,
+// loadProjectsCmd loads the page of projects starting at offset asynchronously.
+// The session files are enumerated first, and the progress of the enumeration
+// and then of the query is reported with SQLProgressMsg before the projects
+// arrive in a ProjectsLoadedMsg.
+func loadProjectsCmd(ctx context.Context, offset int) tea.Cmd {
+	results := make(chan tea.Msg, 1)
+	var wait tea.Cmd
+	wait = func() tea.Msg {
+		msg := <-results
+		if progress, ok := msg.(SQLProgressMsg); ok {
+			progress.next = wait
+			return progress
 		}
+		return msg
 	}
+
+	go func() {
+		// Progress is only a hint, so a report is dropped rather than holding up
+		// the scan while the previous one is still waiting to be shown
+		report := func(msg SQLProgressMsg) {
+			select {
+			case results <- msg:
+			default:
+			}
+		}
+		scanned, err := sessions.ScanSessionFiles(ctx, func(p sessions.ScanProgress) {
+			report(SQLProgressMsg{RequestID: "projects", Progress: p.Percent(), Message: p.Message()})
+		})
+		// The last report and the projects are always delivered, as every
+		// report is followed by waiting for the next message
+		if err == nil {
+			results <- SQLProgressMsg{RequestID: "projects", Progress: -1, Message: scanned.ReadingMessage()}
+		}
+		results <- fetchProjects(ctx, offset)
+	}()
+	return wait
 }
 
-// loadSessionsCmd loads sessions for a project asynchronously
-func loadSessionsCmd(ctx context.Context, projectPath string) tea.Cmd {
+// fetchProjects loads the page of projects starting at offset
+func fetchProjects(ctx context.Context, offset int) ProjectsLoadedMsg {
+	projects, total, err := sessions.FetchProjectsPageAsync(ctx, sessions.PageLimit(), offset)
+	msg := ProjectsLoadedMsg{
+		Projects: projects,
+		Offset:   offset,
+		Total:    total,
+		Error:    err,
+	}
+	if err == nil && total == 0 {
+		msg.EmptyGuidance = sessions.EmptyGuidance()
+	}
+	if limit, ok := sessions.ScanLimited(); ok && err == nil {
+		msg.ScanLimit = &limit
+	}
+	return msg
+}
+
+// loadSessionsCmd loads the page of a project's sessions starting at offset asynchronously
+func loadSessionsCmd(ctx context.Context, projectPath string, offset int) tea.Cmd {
 	return func() tea.Msg {
-		sessions, err := sessions.FetchSessionsForProjectAsync(ctx, projectPath)
+		projectSessions, total, err := sessions.FetchSessionsPageAsync(ctx, projectPath, sessions.PageLimit(), offset)
+		
+		// Favorites and labels are best-effort; an unreadable file only hides the markers
+		if err == nil {
+			_ = sessions.MarkFavorites(projectSessions)
+			_ = sessions.MarkLabels(projectSessions)
+		}
 		return SessionsLoadedMsg{
-			Sessions: sessions,
-			Error:    err,
+			ProjectPath: projectPath,
+			Sessions:    projectSessions,
+			Offset:      offset,
+			Total:       total,
+			Error:       err,
 		}
 	}
 }
 
+// markActiveCmd finds which of the listed sessions are still being written.
+// Probing them takes a moment, so the list is shown without waiting for it.
+func markActiveCmd(list []models.Session) tea.Cmd {
+	sessionIDs := make([]string, len(list))
+	for i, session := range list {
+		sessionIDs[i] = session.SessionID
+	}
+	probed := slices.Clone(list)
+	return func() tea.Msg {
+		active, err := sessions.ActiveSessions(probed)
+		return ActiveSessionsMsg{SessionIDs: sessionIDs, Active: active, Error: err}
+	}
+}
+
+// refreshProjectsCmd reloads the current page of projects in the background
+func refreshProjectsCmd(ctx context.Context, offset int) tea.Cmd {
+	return func() tea.Msg {
+		msg := fetchProjects(ctx, offset)
+		msg.Refresh = true
+		return msg
+	}
+}
+
+// refreshSessionsCmd reloads the current page of a project's sessions in the background
+func refreshSessionsCmd(ctx context.Context, projectPath string, offset int) tea.Cmd {
+	load := loadSessionsCmd(ctx, projectPath, offset)
+	return func() tea.Msg {
+		msg := load().(SessionsLoadedMsg)
+		msg.Refresh = true
+		return msg
+	}
+}
+
 // loadMessagesCmd loads messages for a session asynchronously
 func loadMessagesCmd(ctx context.Context, sessionID string) tea.Cmd {
 	return func() tea.Msg {
-		messages, err := sessions.FetchRecentMessagesForSessionAsync(ctx, sessionID)
+		// Taken before querying so that a write racing the query invalidates the preview
+		modTime, _ := sessions.SessionFileModTime(sessionID)
+		messagesOnly := sessions.MessagesOnly()
+		preview, err := sessions.FetchSessionPreviewAsync(ctx, sessionID)
+		if err != nil {
+			preview = &sessions.SessionPreview{}
+		}
+		
+		// Token usage is best-effort; a failure only hides the usage line
+		var usage *sessions.SessionUsage
+		if err == nil && ctx.Err() == nil {
+			usage, _ = sessions.FetchSessionUsage(sessionID)
+		}
+		
 		return MessagesLoadedMsg{
+			SessionID:    sessionID,
+			Messages:     preview.Messages,
+			MessageCount: preview.MessageCount,
+			Usage:        usage,
+			Detail:       preview.Detail,
+			ModTime:      modTime,
+			MessagesOnly: messagesOnly,
+			Error:        err,
+		}
+	}
+}
+
+// loadConversationCmd loads the full, untruncated conversation of a session
+func loadConversationCmd(ctx context.Context, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		messages, err := sessions.FetchMessages(sessionID)
+		if err == nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if messages == nil {
+			messages = []models.Message{}
+		}
+		return ConversationLoadedMsg{
 			SessionID: sessionID,
 			Messages:  messages,
 			Error:     err,
@@ -104,21 +342,167 @@ func loadMessagesCmd(ctx context.Context, sessionID string) tea.Cmd {
 	}
 }
 
-// loadSummariesCmd loads summaries for sessions asynchronously
+// loadToolUsageCmd loads the chronological tool invocations of a session
+func loadToolUsageCmd(ctx context.Context, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		calls, err := sessions.FetchToolUsage(sessionID)
+		if err == nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if calls == nil {
+			calls = []models.ToolCall{}
+		}
+		return ToolUsageLoadedMsg{
+			SessionID: sessionID,
+			ToolCalls: calls,
+			Error:     err,
+		}
+	}
+}
+
+// loadSummariesCmd loads summaries for sessions asynchronously. They arrive in
+// phases, each as a SummariesLoadedMsg, so that the list fills in progressively.
 func loadSummariesCmd(ctx context.Context, projectPath string, sessionIDs []string) tea.Cmd {
 	return func() tea.Msg {
-		summaries, err := sessions.FetchSessionSummariesAsync(ctx, projectPath, sessionIDs)
+		return nextSummariesCmd(ctx, projectPath, sessionIDs, sessions.StreamSessionSummaries(ctx, sessionIDs))()
+	}
+}
+
+// nextSummariesCmd waits for the next phase of summaries from stream. Once the
+// stream is done, the resume links of sessionIDs are loaded.
+func nextSummariesCmd(ctx context.Context, projectPath string, sessionIDs []string, stream <-chan sessions.SummaryChunk) tea.Cmd {
+	return func() tea.Msg {
+		if chunk, ok := <-stream; ok {
+			if chunk.Err != nil {
+				return SummariesLoadedMsg{ProjectPath: projectPath, Error: chunk.Err}
+			}
+			return SummariesLoadedMsg{
+				ProjectPath: projectPath,
+				Summaries:   chunk.Summaries,
+				Sources:     chunk.Sources,
+				Next:        nextSummariesCmd(ctx, projectPath, sessionIDs, stream),
+			}
+		}
+		if ctx.Err() != nil {
+			return SummariesLoadedMsg{ProjectPath: projectPath, Error: ctx.Err()}
+		}
+
+		// Resume links are best-effort; a failure only hides the indicator
+		resumedFrom, _ := sessions.FetchResumedFrom(sessionIDs)
 		return SummariesLoadedMsg{
 			ProjectPath: projectPath,
-			Summaries:   summaries,
-			Error:       err,
+			ResumedFrom: resumedFrom,
+		}
+	}
+}
+
+// deleteSessionCmd moves a session's events from disk to the trash
+func deleteSessionCmd(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		return SessionDeletedMsg{
+			SessionID: sessionID,
+			Error:     sessions.DeleteSession(sessionID),
+		}
+	}
+}
+
+// restoreSessionCmd moves a session from the trash back into its files
+func restoreSessionCmd(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := sessions.RestoreSession(sessionID)
+		return SessionRestoredMsg{
+			SessionID: sessionID,
+			Error:     err,
 		}
 	}
 }
 
+// toggleFavoriteCmd stars or unstars a session
+func toggleFavoriteCmd(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		favorite, err := sessions.ToggleFavorite(sessionID)
+		return FavoriteToggledMsg{
+			SessionID: sessionID,
+			Favorite:  favorite,
+			Error:     err,
+		}
+	}
+}
+
+// setLabelCmd gives a session a label, or removes its label when label is empty
+func setLabelCmd(sessionID, label string) tea.Cmd {
+	return func() tea.Msg {
+		label = strings.Join(strings.Fields(label), " ")
+		return LabelSetMsg{
+			SessionID: sessionID,
+			Label:     label,
+			Error:     sessions.SetLabel(sessionID, label),
+		}
+	}
+}
+
+// copyToClipboardCmd copies text to the system clipboard
+func copyToClipboardCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		return ClipboardMsg{
+			Text:  text,
+			Error: clipboard.WriteAll(text),
+		}
+	}
+}
+
+// copyResumeCommandCmd copies the shell command resuming session to the system
+// clipboard, for pasting into another terminal
+func copyResumeCommandCmd(session models.Session, extraArgs []string) tea.Cmd {
+	return copyToClipboardCmd(sessions.ResumeCommandLine(session.SessionID, session.ProjectPath, extraArgs...))
+}
+
+// clearStatusCmd clears the footer status after a short delay
+func clearStatusCmd(id int) tea.Cmd {
+	return tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+		return ClearStatusMsg{ID: id}
+	})
+}
+
+// watchTickCmd schedules the next session file check in watch mode
+func watchTickCmd() tea.Cmd {
+	return tea.Tick(watchInterval, func(t time.Time) tea.Msg {
+		return WatchTickMsg(t)
+	})
+}
+
+// checkFilesCmd looks up the latest modification time of the session files
+func checkFilesCmd() tea.Cmd {
+	return func() tea.Msg {
+		modTime, err := sessions.LatestModTime()
+		return FilesCheckedMsg{
+			ModTime:   modTime,
+			CheckedAt: time.Now(),
+			Error:     err,
+		}
+	}
+}
+
+// spinnerInterval is the time between two frames of the loading spinner
+const spinnerInterval = 100 * time.Millisecond
+
+// jumpTimeoutCmd reports when the digits typed for a quick jump should be resolved
+func jumpTimeoutCmd(seq int) tea.Cmd {
+	return tea.Tick(jumpTimeout, func(time.Time) tea.Msg {
+		return JumpTimeoutMsg{Seq: seq}
+	})
+}
+
+// previewDebounceCmd reports when the cursor may have rested on a session for previewDebounce
+func previewDebounceCmd(sessionID string, seq int) tea.Cmd {
+	return tea.Tick(previewDebounce, func(time.Time) tea.Msg {
+		return PreviewDebounceMsg{SessionID: sessionID, Seq: seq}
+	})
+}
+
 // tickCmd creates a ticker for spinner animation
 func tickCmd() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
+	return tea.Tick(spinnerInterval, func(t time.Time) tea.Msg {
 		return TickMsg(t)
 	})
 }
\ No newline at end of file
@@ -0,0 +1,279 @@
+package tui
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// sessionListHeaderLines is the number of lines above the first session in
+// renderSessionsList: the title, the divider and a blank line
+const sessionListHeaderLines = 3
+
+// handleNavigationKey moves the cursor of the project, session or recent list for
+// the up/down, jump and half-page keys. It returns false for any other key.
+func (m model) handleNavigationKey(key string) (tea.Model, tea.Cmd, bool) {
+	cursor, count := m.cursorPosition()
+	var target int
+	switch key {
+	case "up", "k":
+		target = cursor - 1
+	case "down", "j":
+		target = cursor + 1
+	case "g", "home":
+		target = 0
+	case "G", "end":
+		target = count - 1
+	case "ctrl+u":
+		target = cursor - m.halfPage()
+	case "ctrl+d":
+		target = cursor + m.halfPage()
+	default:
+		return m, nil, false
+	}
+
+	updated, cmd := m.moveCursor(target)
+	return updated, cmd, true
+}
+
+// jumpTimeout is how long a typed digit waits for a second one before the cursor jumps
+const jumpTimeout = 600 * time.Millisecond
+
+// handleJumpKey moves the cursor of the project or session list to the item with
+// the typed number. Digits are buffered so that two typed in quick succession
+// jump to a two-digit number; the buffer resolves on jumpTimeout, once no longer
+// number fits the list, or on the next other key, which is then handled as usual.
+// It returns false for keys it leaves to the caller.
+func (m model) handleJumpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	if m.currentMode != projectView && m.currentMode != sessionView {
+		return m, nil, false
+	}
+
+	key := msg.String()
+	if len(key) != 1 || key[0] < '0' || key[0] > '9' {
+		if m.jumpDigits == "" {
+			return m, nil, false
+		}
+		jumped, cmd := m.resolveJump()
+		updated, next := jumped.(model).Update(msg)
+		return updated, tea.Batch(cmd, next), true
+	}
+
+	if m.jumpDigits == "" && key == "0" {
+		return m, nil, true // Items are numbered from 1
+	}
+	m.jumpDigits += key
+	number, _ := strconv.Atoi(m.jumpDigits)
+	if _, count := m.cursorPosition(); len(m.jumpDigits) >= 2 || number*10 > count {
+		updated, cmd := m.resolveJump()
+		return updated, cmd, true
+	}
+
+	m.jumpSeq++
+	m.statusMessage = "Jump to " + m.jumpDigits + "…"
+	return m, jumpTimeoutCmd(m.jumpSeq), true
+}
+
+// resolveJump moves the cursor to the item numbered by the buffered digits and clears them
+func (m model) resolveJump() (tea.Model, tea.Cmd) {
+	number, _ := strconv.Atoi(m.jumpDigits)
+	m.jumpDigits = ""
+	m.statusMessage = ""
+	return m.moveCursor(number - 1)
+}
+
+// cursorPosition returns the cursor and the length of the list in the current view
+func (m model) cursorPosition() (int, int) {
+	switch m.currentMode {
+	case projectView:
+		return m.projectCursor, len(m.projects)
+	case recentView:
+		return m.recentCursor, len(m.recentSessions)
+	case sessionView:
+		if m.selectedProject != nil {
+			return m.sessionCursor, len(m.selectedProject.Sessions)
+		}
+	}
+	return 0, 0
+}
+
+// halfPage returns how many items half a page of the current list holds
+func (m model) halfPage() int {
+	items := m.viewport.Height / 2
+	if m.currentMode == projectView {
+		items /= m.projectItemLines()
+	}
+	if m.currentMode == sessionView {
+		// Sessions take a summary, date and ID line plus a separator
+		items = m.leftViewport.Height / 2 / 4
+	}
+	if items < 1 {
+		items = 1
+	}
+	return items
+}
+
+// moveCursor moves the cursor of the current list to index, clamped to the list.
+// In the session view the preview of the newly selected session is loaded.
+func (m model) moveCursor(index int) (tea.Model, tea.Cmd) {
+	cursor, count := m.cursorPosition()
+	if count == 0 {
+		return m, nil
+	}
+	if index >= count {
+		index = count - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index == cursor {
+		return m, nil
+	}
+
+	switch m.currentMode {
+	case projectView:
+		m.projectCursor = index
+	case recentView:
+		m.recentCursor = index
+	case sessionView:
+		m.sessionCursor = index
+		return m.loadCurrentSessionMessages()
+	}
+	m.updateViewport()
+	return m, nil
+}
+
+// previewDebounce is how long the cursor has to rest on a session before its preview is loaded
+const previewDebounce = 150 * time.Millisecond
+
+// blockingLoad reports whether a load in progress blocks navigation. Loading a
+// session preview doesn't, so that the cursor can move on while it loads.
+func (m model) blockingLoad() bool {
+	if m.currentMode == sessionView && m.loadingState == sessions.StateLoadingMessages {
+		return false
+	}
+	return m.loadingState != sessions.StateIdle
+}
+
+// loadCurrentSessionMessages shows the preview of the session under the cursor.
+// Unless it is cached, the preview is loaded once the cursor has rested on the
+// session for previewDebounce, so that scrolling quickly doesn't query every session.
+func (m model) loadCurrentSessionMessages() (tea.Model, tea.Cmd) {
+	session := m.selectedProject.Sessions[m.sessionCursor]
+
+	// Cancel any existing message fetch for previous session
+	for key, cancel := range m.activeRequests {
+		if strings.HasPrefix(key, "messages-") {
+			cancel()
+			delete(m.activeRequests, key)
+		}
+	}
+	// Previews being fetched or waiting for the cursor to rest are all abandoned
+	m.loadingMessages = make(map[string]bool)
+	m.previewSeq++
+
+	// Check cache first
+	if cached, ok := m.messageCache[session.SessionID]; ok {
+		m.currentMessages = cached
+		m.loadingState = sessions.StateIdle
+		m.updateViewport()
+		return m, nil
+	}
+
+	// Show the spinner right away but load the messages once the cursor rests
+	m.currentMessages = nil // Clear current messages
+	m.loadingState = sessions.StateLoadingMessages
+	m.loadingMessages[session.SessionID] = true
+	m.loadingIndicator.SetMessage("Loading messages...")
+
+	m.updateViewport()
+	return m, tea.Batch(previewDebounceCmd(session.SessionID, m.previewSeq), tickCmd())
+}
+
+// handlePreviewDebounce loads the preview of the session under the cursor if the
+// cursor has not moved since the debounce was scheduled
+func (m model) handlePreviewDebounce(msg PreviewDebounceMsg) (tea.Model, tea.Cmd) {
+	if msg.Seq != m.previewSeq || m.currentMode != sessionView || m.selectedProject == nil ||
+		m.sessionCursor >= len(m.selectedProject.Sessions) ||
+		m.selectedProject.Sessions[m.sessionCursor].SessionID != msg.SessionID {
+		return m, nil
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.activeRequests["messages-"+msg.SessionID] = cancel
+	return m, loadMessagesCmd(ctx, msg.SessionID)
+}
+
+// ensureCursorVisible scrolls the list viewport of the current view so that the
+// whole item under the cursor is shown
+func (m *model) ensureCursorVisible() {
+	switch m.currentMode {
+	case projectView:
+		lines := m.projectItemLines()
+		scrollIntoView(&m.viewport, m.projectCursor*lines, (m.projectCursor+1)*lines-1)
+	case recentView:
+		scrollIntoView(&m.viewport, m.recentCursor, m.recentCursor)
+	case sessionView:
+		if m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
+			first, last := m.sessionLines(m.sessionCursor)
+			scrollIntoView(&m.leftViewport, first, last)
+		}
+	}
+}
+
+// sessionLines returns the first and last line of a session in renderSessionsList,
+// including the day header above it when the list is grouped by day. The first
+// session starts at line 0 so that the list header scrolls back into view.
+func (m model) sessionLines(index int) (int, int) {
+	line := sessionListHeaderLines
+	for i := 0; i < index; i++ {
+		line += m.dayHeaderLines(i) + sessionItemLines(m.selectedProject.Sessions[i]) + 1 // Blank line between sessions
+	}
+	last := line + m.dayHeaderLines(index) + sessionItemLines(m.selectedProject.Sessions[index]) - 1
+	if index == 0 {
+		line = 0
+	}
+	return line, last
+}
+
+// dayHeaderLines returns how many lines the day header above a session takes
+func (m model) dayHeaderLines(index int) int {
+	if m.sessionDayHeader(index) != "" {
+		return 1
+	}
+	return 0
+}
+
+// projectItemLines returns how many lines renderProjects uses for a project
+func (m model) projectItemLines() int {
+	if m.opts.Verbose {
+		return 2 // Name line and latest session summary
+	}
+	return 1
+}
+
+// sessionItemLines returns how many lines renderSessionsList uses for a session
+func sessionItemLines(session models.Session) int {
+	if session.ResumedFrom != "" {
+		return 4
+	}
+	return 3
+}
+
+// scrollIntoView adjusts the viewport offset the least needed to show lines first through last
+func scrollIntoView(vp *viewport.Model, first, last int) {
+	if vp.Height <= 0 {
+		return
+	}
+	if first < vp.YOffset {
+		vp.SetYOffset(first)
+	} else if last >= vp.YOffset+vp.Height {
+		vp.SetYOffset(last - vp.Height + 1)
+	}
+}
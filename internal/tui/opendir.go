@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+// DirOpenedMsg reports that the command opening a project directory has finished
+type DirOpenedMsg struct {
+	Path  string
+	Error error
+}
+
+// openDirCommand returns the command opening dir: the --open-cmd command, else
+// $EDITOR, else the file manager of the OS. A {dir} in --open-cmd is replaced
+// by the directory, which is otherwise appended.
+func openDirCommand(openCmd, dir string) []string {
+	if fields := strings.Fields(openCmd); len(fields) > 0 {
+		placed := false
+		for i, field := range fields {
+			if strings.Contains(field, "{dir}") {
+				fields[i] = strings.ReplaceAll(field, "{dir}", dir)
+				placed = true
+			}
+		}
+		if !placed {
+			fields = append(fields, dir)
+		}
+		return fields
+	}
+	if editor := strings.Fields(os.Getenv("EDITOR")); len(editor) > 0 {
+		return append(editor, dir)
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"open", dir}
+	case "windows":
+		return []string{"explorer", dir}
+	default:
+		return []string{"xdg-open", dir}
+	}
+}
+
+// openProjectDir opens a project's directory for browsing its files. The TUI
+// is suspended while the command runs, so that terminal editors can take over
+// the screen. A directory that no longer exists is reported instead.
+func (m model) openProjectDir(projectPath string) (tea.Model, tea.Cmd) {
+	if projectPath == "" || projectPath == "Unknown" {
+		return m.flashStatus("No directory recorded for this project")
+	}
+	if !sessions.ProjectDirExists(projectPath) {
+		return m.flashStatus(fmt.Sprintf("%s no longer exists", projectPath))
+	}
+
+	argv := openDirCommand(m.opts.OpenCmd, projectPath)
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Dir = projectPath
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return DirOpenedMsg{Path: projectPath, Error: err}
+	})
+}
+
+// handleDirOpened reports a failure to open a project directory
+func (m model) handleDirOpened(msg DirOpenedMsg) (tea.Model, tea.Cmd) {
+	if msg.Error != nil {
+		return m.flashStatus("Open failed: " + msg.Error.Error())
+	}
+	return m, nil
+}
@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// enterRecentView switches to the flat list of recent sessions across all projects and reloads it
+func (m model) enterRecentView() (tea.Model, tea.Cmd) {
+	m.currentMode = recentView
+	m.recentCursor = 0
+	m.loadingState = sessions.StateLoadingSessions
+	m.loadingIndicator.SetMessage("Loading recent sessions...")
+	m.updateViewport()
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.activeRequests["recent"] = cancel
+	return m, tea.Batch(loadRecentSessionsCmd(ctx), tickCmd())
+}
+
+// leaveRecentView returns to the project list, loading it if it was never loaded
+func (m model) leaveRecentView() (tea.Model, tea.Cmd) {
+	m.currentMode = projectView
+	m.updateViewport()
+	if len(m.projects) > 0 {
+		return m, nil
+	}
+
+	m.loadingState = sessions.StateLoadingProjects
+	m.loadingIndicator.SetMessage("Loading projects...")
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.activeRequests["projects"] = cancel
+	return m, tea.Batch(loadProjectsCmd(ctx, 0), tickCmd())
+}
+
+// handleRecentSessionsLoaded shows the loaded recent sessions
+func (m model) handleRecentSessionsLoaded(msg RecentSessionsLoadedMsg) (tea.Model, tea.Cmd) {
+	delete(m.activeRequests, "recent")
+	if m.currentMode != recentView {
+		return m, nil
+	}
+	if errors.Is(msg.Error, context.Canceled) {
+		return m, nil // Cancelled with esc, which already reset the loading state
+	}
+	m.loadingState = sessions.StateIdle
+	if msg.Error != nil {
+		m.showError(msg.Error, retryRecent)
+		m.updateViewport()
+		return m, nil
+	}
+	m.recentSessions = msg.Sessions
+	m.recentCursor = 0
+	m.updateViewport()
+	return m, markActiveCmd(msg.Sessions)
+}
+
+// updateRecentView handles keys in the recent sessions view. Selecting a session
+// resumes it directly without going through its project.
+func (m model) updateRecentView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if updated, cmd, ok := m.handleNavigationKey(msg.String()); ok {
+		return updated, cmd
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.cancel()
+		return m, tea.Quit
+	case "enter":
+		if m.recentCursor < len(m.recentSessions) {
+			return m.selectSession(m.recentSessions[m.recentCursor])
+		}
+	case "s":
+		if m.recentCursor < len(m.recentSessions) {
+			return m, toggleFavoriteCmd(m.recentSessions[m.recentCursor].SessionID)
+		}
+	case "c":
+		if m.recentCursor < len(m.recentSessions) {
+			return m, copyResumeCommandCmd(m.recentSessions[m.recentCursor], m.opts.ExtraArgs)
+		}
+	case "o":
+		if m.recentCursor < len(m.recentSessions) {
+			return m.openProjectDir(m.recentSessions[m.recentCursor].ProjectPath)
+		}
+	case "r", "esc", "backspace":
+		return m.leaveRecentView()
+	}
+	return m, nil
+}
+
+// renderRecentSessions renders the recent sessions, each annotated with its project name
+func (m model) renderRecentSessions() string {
+	if m.loadingState == sessions.StateLoadingSessions {
+		return m.newStyle().Foreground(m.theme.Accent).Render(m.loadingIndicator.View())
+	}
+	if len(m.recentSessions) == 0 {
+		return m.newStyle().Foreground(lipgloss.Color("240")).Italic(true).Render("No sessions found")
+	}
+
+	projectStyle := m.newStyle().
+		Foreground(m.theme.Tool)
+
+	var s strings.Builder
+	for i, session := range m.recentSessions {
+		cursor := "  "
+		style := m.newStyle()
+		if i == m.recentCursor {
+			cursor = "> "
+			style = style.Foreground(m.theme.Accent).Bold(true)
+		}
+
+		summary := session.Summary
+		summaryStyle := style
+		if summary == "" {
+			summary = "No Summary"
+		} else if session.SummarySource.Generated() {
+			summaryStyle = style.Italic(true)
+		}
+		if session.IsResumed {
+			summary = "[Resumed] " + summary
+		}
+		if session.Label != "" {
+			summary = "[" + session.Label + "] " + summary
+		}
+		if session.Favorite {
+			summary = "★ " + summary
+		}
+		if session.Active {
+			summary = activeMarker + summary
+		}
+
+		suffix := fmt.Sprintf(" - Last Active: %s", m.formatTime(session.LastActivity))
+		if session.MultipleDirs {
+			suffix += " • " + multipleDirsMarker
+		}
+		project := fmt.Sprintf("[%s] ", sessions.ProjectName(session.ProjectPath))
+		summary = truncateSummary(summary, m.width-len(cursor)-len(project)-len(suffix))
+
+		s.WriteString(style.Render(cursor) + projectStyle.Render(project) + summaryStyle.Render(summary) + m.styleForAge(session.LastActivity).Render(suffix) + "\n")
+	}
+	return s.String()
+}
+
+// minTextWidth is the narrowest a line is cut to, so that a tiny terminal still shows something
+const minTextWidth = 20
+
+// truncateSummary shortens text to fit width terminal cells, ending it with "..."
+// when it is cut. Widths below minTextWidth are raised to it.
+func truncateSummary(summary string, width int) string {
+	if width < minTextWidth {
+		width = minTextWidth
+	}
+	return runewidth.Truncate(summary, width, "...")
+}
+
+// loadRecentSessionsCmd loads the most recent sessions across all projects
+func loadRecentSessionsCmd(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		recent, err := sessions.FetchRecentSessionsGlobal(sessions.PageLimit())
+		if err == nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if err == nil {
+			_ = sessions.MarkFavorites(recent) // Best-effort, like in the session list
+			_ = sessions.MarkLabels(recent)
+		}
+		if recent == nil {
+			recent = []models.Session{}
+		}
+		return RecentSessionsLoadedMsg{
+			Sessions: recent,
+			Error:    err,
+		}
+	}
+}
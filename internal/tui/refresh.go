@@ -0,0 +1,38 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/strrl/claude-resume/internal/sessions"
+)
+
+// refresh reloads the listings of the current view in the background, the way
+// watch mode does, so the cursor stays on the same project or session
+func (m model) refresh() (tea.Model, tea.Cmd) {
+	if m.loadingState != sessions.StateIdle || m.refreshing > 0 {
+		return m, nil
+	}
+
+	cmds := []tea.Cmd{refreshProjectsCmd(m.ctx, m.projectOffset)}
+	m.refreshing = 1
+	if m.currentMode == sessionView && m.selectedProject != nil {
+		cmds = append(cmds, refreshSessionsCmd(m.ctx, m.selectedProject.Path, m.sessionOffset))
+		m.refreshing++
+	}
+	cmds = append(cmds, tickCmd())
+	return m, tea.Batch(cmds...)
+}
+
+// refreshDone counts off a refresh that arrived. Failures of watch mode
+// refreshes go unreported, but a refresh the user asked for flashes its error.
+func (m model) refreshDone(err error) (model, tea.Cmd) {
+	if m.refreshing == 0 {
+		return m, nil
+	}
+	m.refreshing--
+	if err == nil {
+		return m, nil
+	}
+	m.statusID++
+	m.statusMessage = "Refresh failed: " + err.Error()
+	return m, clearStatusCmd(m.statusID)
+}
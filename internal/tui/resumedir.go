@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// selectSession selects a session for resuming. When its recorded project
+// directory no longer exists and no --cwd override was given, the confirmation
+// screen asks for an alternate directory even with NoConfirm.
+func (m model) selectSession(session models.Session) (tea.Model, tea.Cmd) {
+	m.selectedSession = &session
+	m.confirmReturn = m.currentMode
+	m.resumeDir = m.opts.ResumeDir
+	m.dirPrompt = m.resumeDir == "" && !sessions.ProjectDirExists(m.selectedSession.ProjectPath)
+	m.dirError = ""
+
+	if m.dirPrompt {
+		m.dirInput = newDirInput()
+		m.currentMode = confirmView
+		return m, textinput.Blink
+	}
+	if !m.opts.NoConfirm {
+		// Ask for confirmation before handing the terminal to claude
+		m.currentMode = confirmView
+		return m, nil
+	}
+	m.cancel() // Cancel context before quitting
+	return m, tea.Quit
+}
+
+// newDirInput creates the alternate directory input, prefilled with the current directory
+func newDirInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "directory to resume in"
+	if cwd, err := os.Getwd(); err == nil {
+		input.SetValue(cwd)
+	}
+	input.Focus()
+	return input
+}
+
+// updateDirPrompt handles keys while asking for an alternate directory
+func (m model) updateDirPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		dir := strings.TrimSpace(m.dirInput.Value())
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			m.dirError = fmt.Sprintf("%s is not a directory", dir)
+			return m, nil
+		}
+		m.resumeDir = dir
+		m.cancel() // Cancel context before quitting
+		return m, tea.Quit
+	case "esc":
+		m.selectedSession = nil
+		m.dirPrompt = false
+		m.currentMode = m.confirmReturn
+		m.updateViewport()
+		return m, nil
+	case "ctrl+c":
+		m.selectedSession = nil
+		m.cancel()
+		return m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.dirInput, cmd = m.dirInput.Update(msg)
+	m.dirError = ""
+	return m, cmd
+}
+
+// renderDirPrompt renders the part of the confirmation screen asking for an alternate directory
+func (m model) renderDirPrompt() string {
+	warnStyle := m.newStyle().
+		Foreground(lipgloss.Color("214"))
+	errorStyle := m.newStyle().
+		Foreground(lipgloss.Color("196"))
+
+	var s strings.Builder
+	s.WriteString(warnStyle.Render("The project directory no longer exists.") + "\n")
+	s.WriteString(warnStyle.Render("Resume in this directory instead:") + "\n")
+	s.WriteString(m.dirInput.View() + "\n")
+	if m.dirError != "" {
+		s.WriteString(errorStyle.Render(m.dirError) + "\n")
+	}
+	return s.String()
+}
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/strrl/claude-resume/internal/paths"
+)
+
+// LastSelection is the project and session under the cursor when the TUI last
+// exited. The next run starts with the cursor on them.
+type LastSelection struct {
+	ProjectPath string `json:"projectPath"`
+	SessionID   string `json:"sessionId,omitempty"`
+}
+
+// selectionPath returns the location of the last selection file, next to the preview cache
+func selectionPath() (string, error) {
+	cacheDir, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "selection.json"), nil
+}
+
+// loadSelection reads the last selection from path. A missing file is an empty selection.
+func loadSelection(path string) (LastSelection, error) {
+	var selection LastSelection
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return selection, nil
+	}
+	if err != nil {
+		return selection, fmt.Errorf("failed to read last selection: %w", err)
+	}
+	if err := json.Unmarshal(data, &selection); err != nil {
+		return LastSelection{}, fmt.Errorf("failed to parse last selection %s: %w", path, err)
+	}
+	return selection, nil
+}
+
+// saveSelection writes selection to path
+func saveSelection(path string, selection LastSelection) error {
+	data, err := json.Marshal(selection)
+	if err != nil {
+		return fmt.Errorf("failed to encode last selection: %w", err)
+	}
+	if err := paths.MkdirFor(path); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write last selection: %w", err)
+	}
+	return nil
+}
+
+// currentSelection returns the project and session under the cursor, or the
+// session picked for resuming. It returns false when nothing is selected.
+func (m model) currentSelection() (LastSelection, bool) {
+	if m.selectedSession != nil {
+		return LastSelection{ProjectPath: m.selectedSession.ProjectPath, SessionID: m.selectedSession.SessionID}, true
+	}
+	switch m.currentMode {
+	case recentView:
+		if m.recentCursor < len(m.recentSessions) {
+			session := m.recentSessions[m.recentCursor]
+			return LastSelection{ProjectPath: session.ProjectPath, SessionID: session.SessionID}, true
+		}
+	case projectView:
+		if m.projectCursor < len(m.projects) {
+			return LastSelection{ProjectPath: m.projects[m.projectCursor].Path}, true
+		}
+	default:
+		if m.selectedProject == nil {
+			return LastSelection{}, false
+		}
+		selection := LastSelection{ProjectPath: m.selectedProject.Path}
+		if m.sessionCursor < len(m.selectedProject.Sessions) {
+			selection.SessionID = m.selectedProject.Sessions[m.sessionCursor].SessionID
+		}
+		return selection, true
+	}
+	return LastSelection{}, false
+}
+
+// restoreProjectCursor moves the project cursor to the project of the last
+// selection, if it is among the loaded projects
+func (m *model) restoreProjectCursor() {
+	if m.restore == nil {
+		return
+	}
+	for i, project := range m.projects {
+		if project.Path == m.restore.ProjectPath {
+			m.projectCursor = i
+			return
+		}
+	}
+}
+
+// restoreSessionCursor moves the session cursor to the session of the last
+// selection when its project's sessions have loaded. The last selection is
+// forgotten once any project is opened, so it is restored at most once.
+func (m *model) restoreSessionCursor(projectPath string) {
+	restore := m.restore
+	m.restore = nil
+	if restore == nil || restore.ProjectPath != projectPath || m.selectedProject == nil {
+		return
+	}
+	for i, session := range m.selectedProject.Sessions {
+		if session.SessionID == restore.SessionID {
+			m.sessionCursor = i
+			return
+		}
+	}
+}
@@ -37,22 +37,35 @@ type LoadingIndicator struct {
 	message  string
 	progress float64
 	showProgress bool
+	renderer *lipgloss.Renderer
 }
 
 // NewLoadingIndicator creates a new loading indicator
 func NewLoadingIndicator(message string) *LoadingIndicator {
 	return &LoadingIndicator{
-		spinner: NewSpinner(),
-		message: message,
+		spinner:  NewSpinner(),
+		message:  message,
+		renderer: lipgloss.DefaultRenderer(),
 	}
 }
 
+// SetRenderer sets the renderer used to style the indicator
+func (l *LoadingIndicator) SetRenderer(renderer *lipgloss.Renderer) {
+	l.renderer = renderer
+}
+
 // SetProgress sets the progress percentage (0-100)
 func (l *LoadingIndicator) SetProgress(progress float64) {
 	l.progress = progress
 	l.showProgress = true
 }
 
+// ClearProgress hides the progress bar, leaving the spinner alone
+func (l *LoadingIndicator) ClearProgress() {
+	l.progress = 0
+	l.showProgress = false
+}
+
 // SetMessage updates the loading message
 func (l *LoadingIndicator) SetMessage(message string) {
 	l.message = message
@@ -65,16 +78,16 @@ func (l *LoadingIndicator) Tick() {
 
 // View renders the loading indicator
 func (l *LoadingIndicator) View() string {
-	spinnerStyle := lipgloss.NewStyle().
+	spinnerStyle := l.renderer.NewStyle().
 		Foreground(lipgloss.Color("212"))
 
-	messageStyle := lipgloss.NewStyle().
+	messageStyle := l.renderer.NewStyle().
 		Foreground(lipgloss.Color("250"))
 
 	var content string
 	if l.showProgress {
 		// Show progress bar
-		progressBar := renderProgressBar(l.progress, 20)
+		progressBar := renderProgressBar(l.renderer, l.progress, 20)
 		content = fmt.Sprintf("%s %s %s (%.0f%%)",
 			spinnerStyle.Render(l.spinner.View()),
 			messageStyle.Render(l.message),
@@ -91,7 +104,7 @@ func (l *LoadingIndicator) View() string {
 }
 
 // renderProgressBar creates a simple progress bar
-func renderProgressBar(progress float64, width int) string {
+func renderProgressBar(renderer *lipgloss.Renderer, progress float64, width int) string {
 	if progress < 0 {
 		progress = 0
 	}
@@ -102,10 +115,10 @@ func renderProgressBar(progress float64, width int) string {
 	filled := int(float64(width) * progress / 100)
 	empty := width - filled
 
-	barStyle := lipgloss.NewStyle().
+	barStyle := renderer.NewStyle().
 		Foreground(lipgloss.Color("42"))
 
-	emptyStyle := lipgloss.NewStyle().
+	emptyStyle := renderer.NewStyle().
 		Foreground(lipgloss.Color("238"))
 	
 	return barStyle.Render(strings.Repeat("█", filled)) + 
@@ -117,7 +130,7 @@ func LoadingOverlay(width, height int, indicator *LoadingIndicator) string {
 	content := indicator.View()
 	
 	// Add cancel hint
-	cancelHint := lipgloss.NewStyle().
+	cancelHint := indicator.renderer.NewStyle().
 		Foreground(lipgloss.Color("241")).
 		Render("[ESC to cancel]")
 	
@@ -125,7 +138,7 @@ func LoadingOverlay(width, height int, indicator *LoadingIndicator) string {
 	fullContent := fmt.Sprintf("%s\n\n%s", content, cancelHint)
 	
 	// Center the content
-	style := lipgloss.NewStyle().
+	style := indicator.renderer.NewStyle().
 		Width(width).
 		Height(height).
 		Align(lipgloss.Center, lipgloss.Center)
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme defines the accent colors used throughout the TUI
+type Theme struct {
+	Accent          lipgloss.Color // Cursor highlight and spinners
+	Title           lipgloss.Color // Headers and titles
+	TitleBackground lipgloss.Color // Title bar background and borders
+	User            lipgloss.Color // User role label
+	Assistant       lipgloss.Color // Assistant role label
+	Tool            lipgloss.Color // Tool calls and commands
+}
+
+var themes = map[string]Theme{
+	"default": {
+		Accent:          "212",
+		Title:           "229",
+		TitleBackground: "63",
+		User:            "39",
+		Assistant:       "213",
+		Tool:            "220",
+	},
+	"ocean": {
+		Accent:          "45",
+		Title:           "195",
+		TitleBackground: "24",
+		User:            "81",
+		Assistant:       "141",
+		Tool:            "214",
+	},
+	"forest": {
+		Accent:          "114",
+		Title:           "193",
+		TitleBackground: "22",
+		User:            "150",
+		Assistant:       "179",
+		Tool:            "221",
+	},
+	"mono": {
+		Accent:          "255",
+		Title:           "255",
+		TitleBackground: "238",
+		User:            "252",
+		Assistant:       "250",
+		Tool:            "248",
+	},
+}
+
+// ThemeNames returns the names of the available themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// themeByName returns the named theme, falling back to the default theme
+func themeByName(name string) Theme {
+	if theme, ok := themes[name]; ok {
+		return theme
+	}
+	return themes["default"]
+}
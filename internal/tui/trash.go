@@ -0,0 +1,33 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// undoDelete restores the session the last delete of this run moved to the trash
+func (m model) undoDelete() (tea.Model, tea.Cmd) {
+	if m.lastDeleted == "" {
+		return m.flashStatus("Nothing to undo")
+	}
+	m.statusMessage = "Restoring session..."
+	return m, restoreSessionCmd(m.lastDeleted)
+}
+
+// handleSessionRestored reloads the listings in the background once a deleted
+// session is back in its files, so that it reappears in them
+func (m model) handleSessionRestored(msg SessionRestoredMsg) (tea.Model, tea.Cmd) {
+	if msg.Error != nil {
+		return m.flashStatus("Undo failed: " + msg.Error.Error())
+	}
+	if msg.SessionID == m.lastDeleted {
+		m.lastDeleted = ""
+	}
+
+	updated, flash := m.flashStatus("Session restored")
+	m = updated.(model)
+	cmds := []tea.Cmd{flash, refreshProjectsCmd(m.ctx, m.projectOffset)}
+	if m.currentMode == sessionView && m.selectedProject != nil {
+		cmds = append(cmds, refreshSessionsCmd(m.ctx, m.selectedProject.Path, m.sessionOffset))
+	}
+	return m, tea.Batch(cmds...)
+}
@@ -2,13 +2,19 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/strrl/claude-resume/internal/cache"
 	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/internal/timefmt"
 	"github.com/strrl/claude-resume/pkg/models"
 )
 
@@ -17,9 +23,46 @@ type viewMode int
 const (
 	projectView viewMode = iota
 	sessionView
+	confirmView
+	messageView // Full conversation of a session
+	toolView    // Tool invocation timeline of a session
+	recentView  // Most recent sessions across all projects
 )
 
+// Options configures the behavior of the TUI
+type Options struct {
+	NoConfirm  bool     // Resume immediately on enter instead of showing a confirmation screen
+	ExtraArgs  []string // Extra arguments forwarded to claude when resuming
+	DateFormat string   // Go time layout for timestamps, defaults to "Jan 02 15:04 MST"
+	TimeFormat string   // Timestamp display mode, see timefmt.Modes; defaults to absolute
+	Theme      string   // Name of the color theme, see ThemeNames
+	Watch      bool     // Refresh the listings when session files change
+	NoMarkdown bool     // Show conversations as plain text instead of rendering Markdown
+	NoCache    bool     // Don't read or write the on-disk message preview cache
+	NoColor    bool     // Render every style as plain text
+	ResumeDir  string   // Resume in this directory instead of the session's project directory
+	Recent     bool     // Start in the recent sessions view instead of the project list
+	Verbose    bool     // Show the latest session's summary under each project
+
+	NoResumePosition bool // Start at the top instead of on the last selected project and session
+
+	OpenCmd   string        // Command opening a project directory on o, defaults to $EDITOR or the file manager
+	FreshAge  time.Duration // Sessions active more recently are green, defaults to that of config.Default
+	RecentAge time.Duration // Sessions active more recently are yellow, defaults to that of config.Default
+
+	GroupByDay bool // Separate the session list into days with date headers, toggled with D
+	FullIDs    bool // Show whole session IDs in the session list when they fit, toggled with I
+
+	NewestFirst  bool // List the full conversation newest message first, toggled with O
+	MessageLines int  // Show at most this many lines of each message in the conversation until expanded with e, 0 for all
+
+	IdleTimeout time.Duration // Quit without resuming after this long without a keypress, never when 0
+}
+
 type model struct {
+	opts            Options
+	theme           Theme
+	renderer        *lipgloss.Renderer // Creates every style, see newStyle
 	projects        []models.Project
 	currentMode     viewMode
 	projectCursor   int
@@ -29,9 +72,48 @@ type model struct {
 	viewport        viewport.Model
 	leftViewport    viewport.Model  // For sessions list in split view
 	rightViewport   viewport.Model  // For messages preview in split view
-	currentMessages []string        // Cache for current session messages
+	currentMessages []sessions.PreviewLine        // Cache for current session messages
 	ready           bool
-	err             error
+	err             error           // Shown in the error banner until dismissed, see errorbanner.go
+	retry           retryFunc       // Repeats the load that failed with err
+	pendingDelete   *models.Session // Session awaiting delete confirmation
+	labelTarget     *models.Session // Session whose label is being typed, see labels.go
+	labelInput      textinput.Model
+	lastDeleted     string          // Session the last delete moved to the trash, restored with u
+	statusMessage   string          // Transient status shown in the footer
+	statusID        int             // Incremented on each flashed status so stale clears are ignored
+	refreshing      int             // Refreshes asked for with ctrl+r still in flight, see refresh.go
+	lastTick        time.Time       // Time of the last spinner frame, see TickMsg
+	lastKey         time.Time       // Time of the last keypress, see IdleTimeoutMsg
+	previewSeq      int             // Incremented on each cursor move so stale preview debounces are ignored
+	jumpDigits      string          // Digits typed for a quick jump, see handleJumpKey
+	jumpSeq         int             // Incremented on each typed digit so stale jump timeouts are ignored
+	showHelp        bool            // Help overlay is shown over the current view
+	resumeDir       string          // Directory to resume in when it differs from the project directory
+	dirPrompt       bool            // Confirmation screen is asking for an alternate directory
+	dirInput        textinput.Model // Alternate directory input, see resumedir.go
+	dirError        string          // Why the entered directory was rejected
+	confirmReturn   viewMode        // View the confirmation screen returns to when cancelled
+	recentSessions  []models.Session // Shown in recentView, see recent.go
+	recentCursor    int
+	stderrLines     []string        // Printed to stderr once the TUI has exited
+	projectOffset   int             // Position of the first loaded project in the full listing
+	projectTotal    int             // Number of projects across all pages
+	emptyGuidance   string          // Advice shown in place of an empty project list
+	scanLimit       *sessions.ScanLimit // Set when only the newest session files are read
+	sessionOffset   int             // Position of the first loaded session in the full listing
+	sessionTotal    int             // Number of sessions of the selected project across all pages
+	watchModTime    time.Time       // Session file modification time the listings reflect
+	watchPending    time.Time       // Newer modification time waiting for writes to settle
+	watchSince      time.Time       // When watchPending was first seen
+	restore         *LastSelection  // Selection of the previous run to put the cursor on, see selection.go
+	
+	// Full conversation and tool timeline views
+	conversation    []models.Message  // Full conversation shown in messageView, nil while loading
+	toolCalls       []models.ToolCall // Tool timeline shown in toolView, nil while loading
+	conversationID  string            // Session shown in messageView or toolView
+	conversationErr error
+	expandMessages  bool // Show messages cut to Options.MessageLines in full, toggled with e
 	width           int
 	height          int
 	
@@ -43,8 +125,12 @@ type model struct {
 	cancel          context.CancelFunc
 	
 	// Message cache: sessionID -> messages
-	messageCache    map[string][]string
+	messageCache    map[string][]sessions.PreviewLine
+	usageCache      map[string]*sessions.SessionUsage // sessionID -> token usage
+	detailCache     map[string]*models.SessionDetail  // sessionID -> detail header of the preview
+	messageCounts   map[string]int                    // sessionID -> total message count
 	loadingMessages map[string]bool  // Track which sessions are currently loading
+	diskCache       *cache.Store     // Previews persisted across runs, nil when disabled
 	
 	// Initial command to run on startup
 	initialCmd tea.Cmd
@@ -53,6 +139,8 @@ type model struct {
 func initialModel(projects []models.Project) model {
 	ctx, cancel := context.WithCancel(context.Background())
 	return model{
+		theme:         themeByName("default"),
+		renderer:      lipgloss.DefaultRenderer(),
 		projects:      projects,
 		currentMode:   projectView,
 		projectCursor: 0,
@@ -62,7 +150,10 @@ func initialModel(projects []models.Project) model {
 		activeRequests: make(map[string]context.CancelFunc),
 		ctx:           ctx,
 		cancel:        cancel,
-		messageCache:  make(map[string][]string),
+		messageCache:  make(map[string][]sessions.PreviewLine),
+		usageCache:    make(map[string]*sessions.SessionUsage),
+		detailCache:   make(map[string]*models.SessionDetail),
+		messageCounts: make(map[string]int),
 		loadingMessages: make(map[string]bool),
 	}
 }
@@ -86,36 +177,93 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// Returning from the program opening a directory counts as activity too
+	switch msg.(type) {
+	case tea.KeyMsg, DirOpenedMsg:
+		m.lastKey = time.Now()
+	}
+
 	switch msg := msg.(type) {
 	case TickMsg:
+		// Every load starts its own tick loop; a tick arriving before the next frame
+		// is due belongs to a redundant loop, which is ended here so that a single
+		// loop drives the spinner at its normal speed
+		if time.Time(msg).Sub(m.lastTick) < spinnerInterval*9/10 {
+			return m, nil
+		}
+		m.lastTick = time.Time(msg)
+		
 		// Update spinner animation for any loading state
-		if m.loadingState != sessions.StateIdle || len(m.loadingMessages) > 0 {
+		if m.loadingState != sessions.StateIdle || len(m.loadingMessages) > 0 || m.refreshing > 0 {
 			m.loadingIndicator.Tick()
 			m.updateViewport() // Update viewport to show spinner animation
 			cmds = append(cmds, tickCmd())
 		}
 		return m, tea.Batch(cmds...)
 	
+	case WatchTickMsg:
+		return m, checkFilesCmd()
+	
+	case IdleTimeoutMsg:
+		return m.handleIdleTimeout(msg)
+	
+	case FilesCheckedMsg:
+		return m.handleFilesChecked(msg)
+	
+	case SQLProgressMsg:
+		// Reports arriving once the projects are no longer loading are ignored,
+		// but the load is still waited for
+		if m.loadingState == sessions.StateLoadingProjects {
+			if msg.Progress < 0 {
+				m.loadingIndicator.ClearProgress()
+			} else {
+				m.loadingIndicator.SetProgress(msg.Progress)
+			}
+			m.loadingIndicator.SetMessage(msg.Message)
+			m.updateViewport()
+		}
+		return m, msg.next
+	
 	case ProjectsLoadedMsg:
+		if msg.Refresh {
+			return m.applyProjectsRefresh(msg).refreshDone(msg.Error)
+		}
 		m.loadingState = sessions.StateIdle
+		m.loadingIndicator.ClearProgress()
 		if msg.Error != nil {
-			m.err = msg.Error
+			m.showError(msg.Error, retryProjects(msg.Offset))
 		} else {
 			m.projects = msg.Projects
+			m.projectOffset = msg.Offset
+			m.projectTotal = msg.Total
+			m.emptyGuidance = msg.EmptyGuidance
+			m.scanLimit = msg.ScanLimit
+			m.projectCursor = 0
+			m.restoreProjectCursor()
 			m.updateViewport()
 		}
 		return m, nil
 	
 	case SessionsLoadedMsg:
+		if msg.Refresh {
+			m, done := m.refreshDone(msg.Error)
+			updated, cmd := m.applySessionsRefresh(msg)
+			return updated, tea.Batch(cmd, done)
+		}
 		if msg.Error != nil {
 			m.loadingState = sessions.StateIdle
-			m.err = msg.Error
+			m.showError(msg.Error, retrySessions(msg.ProjectPath, msg.Offset))
+			m.updateViewport()
 		} else if m.selectedProject != nil {
 			m.selectedProject.Sessions = msg.Sessions
+			m.sessionOffset = msg.Offset
+			m.sessionTotal = msg.Total
 			m.currentMode = sessionView
 			m.sessionCursor = 0
+			m.restoreSessionCursor(msg.ProjectPath)
 			m.loadingState = sessions.StateIdle // Sessions loaded, set to idle first
 			m.updateViewport() // Update the view to show sessions
+			cmds = append(cmds, markActiveCmd(msg.Sessions))
 			
 			// Load summaries for all sessions asynchronously
 			if len(msg.Sessions) > 0 {
@@ -130,14 +278,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmds = append(cmds, loadSummariesCmd(ctx, m.selectedProject.Path, sessionIDs))
 			}
 			
-			// Load messages for the first session
-			if len(msg.Sessions) > 0 {
-				session := msg.Sessions[0]
+			// Load messages for the session under the cursor
+			if m.sessionCursor < len(msg.Sessions) {
+				session := msg.Sessions[m.sessionCursor]
 				// Check cache first
 				if cached, ok := m.messageCache[session.SessionID]; ok {
 					m.currentMessages = cached
 				} else {
-					m.currentMessages = []string{} // Clear messages while loading
+					m.currentMessages = nil // Clear messages while loading
 					m.loadingState = sessions.StateLoadingMessages
 					m.loadingMessages[session.SessionID] = true
 					m.loadingIndicator.SetMessage("Loading messages...")
@@ -152,6 +300,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Batch(cmds...)
 	
+	case RecentSessionsLoadedMsg:
+		return m.handleRecentSessionsLoaded(msg)
+	
+	case ActiveSessionsMsg:
+		// Best-effort like the other markers; an error leaves them as they were
+		if msg.Error == nil {
+			m.applyActive(msg)
+		}
+		return m, nil
+	
 	case SummariesLoadedMsg:
 		// Update session summaries when they arrive
 		if msg.Error == nil && m.selectedProject != nil {
@@ -159,14 +317,41 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			for i := range m.selectedProject.Sessions {
 				if summary, ok := msg.Summaries[m.selectedProject.Sessions[i].SessionID]; ok {
 					m.selectedProject.Sessions[i].Summary = summary
+					m.selectedProject.Sessions[i].SummarySource = msg.Sources[m.selectedProject.Sessions[i].SessionID]
+				}
+				if parent, ok := msg.ResumedFrom[m.selectedProject.Sessions[i].SessionID]; ok {
+					m.selectedProject.Sessions[i].ResumedFrom = parent
 				}
 			}
 			// Update the viewport to show the summaries
 			m.updateViewport()
 		}
+		// Keep reading chunks only while the project they belong to is shown
+		if msg.Next != nil && m.selectedProject != nil && m.selectedProject.Path == msg.ProjectPath {
+			return m, msg.Next
+		}
 		return m, nil
 	
+	case PreviewDebounceMsg:
+		return m.handlePreviewDebounce(msg)
+	
+	case JumpTimeoutMsg:
+		if msg.Seq != m.jumpSeq || m.jumpDigits == "" {
+			return m, nil
+		}
+		return m.resolveJump()
+	
 	case MessagesLoadedMsg:
+		// A preview formatted before the tool call toggle has been reloaded already
+		if msg.MessagesOnly != sessions.MessagesOnly() {
+			return m, nil
+		}
+		// Cancelled when the cursor moved on, which already cleared its loading state
+		if errors.Is(msg.Error, context.Canceled) {
+			return m, nil
+		}
+		delete(m.activeRequests, "messages-"+msg.SessionID)
+		
 		// Mark this session as no longer loading
 		if msg.SessionID != "" {
 			delete(m.loadingMessages, msg.SessionID)
@@ -174,10 +359,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		
 		// Cache the messages
 		if msg.Error == nil {
-			if len(msg.Messages) == 0 {
-				m.messageCache[msg.SessionID] = []string{"No messages found for this session"}
-			} else {
-				m.messageCache[msg.SessionID] = msg.Messages
+			m.cacheMessages(msg.SessionID, msg.Messages, msg.MessageCount, msg.Usage, msg.Detail)
+			if m.diskCache != nil && !msg.ModTime.IsZero() {
+				m.diskCache.Put(msg.SessionID, cache.Entry{
+					ModTime:      msg.ModTime,
+					Messages:     msg.Messages,
+					MessageCount: msg.MessageCount,
+					Usage:        msg.Usage,
+					Detail:       msg.Detail,
+					MessagesOnly: msg.MessagesOnly,
+					MessageTypes: sessions.MessageTypesKey(),
+				})
 			}
 			
 			// Always update current messages if this is the selected session
@@ -190,11 +382,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		} else {
-			// On error, show error message if this is still the selected session
+			// On error, show the banner if this is still the selected session
 			if m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
 				currentSession := m.selectedProject.Sessions[m.sessionCursor]
 				if currentSession.SessionID == msg.SessionID {
-					m.currentMessages = []string{fmt.Sprintf("Error loading messages: %v", msg.Error)}
+					m.showError(msg.Error, retryPreview)
 				}
 			}
 		}
@@ -207,6 +399,97 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateViewport()
 		return m, nil
 	
+	case ConversationLoadedMsg:
+		if !m.conversationFor(msg.SessionID) {
+			return m, nil
+		}
+		delete(m.activeRequests, "conversation")
+		if len(m.loadingMessages) == 0 {
+			m.loadingState = sessions.StateIdle
+		}
+		m.conversation = msg.Messages
+		m.conversationErr = msg.Error
+		m.updateViewport()
+		m.viewport.GotoTop()
+		return m, nil
+	
+	case ToolUsageLoadedMsg:
+		if m.currentMode != toolView || m.conversationID != msg.SessionID {
+			return m, nil
+		}
+		delete(m.activeRequests, "conversation")
+		if len(m.loadingMessages) == 0 {
+			m.loadingState = sessions.StateIdle
+		}
+		m.toolCalls = msg.ToolCalls
+		m.conversationErr = msg.Error
+		m.updateViewport()
+		m.viewport.GotoTop()
+		return m, nil
+	
+	case DirOpenedMsg:
+		return m.handleDirOpened(msg)
+
+	case ClipboardMsg:
+		if msg.Error != nil {
+			// Headless systems have no clipboard; print the text after exiting instead
+			m.stderrLines = append(m.stderrLines, msg.Text)
+			return m.flashStatus("Clipboard unavailable, it will be printed on exit")
+		}
+		return m.flashStatus("Copied!")
+	
+	case ClearStatusMsg:
+		if msg.ID == m.statusID {
+			m.statusMessage = ""
+		}
+		return m, nil
+	
+	case FavoriteToggledMsg:
+		return m.handleFavoriteToggled(msg)
+	
+	case LabelSetMsg:
+		return m.handleLabelSet(msg)
+	
+	case SessionRestoredMsg:
+		return m.handleSessionRestored(msg)
+	
+	case SessionDeletedMsg:
+		if msg.Error != nil {
+			m.statusMessage = fmt.Sprintf("Delete failed: %v", msg.Error)
+			return m, nil
+		}
+		m.statusMessage = "Deleted — press u to undo"
+		m.lastDeleted = msg.SessionID
+		m.forgetMessages(msg.SessionID)
+		if m.selectedProject != nil {
+			for i, session := range m.selectedProject.Sessions {
+				if session.SessionID == msg.SessionID {
+					m.selectedProject.Sessions = append(m.selectedProject.Sessions[:i], m.selectedProject.Sessions[i+1:]...)
+					if m.sessionTotal > 0 {
+						m.sessionTotal--
+					}
+					break
+				}
+			}
+			if m.projectCursor < len(m.projects) && m.projects[m.projectCursor].Path == m.selectedProject.Path {
+				m.projects[m.projectCursor].SessionCount--
+			}
+			if m.sessionCursor >= len(m.selectedProject.Sessions) && m.sessionCursor > 0 {
+				m.sessionCursor--
+			}
+			if m.sessionCursor < len(m.selectedProject.Sessions) {
+				if cached, ok := m.messageCache[m.selectedProject.Sessions[m.sessionCursor].SessionID]; ok {
+					m.currentMessages = cached
+				} else {
+					m.currentMessages = nil
+				}
+			} else {
+				m.currentMessages = nil
+			}
+		}
+		m.updateViewport()
+		return m, nil
+	
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -243,6 +526,63 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		m.statusMessage = ""
+		
+		// A pending delete is confirmed with y; any other key cancels it
+		if m.pendingDelete != nil {
+			session := m.pendingDelete
+			m.pendingDelete = nil
+			if msg.String() == "y" {
+				m.statusMessage = "Deleting session..."
+				return m, deleteSessionCmd(session.SessionID)
+			}
+			m.statusMessage = "Delete cancelled"
+			return m, nil
+		}
+		
+		// Typed keys go to the label being entered
+		if m.labelTarget != nil {
+			return m.updateLabelPrompt(msg)
+		}
+		
+		// Any key closes the help overlay; ? opens it except where it is typed
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if msg.String() == "?" && m.keyContext() != directoryKeys && m.keyContext() != confirmKeys {
+			m.showHelp = true
+			return m, nil
+		}
+		
+		// The confirmation screen only reacts to confirm, cancel and quit
+		if m.currentMode == confirmView {
+			if m.dirPrompt {
+				return m.updateDirPrompt(msg)
+			}
+			switch msg.String() {
+			case "enter", "y":
+				m.cancel() // Cancel context before quitting
+				return m, tea.Quit
+			case "esc", "n", "backspace":
+				m.selectedSession = nil
+				m.currentMode = m.confirmReturn
+				m.updateViewport()
+			case "ctrl+c", "q":
+				m.selectedSession = nil
+				m.cancel()
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		
+		// The error banner takes r and esc until it is dismissed
+		if m.err != nil {
+			if updated, cmd, ok := m.updateErrorBanner(msg); ok {
+				return updated, cmd
+			}
+		}
+		
 		// Handle ESC for cancellation when loading
 		if msg.String() == "esc" && m.loadingState != sessions.StateIdle {
 			// Cancel current operation
@@ -250,105 +590,64 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cancel()
 			}
 			m.activeRequests = make(map[string]context.CancelFunc)
+			m.loadingMessages = make(map[string]bool)
+			m.previewSeq++ // Drop a pending preview load too
 			m.loadingState = sessions.StateIdle
+			m.loadingIndicator.ClearProgress()
 			m.loadingIndicator.SetMessage("Cancelled")
 			return m, nil
 		}
 		
 		// Block navigation when loading
-		if m.loadingState != sessions.StateIdle {
+		if m.blockingLoad() {
 			return m, nil
 		}
 		
+		if m.currentMode == recentView {
+			return m.updateRecentView(msg)
+		}
+		
+		// The conversation and tool views scroll their viewport and return to the split view
+		if m.currentMode == messageView || m.currentMode == toolView {
+			switch msg.String() {
+			case "esc", "backspace", "v", "t":
+				m.currentMode = sessionView
+				m.conversation = nil
+				m.toolCalls = nil
+				m.conversationErr = nil
+				m.expandMessages = false
+				m.viewport.GotoTop()
+				m.updateViewport()
+				return m, nil
+			case "O":
+				if m.currentMode == messageView {
+					return m.toggleNewestFirst()
+				}
+			case "e":
+				if m.currentMode == messageView {
+					return m.toggleExpandMessages()
+				}
+			case "ctrl+c", "q":
+				m.cancel()
+				return m, tea.Quit
+			}
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+		
+		if updated, cmd, ok := m.handleJumpKey(msg); ok {
+			return updated, cmd
+		}
+		if updated, cmd, ok := m.handleNavigationKey(msg.String()); ok {
+			return updated, cmd
+		}
+		
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.cancel() // Cancel context on quit
 			return m, tea.Quit
 
-		case "up", "k":
-			if m.currentMode == projectView {
-				if m.projectCursor > 0 {
-					m.projectCursor--
-					m.updateViewport()
-				}
-			} else {
-				if m.sessionCursor > 0 {
-					m.sessionCursor--
-					session := m.selectedProject.Sessions[m.sessionCursor]
-					
-					// Cancel any existing message fetch for previous session
-					for key, cancel := range m.activeRequests {
-						if strings.HasPrefix(key, "messages-") {
-							cancel()
-							delete(m.activeRequests, key)
-						}
-					}
-					
-					// Check cache first
-					if cached, ok := m.messageCache[session.SessionID]; ok {
-						m.currentMessages = cached
-						m.loadingState = sessions.StateIdle
-						m.updateViewport()
-					} else {
-						// Load messages asynchronously
-						m.currentMessages = []string{} // Clear current messages
-						m.loadingState = sessions.StateLoadingMessages
-						m.loadingMessages[session.SessionID] = true
-						m.loadingIndicator.SetMessage("Loading messages...")
-						
-						ctx, cancel := context.WithCancel(m.ctx)
-						m.activeRequests["messages-"+session.SessionID] = cancel
-						
-						cmds = append(cmds, loadMessagesCmd(ctx, session.SessionID))
-						cmds = append(cmds, tickCmd())
-						m.updateViewport()
-						return m, tea.Batch(cmds...)
-					}
-				}
-			}
-
-		case "down", "j":
-			if m.currentMode == projectView {
-				if m.projectCursor < len(m.projects)-1 {
-					m.projectCursor++
-					m.updateViewport()
-				}
-			} else {
-				if m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions)-1 {
-					m.sessionCursor++
-					session := m.selectedProject.Sessions[m.sessionCursor]
-					
-					// Cancel any existing message fetch for previous session
-					for key, cancel := range m.activeRequests {
-						if strings.HasPrefix(key, "messages-") {
-							cancel()
-							delete(m.activeRequests, key)
-						}
-					}
-					
-					// Check cache first
-					if cached, ok := m.messageCache[session.SessionID]; ok {
-						m.currentMessages = cached
-						m.loadingState = sessions.StateIdle
-						m.updateViewport()
-					} else {
-						// Load messages asynchronously
-						m.currentMessages = []string{} // Clear current messages
-						m.loadingState = sessions.StateLoadingMessages
-						m.loadingMessages[session.SessionID] = true
-						m.loadingIndicator.SetMessage("Loading messages...")
-						
-						ctx, cancel := context.WithCancel(m.ctx)
-						m.activeRequests["messages-"+session.SessionID] = cancel
-						
-						cmds = append(cmds, loadMessagesCmd(ctx, session.SessionID))
-						cmds = append(cmds, tickCmd())
-						m.updateViewport()
-						return m, tea.Batch(cmds...)
-					}
-				}
-			}
-
 		case "enter":
 			if m.currentMode == projectView {
 				// Load sessions for the selected project asynchronously
@@ -358,7 +657,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedProject = &project
 					m.currentMode = sessionView // Switch to session view immediately
 					m.sessionCursor = 0
-					m.currentMessages = []string{} // Clear messages
+					m.currentMessages = nil // Clear messages
 					m.loadingState = sessions.StateLoadingSessions
 					m.loadingIndicator.SetMessage("Loading sessions...")
 					m.updateViewport() // Update view to show split screen with loading
@@ -367,17 +666,145 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					ctx, cancel := context.WithCancel(m.ctx)
 					m.activeRequests["sessions"] = cancel
 					
-					cmds = append(cmds, loadSessionsCmd(ctx, project.Path))
+					cmds = append(cmds, loadSessionsCmd(ctx, project.Path, 0))
 					cmds = append(cmds, tickCmd())
 					return m, tea.Batch(cmds...)
 				}
 			} else {
 				// Select session to resume
 				if m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
-					m.selectedSession = &m.selectedProject.Sessions[m.sessionCursor]
-					m.cancel() // Cancel context before quitting
-					return m, tea.Quit
+					return m.selectSession(m.selectedProject.Sessions[m.sessionCursor])
+				}
+			}
+
+		case "pgdown", "pgup":
+			delta := 1
+			if msg.String() == "pgup" {
+				delta = -1
+			}
+			if m.currentMode == projectView {
+				offset, ok := pageOffset(m.projectOffset, m.projectTotal, delta)
+				if !ok {
+					return m, nil
 				}
+				m.loadingState = sessions.StateLoadingProjects
+				m.loadingIndicator.SetMessage("Loading projects...")
+				
+				ctx, cancel := context.WithCancel(m.ctx)
+				m.activeRequests["projects"] = cancel
+				return m, tea.Batch(loadProjectsCmd(ctx, offset), tickCmd())
+			}
+			if m.currentMode == sessionView && m.selectedProject != nil {
+				offset, ok := pageOffset(m.sessionOffset, m.sessionTotal, delta)
+				if !ok {
+					return m, nil
+				}
+				m.selectedProject.Sessions = []models.Session{}
+				m.sessionCursor = 0
+				m.currentMessages = nil
+				m.loadingState = sessions.StateLoadingSessions
+				m.loadingIndicator.SetMessage("Loading sessions...")
+				m.updateViewport()
+				
+				ctx, cancel := context.WithCancel(m.ctx)
+				m.activeRequests["sessions"] = cancel
+				return m, tea.Batch(loadSessionsCmd(ctx, m.selectedProject.Path, offset), tickCmd())
+			}
+
+		case "v":
+			if m.currentMode == sessionView && m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
+				session := m.selectedProject.Sessions[m.sessionCursor]
+				m.currentMode = messageView
+				m.conversationID = session.SessionID
+				m.conversation = nil
+				m.conversationErr = nil
+				m.loadingState = sessions.StateLoadingMessages
+				m.loadingIndicator.SetMessage("Loading conversation...")
+				m.updateViewport()
+				
+				ctx, cancel := context.WithCancel(m.ctx)
+				m.activeRequests["conversation"] = cancel
+				return m, tea.Batch(loadConversationCmd(ctx, session.SessionID), tickCmd())
+			}
+
+		case "t":
+			if m.currentMode == sessionView && m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
+				session := m.selectedProject.Sessions[m.sessionCursor]
+				m.currentMode = toolView
+				m.conversationID = session.SessionID
+				m.toolCalls = nil
+				m.conversationErr = nil
+				m.loadingState = sessions.StateLoadingMessages
+				m.loadingIndicator.SetMessage("Loading tool usage...")
+				m.updateViewport()
+				
+				ctx, cancel := context.WithCancel(m.ctx)
+				m.activeRequests["conversation"] = cancel
+				return m, tea.Batch(loadToolUsageCmd(ctx, session.SessionID), tickCmd())
+			}
+
+		case "s":
+			if m.currentMode == sessionView && m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
+				return m, toggleFavoriteCmd(m.selectedProject.Sessions[m.sessionCursor].SessionID)
+			}
+
+		case "m":
+			if m.currentMode == sessionView && m.selectedProject != nil {
+				return m.toggleMessagesOnly()
+			}
+
+		case "D":
+			if m.currentMode == sessionView && m.selectedProject != nil {
+				return m.toggleGroupByDay()
+			}
+
+		case "I":
+			if m.currentMode == sessionView && m.selectedProject != nil {
+				return m.toggleFullIDs()
+			}
+
+		case "y":
+			if m.currentMode == sessionView && m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
+				return m, copyToClipboardCmd(m.selectedProject.Sessions[m.sessionCursor].SessionID)
+			}
+
+		case "c":
+			if m.currentMode == sessionView && m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
+				return m, copyResumeCommandCmd(m.selectedProject.Sessions[m.sessionCursor], m.opts.ExtraArgs)
+			}
+
+		case "d":
+			if m.currentMode == sessionView && m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
+				session := m.selectedProject.Sessions[m.sessionCursor]
+				m.pendingDelete = &session
+			}
+
+		case "n":
+			if m.currentMode == sessionView && m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
+				return m.startLabel(m.selectedProject.Sessions[m.sessionCursor])
+			}
+
+		case "r":
+			if m.currentMode == projectView {
+				return m.enterRecentView()
+			}
+
+		case "ctrl+r":
+			if m.currentMode == projectView || m.currentMode == sessionView {
+				return m.refresh()
+			}
+
+		case "u":
+			if m.currentMode == projectView || m.currentMode == sessionView {
+				return m.undoDelete()
+			}
+
+		case "o":
+			if m.currentMode == projectView && m.projectCursor < len(m.projects) {
+				return m.openProjectDir(m.projects[m.projectCursor].Path)
+			}
+			if m.currentMode == sessionView && m.selectedProject != nil {
+				return m.openProjectDir(m.selectedProject.Path)
 			}
 
 		case "esc", "backspace":
@@ -390,15 +817,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Handle viewport updates
-	if m.currentMode == projectView {
-		var cmd tea.Cmd
-		m.viewport, cmd = m.viewport.Update(msg)
-		cmds = append(cmds, cmd)
+	// Handle viewport updates. Keys don't scroll the lists directly; they follow
+	// the cursor instead, see ensureCursorVisible.
+	_, isKey := msg.(tea.KeyMsg)
+	if m.currentMode == projectView || m.currentMode == recentView {
+		if !isKey {
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 	} else {
 		// Update both viewports in session view
 		var leftCmd, rightCmd tea.Cmd
-		m.leftViewport, leftCmd = m.leftViewport.Update(msg)
+		if !isKey {
+			m.leftViewport, leftCmd = m.leftViewport.Update(msg)
+		}
 		m.rightViewport, rightCmd = m.rightViewport.Update(msg)
 		cmds = append(cmds, leftCmd, rightCmd)
 	}
@@ -410,6 +843,12 @@ func (m *model) updateViewport() {
 	if m.currentMode == projectView {
 		content := m.renderProjects()
 		m.viewport.SetContent(content)
+	} else if m.currentMode == messageView {
+		m.viewport.SetContent(m.renderConversation())
+	} else if m.currentMode == toolView {
+		m.viewport.SetContent(m.renderToolTimeline())
+	} else if m.currentMode == recentView {
+		m.viewport.SetContent(m.renderRecentSessions())
 	} else {
 		// Split screen for session view
 		leftContent := m.renderSessionsList()
@@ -417,6 +856,45 @@ func (m *model) updateViewport() {
 		m.leftViewport.SetContent(leftContent)
 		m.rightViewport.SetContent(rightContent)
 	}
+	m.ensureCursorVisible()
+}
+
+// flashStatus shows a status message in the footer that clears itself shortly after
+func (m model) flashStatus(status string) (tea.Model, tea.Cmd) {
+	m.statusID++
+	m.statusMessage = status
+	return m, clearStatusCmd(m.statusID)
+}
+
+// pageOffset returns the offset of the page delta pages away from offset,
+// or false when that page is outside the listing
+func pageOffset(offset, total, delta int) (int, bool) {
+	next := offset + delta*sessions.PageLimit()
+	if next < 0 || next >= total {
+		return offset, false
+	}
+	return next, true
+}
+
+// pageRange returns the paging indicator for the current view, or "" when everything fits on one page
+func (m model) pageRange() string {
+	if m.currentMode == projectView {
+		if m.projectTotal > len(m.projects) {
+			return sessions.FormatPageRange(m.projectOffset, len(m.projects), m.projectTotal)
+		}
+	} else if m.selectedProject != nil && m.sessionTotal > len(m.selectedProject.Sessions) {
+		return sessions.FormatPageRange(m.sessionOffset, len(m.selectedProject.Sessions), m.sessionTotal)
+	}
+	return ""
+}
+
+// formatTime formats a timestamp using the configured date format
+func (m model) formatTime(t time.Time) string {
+	layout := m.opts.DateFormat
+	if layout == "" {
+		layout = "Jan 02 15:04 MST"
+	}
+	return timefmt.Format(t, layout, m.opts.TimeFormat)
 }
 
 // loadCurrentSessionMessages is now replaced by async loading
@@ -430,6 +908,13 @@ func (m model) renderContent() string {
 }
 
 func (m model) renderProjects() string {
+	if len(m.projects) == 0 && m.emptyGuidance != "" {
+		return m.newStyle().Foreground(lipgloss.Color("240")).Italic(true).Render(m.emptyGuidance)
+	}
+
+	summaryStyle := m.newStyle().
+		Foreground(lipgloss.Color("242"))
+
 	var s strings.Builder
 	
 	for i, project := range m.projects {
@@ -438,18 +923,29 @@ func (m model) renderProjects() string {
 			cursor = "> "
 		}
 		
-		style := lipgloss.NewStyle()
+		style := m.newStyle()
 		if i == m.projectCursor {
-			style = style.Foreground(lipgloss.Color("212")).Bold(true)
+			style = style.Foreground(m.theme.Accent).Bold(true)
 		}
 		
-		line := fmt.Sprintf("%s%s (%d sessions) - Last Active: %s",
+		line := fmt.Sprintf("%s%d. %s (%d sessions, %d messages) - Last Active: %s",
 			cursor,
+			i+1,
 			project.Name,
 			project.SessionCount,
-			project.LastActivity.Format("Jan 02 15:04"))
+			project.MessageCount,
+			m.formatTime(project.LastActivity))
 		
 		s.WriteString(style.Render(line) + "\n")
+		
+		if m.opts.Verbose {
+			summary := project.LatestSummary
+			if summary == "" {
+				summary = "No Summary"
+			}
+			summary = truncateSummary(summary, m.width-len(cursor)-2)
+			s.WriteString(summaryStyle.Render("    "+summary) + "\n")
+		}
 	}
 	
 	return s.String()
@@ -469,38 +965,44 @@ func (m model) renderSessionsList() string {
 	var s strings.Builder
 	
 	// Header for sessions list
-	headerStyle := lipgloss.NewStyle().
+	headerStyle := m.newStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("229"))
+		Foreground(m.theme.Title)
 	s.WriteString(headerStyle.Render("Sessions") + "\n")
 	s.WriteString(strings.Repeat("─", m.leftViewport.Width-2) + "\n\n")
 	
 	// Show loading state for sessions
 	if m.loadingState == sessions.StateLoadingSessions {
-		loadingStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("212"))
+		loadingStyle := m.newStyle().
+			Foreground(m.theme.Accent)
 		s.WriteString(loadingStyle.Render(m.loadingIndicator.View()))
 		return s.String()
 	}
 	
 	if m.selectedProject.Sessions == nil || len(m.selectedProject.Sessions) == 0 {
-		emptyStyle := lipgloss.NewStyle().
+		emptyStyle := m.newStyle().
 			Foreground(lipgloss.Color("240")).
 			Italic(true)
 		s.WriteString(emptyStyle.Render("No sessions found"))
 		return s.String()
 	}
 	
+	dayStyle := m.newStyle().
+		Foreground(lipgloss.Color("245"))
 	for i, session := range m.selectedProject.Sessions {
 		cursor := "  "
 		if i == m.sessionCursor {
 			cursor = "> "
 		}
 		
+		if day := m.sessionDayHeader(i); day != "" {
+			s.WriteString(dayStyle.Render("── "+day+" ──") + "\n")
+		}
+		
 		// Summary line (always show, use "No Summary" if empty)
-		summaryStyle := lipgloss.NewStyle()
+		summaryStyle := m.newStyle()
 		if i == m.sessionCursor {
-			summaryStyle = summaryStyle.Foreground(lipgloss.Color("212")).Bold(true)
+			summaryStyle = summaryStyle.Foreground(m.theme.Accent).Bold(true)
 		} else {
 			summaryStyle = summaryStyle.Foreground(lipgloss.Color("250"))
 		}
@@ -514,50 +1016,68 @@ func (m model) renderSessionsList() string {
 			} else {
 				summaryStyle = summaryStyle.Foreground(lipgloss.Color("245")).Italic(true)
 			}
+		} else if session.SummarySource.Generated() {
+			// Titles made of the first prompt or reply stand apart from Claude's summaries
+			summaryStyle = summaryStyle.Italic(true)
 		}
 		
 		// Add [Resumed] prefix if this session was resumed
 		if session.IsResumed {
 			summaryText = "[Resumed] " + summaryText
 		}
-		
-		// Truncate summary to fit in the left panel
-		maxWidth := m.leftViewport.Width - 4
-		if maxWidth < 20 {
-			maxWidth = 20
+		if session.Label != "" {
+			summaryText = "[" + session.Label + "] " + summaryText
 		}
-		if len(summaryText) > maxWidth {
-			summaryText = summaryText[:maxWidth-3] + "..."
+		if session.Favorite {
+			summaryText = "★ " + summaryText
 		}
-		summaryLine := fmt.Sprintf("%s%s", cursor, summaryText)
+		if session.Active {
+			summaryText = activeMarker + summaryText
+		}
+		
+		// Truncate summary to fit in the left panel, after the number typed to jump to it
+		number := fmt.Sprintf("%d. ", i+1)
+		summaryText = truncateSummary(summaryText, m.leftViewport.Width-4-len(number))
+		summaryLine := fmt.Sprintf("%s%s%s", cursor, number, summaryText)
 		s.WriteString(summaryStyle.Render(summaryLine) + "\n")
 		
-		// Date and time with "Last Active" label
-		dateStyle := lipgloss.NewStyle()
+		// Date and time with "Last Active" label, colored by how long ago it was
+		dateStyle := m.styleForAge(session.LastActivity)
 		if i == m.sessionCursor {
-			dateStyle = dateStyle.Foreground(lipgloss.Color("245"))
-		} else {
-			dateStyle = dateStyle.Foreground(lipgloss.Color("240"))
+			dateStyle = dateStyle.Bold(true)
 		}
 		
-		dateLine := fmt.Sprintf("  Last Active: %s", session.LastActivity.Format("Jan 02 15:04 MST"))
+		dateLine := fmt.Sprintf("  Last Active: %s", m.formatTime(session.LastActivity))
+		if session.Duration > 0 {
+			dateLine += " • ⏲ " + sessions.FormatDuration(session.Duration)
+		}
+		if session.GitBranch != "" {
+			dateLine += " • ⎇ " + session.GitBranch
+		}
+		if session.MultipleDirs {
+			dateLine += " • " + multipleDirsMarker
+		}
+		dateLine = truncateSummary(dateLine, m.leftViewport.Width-2)
 		s.WriteString(dateStyle.Render(dateLine) + "\n")
 		
 		// Session ID (smaller, tertiary info)
-		sessionIDStyle := lipgloss.NewStyle()
+		sessionIDStyle := m.newStyle()
 		if i == m.sessionCursor {
 			sessionIDStyle = sessionIDStyle.Foreground(lipgloss.Color("238"))
 		} else {
 			sessionIDStyle = sessionIDStyle.Foreground(lipgloss.Color("235"))
 		}
 		
-		truncatedID := session.SessionID
-		if len(truncatedID) > 12 {
-			truncatedID = truncatedID[:12] + "..."
-		}
+		truncatedID := shortSessionID(session.SessionID, m.leftViewport.Width-2, m.opts.FullIDs)
 		sessionIDLine := fmt.Sprintf("  %s", truncatedID)
 		s.WriteString(sessionIDStyle.Render(sessionIDLine) + "\n")
 		
+		if session.ResumedFrom != "" {
+			prefix := "  ↳ resumed from "
+			resumedFrom := shortSessionID(session.ResumedFrom, m.leftViewport.Width-lipgloss.Width(prefix), m.opts.FullIDs)
+			s.WriteString(sessionIDStyle.Render(prefix+resumedFrom) + "\n")
+		}
+		
 		if i < len(m.selectedProject.Sessions)-1 {
 			s.WriteString("\n")
 		}
@@ -566,16 +1086,64 @@ func (m model) renderSessionsList() string {
 	return s.String()
 }
 
+// minSessionIDLength is how many characters of a session ID are always shown
+const minSessionIDLength = 12
+
+// shortSessionID truncates a session ID for display in the session list, in a
+// line of width cells. The whole ID is shown when full and it fits; otherwise
+// it keeps as many characters as fit with full, or a third of width without,
+// and never fewer than minSessionIDLength.
+func shortSessionID(sessionID string, width int, full bool) string {
+	keep := width / 3
+	if full {
+		if len(sessionID) <= width {
+			return sessionID
+		}
+		keep = width - len("...")
+	}
+	keep = max(keep, minSessionIDLength)
+	if len(sessionID) > keep {
+		return sessionID[:keep] + "..."
+	}
+	return sessionID
+}
+
+// toggleFullIDs switches the session list between whole and shortened session IDs
+func (m model) toggleFullIDs() (tea.Model, tea.Cmd) {
+	m.opts.FullIDs = !m.opts.FullIDs
+	m.updateViewport()
+	if m.opts.FullIDs {
+		return m.flashStatus("Showing whole session IDs")
+	}
+	return m.flashStatus("Shortening session IDs")
+}
 
 func (m model) renderMessages() string {
 	var s strings.Builder
 	
 	// Header
-	headerStyle := lipgloss.NewStyle().
+	headerStyle := m.newStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("229"))
+		Foreground(m.theme.Title)
 	
 	s.WriteString(headerStyle.Render("Conversation") + "\n")
+	
+	// Detail and token usage for the highlighted session, once they have been loaded
+	if m.selectedProject != nil && m.sessionCursor < len(m.selectedProject.Sessions) {
+		currentSession := m.selectedProject.Sessions[m.sessionCursor]
+		if detail := m.detailCache[currentSession.SessionID]; detail != nil {
+			s.WriteString(m.renderSessionDetail(detail))
+		}
+		if usage, ok := m.usageCache[currentSession.SessionID]; ok {
+			usageStyle := m.newStyle().
+				Foreground(lipgloss.Color("245"))
+			s.WriteString(usageStyle.Render("Tokens: "+sessions.FormatUsage(usage)) + "\n")
+			if usage != nil && usage.Available {
+				s.WriteString(usageStyle.Render("Models: "+sessions.FormatModels(usage)) + "\n")
+			}
+		}
+	}
+	
 	dividerWidth := m.rightViewport.Width - 2
 	if dividerWidth < 10 {
 		dividerWidth = 10
@@ -591,15 +1159,15 @@ func (m model) renderMessages() string {
 	
 	// Show loading state for messages
 	if isLoadingCurrentSession {
-		loadingStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("212"))
+		loadingStyle := m.newStyle().
+			Foreground(m.theme.Accent)
 		s.WriteString(loadingStyle.Render(m.loadingIndicator.View()))
 		return s.String()
 	}
 	
 	// If sessions are still loading, show a placeholder
 	if m.loadingState == sessions.StateLoadingSessions {
-		emptyStyle := lipgloss.NewStyle().
+		emptyStyle := m.newStyle().
 			Foreground(lipgloss.Color("240")).
 			Italic(true)
 		s.WriteString(emptyStyle.Render("Select a session to view messages"))
@@ -607,7 +1175,7 @@ func (m model) renderMessages() string {
 	}
 	
 	if len(m.currentMessages) == 0 {
-		emptyStyle := lipgloss.NewStyle().
+		emptyStyle := m.newStyle().
 			Foreground(lipgloss.Color("240")).
 			Italic(true)
 		s.WriteString(emptyStyle.Render("No messages found"))
@@ -615,36 +1183,53 @@ func (m model) renderMessages() string {
 	}
 	
 	// Display messages with role-based styling
-	for i, msg := range m.currentMessages {
+	for i, line := range m.currentMessages {
+		msg := line.Text
+		
 		// Check if this is the omitted messages indicator
 		if strings.HasPrefix(msg, "... (") && strings.Contains(msg, "messages omitted)") {
 			// Style the omitted indicator specially
-			omittedStyle := lipgloss.NewStyle().
+			omittedStyle := m.newStyle().
 				Foreground(lipgloss.Color("238")).
 				Italic(true)
 			s.WriteString("\n" + omittedStyle.Render(msg) + "\n\n")
 			continue
 		}
 		
+		// Long pauses between messages get a dimmed divider
+		if strings.HasPrefix(msg, sessions.GapDividerPrefix) {
+			gapStyle := m.newStyle().
+				Foreground(lipgloss.Color("240")).
+				Italic(true)
+			s.WriteString(gapStyle.Render(msg) + "\n\n")
+			continue
+		}
+		
+		// Render the message time dimmed ahead of the role
+		timePrefix := line.TimePrefix()
+		if timePrefix != "" {
+			s.WriteString(m.newStyle().Foreground(lipgloss.Color("240")).Render(timePrefix))
+		}
+		
 		// Determine role and style
 		var roleStyle, contentStyle lipgloss.Style
 		
 		if strings.HasPrefix(msg, "[User]") {
-			roleStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("39")).
+			roleStyle = m.newStyle().
+				Foreground(m.theme.User).
 				Bold(true)
-			contentStyle = lipgloss.NewStyle().
+			contentStyle = m.newStyle().
 				Foreground(lipgloss.Color("252"))
 		} else if strings.HasPrefix(msg, "[Assistant]") {
-			roleStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("213")).
+			roleStyle = m.newStyle().
+				Foreground(m.theme.Assistant).
 				Bold(true)
-			contentStyle = lipgloss.NewStyle().
+			contentStyle = m.newStyle().
 				Foreground(lipgloss.Color("250"))
 		} else {
-			roleStyle = lipgloss.NewStyle().
+			roleStyle = m.newStyle().
 				Foreground(lipgloss.Color("243"))
-			contentStyle = lipgloss.NewStyle().
+			contentStyle = m.newStyle().
 				Foreground(lipgloss.Color("248"))
 		}
 		
@@ -657,29 +1242,30 @@ func (m model) renderMessages() string {
 			// Render role
 			s.WriteString(roleStyle.Render(role) + " ")
 			
-			// Render content with wrapping
-			wrapWidth := m.rightViewport.Width - len(role) - 2
-			if wrapWidth < 30 {
-				wrapWidth = 30
+			// Render content with wrapping, in the columns left of the pane
+			indent := lipgloss.Width(timePrefix) + lipgloss.Width(role) + 1
+			wrapWidth := m.rightViewport.Width - indent - 1
+			if wrapWidth < minTextWidth {
+				wrapWidth = minTextWidth
 			}
 			
 			// Special handling for tool calls
 			if strings.Contains(content, "🔧") {
 				// Tool calls get special coloring
-				toolStyle := lipgloss.NewStyle().
-					Foreground(lipgloss.Color("220"))
-				s.WriteString(toolStyle.Render(content) + "\n")
+				toolStyle := m.newStyle().
+					Foreground(m.theme.Tool)
+				s.WriteString(toolStyle.Render(truncateSummary(content, wrapWidth)) + "\n")
 			} else if strings.Contains(content, "↩") {
 				// Tool results get dimmer coloring
-				resultStyle := lipgloss.NewStyle().
+				resultStyle := m.newStyle().
 					Foreground(lipgloss.Color("240"))
-				s.WriteString(resultStyle.Render(content) + "\n")
+				s.WriteString(resultStyle.Render(truncateSummary(content, wrapWidth)) + "\n")
 			} else {
 				// Regular text content
 				lines := wrapText(content, wrapWidth)
 				for j, line := range lines {
 					if j > 0 {
-						s.WriteString(strings.Repeat(" ", len(role)+1)) // Indent continuation
+						s.WriteString(strings.Repeat(" ", indent)) // Indent continuation
 					}
 					s.WriteString(contentStyle.Render(line) + "\n")
 				}
@@ -711,7 +1297,7 @@ func wrapText(text string, width int) []string {
 	
 	currentLine := words[0]
 	for _, word := range words[1:] {
-		if len(currentLine)+1+len(word) > width {
+		if lipgloss.Width(currentLine)+1+lipgloss.Width(word) > width {
 			lines = append(lines, currentLine)
 			currentLine = word
 		} else {
@@ -726,15 +1312,25 @@ func wrapText(text string, width int) []string {
 }
 
 func (m model) View() string {
+	if m.showHelp && m.ready {
+		return m.renderHelpOverlay(m.renderView())
+	}
+	return m.renderView()
+}
+
+// renderView renders the current view without the help overlay
+func (m model) renderView() string {
 	if !m.ready {
+		if m.loadingState != sessions.StateIdle {
+			return "\n  " + m.loadingIndicator.View()
+		}
 		return "\n  Initializing..."
 	}
 
+	header := m.renderHeader()
 	if m.err != nil {
-		return fmt.Sprintf("\n  Error: %v\n", m.err)
+		header = m.renderErrorBanner()
 	}
-
-	header := m.renderHeader()
 	footer := m.renderFooter()
 	
 	// Show loading overlay only for projects loading
@@ -743,8 +1339,10 @@ func (m model) View() string {
 		return fmt.Sprintf("%s\n%s\n%s", header, loadingView, footer)
 	}
 	
-	if m.currentMode == projectView {
+	if m.currentMode == projectView || m.currentMode == recentView || m.currentMode == messageView || m.currentMode == toolView {
 		return fmt.Sprintf("%s\n%s\n%s", header, m.viewport.View(), footer)
+	} else if m.currentMode == confirmView {
+		return fmt.Sprintf("%s\n%s\n%s", header, m.renderConfirm(), footer)
 	} else {
 		// Split screen view for sessions (loading states handled in panels)
 		return fmt.Sprintf("%s\n%s\n%s", header, m.renderSplitView(), footer)
@@ -753,15 +1351,15 @@ func (m model) View() string {
 
 func (m model) renderSplitView() string {
 	// Use lipgloss to properly handle the layout
-	leftStyle := lipgloss.NewStyle().
+	leftStyle := m.newStyle().
 		Width(m.leftViewport.Width).
 		Height(m.leftViewport.Height)
 	
-	rightStyle := lipgloss.NewStyle().
+	rightStyle := m.newStyle().
 		Width(m.rightViewport.Width).
 		Height(m.rightViewport.Height)
 	
-	dividerStyle := lipgloss.NewStyle().
+	dividerStyle := m.newStyle().
 		Foreground(lipgloss.Color("238")).
 		Height(m.leftViewport.Height)
 	
@@ -786,34 +1384,145 @@ func (m model) renderSplitView() string {
 	)
 }
 
+// renderConfirm renders the confirmation screen shown before resuming a session
+func (m model) renderConfirm() string {
+	if m.selectedSession == nil {
+		return ""
+	}
+	session := m.selectedSession
+	dir := session.ProjectPath
+	if m.resumeDir != "" {
+		dir = m.resumeDir
+	}
+	
+	labelStyle := m.newStyle().
+		Foreground(lipgloss.Color("245"))
+	valueStyle := m.newStyle().
+		Foreground(lipgloss.Color("252"))
+	commandStyle := m.newStyle().
+		Foreground(m.theme.Tool)
+	
+	summary := session.Summary
+	if summary == "" {
+		summary = "No Summary"
+	}
+	
+	messageCount := "unknown"
+	if count, ok := m.messageCounts[session.SessionID]; ok {
+		messageCount = fmt.Sprintf("%d", count)
+	}
+	
+	var s strings.Builder
+	s.WriteString(m.newStyle().Bold(true).Foreground(m.theme.Title).Render("Resume this session?") + "\n\n")
+	rows := [][2]string{
+		{"Summary", summary},
+		{"Session", session.SessionID},
+		{"Project", session.ProjectPath},
+		{"Last Active", m.formatTime(session.LastActivity)},
+		{"Messages", messageCount},
+	}
+	for _, row := range rows {
+		s.WriteString(labelStyle.Render(fmt.Sprintf("%-12s", row[0]+":")) + valueStyle.Render(row[1]) + "\n")
+	}
+	if session.Active {
+		warnStyle := m.newStyle().
+			Foreground(lipgloss.Color("214"))
+		s.WriteString("\n" + warnStyle.Render("This session is still being written, probably by claude in another terminal.") + "\n")
+		s.WriteString(warnStyle.Render("Resuming it there as well may conflict.") + "\n")
+	}
+	if m.dirPrompt {
+		s.WriteString("\n" + m.renderDirPrompt())
+	} else {
+		s.WriteString("\n" + labelStyle.Render("Command:") + "\n")
+		s.WriteString(commandStyle.Render(sessions.ResumeCommandLine(session.SessionID, dir, m.opts.ExtraArgs...)) + "\n")
+	}
+	
+	boxStyle := m.newStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.TitleBackground).
+		Padding(1, 2).
+		MaxWidth(m.width)
+	
+	return m.newStyle().
+		Width(m.width).
+		Height(m.height-2).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(boxStyle.Render(s.String()))
+}
+
 func (m model) renderHeader() string {
 	title := "Claude Resume - Projects"
-	if m.currentMode == sessionView && m.selectedProject != nil {
+	if m.loadingState != sessions.StateLoadingProjects {
+		total := m.projectTotal
+		if total < len(m.projects) {
+			total = len(m.projects)
+		}
+		title += fmt.Sprintf(" (%s)", sessions.Pluralize(total, "project"))
+	}
+	if m.currentMode != projectView && m.selectedProject != nil {
 		title = fmt.Sprintf("Claude Resume - %s", m.selectedProject.Name)
+		if m.loadingState != sessions.StateLoadingSessions {
+			total := m.sessionTotal
+			if total < len(m.selectedProject.Sessions) {
+				total = len(m.selectedProject.Sessions)
+			}
+			title += fmt.Sprintf(" (%s)", sessions.Pluralize(total, "session"))
+		}
+	}
+	if m.currentMode == recentView || (m.currentMode == confirmView && m.confirmReturn == recentView) {
+		title = "Claude Resume - Recent Sessions"
+		if m.loadingState != sessions.StateLoadingSessions {
+			title += fmt.Sprintf(" (%s)", sessions.Pluralize(len(m.recentSessions), "session"))
+		}
+	}
+	if m.scanLimit != nil {
+		title += fmt.Sprintf(" • newest %d of %d session files", m.scanLimit.ReadFiles, m.scanLimit.TotalFiles)
 	}
 	
-	style := lipgloss.NewStyle().
+	style := m.newStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("63"))
+		Foreground(m.theme.Title).
+		Background(m.theme.TitleBackground)
 	
 	return style.Render(title)
 }
 
 func (m model) renderFooter() string {
-	var info string
-	
-	if m.loadingState != sessions.StateIdle {
-		info = "ESC: cancel • q: quit"
-	} else {
-		info = "↑/↓: navigate • enter: select"
-		if m.currentMode == sessionView {
-			info += " • esc: back"
+	if m.pendingDelete != nil {
+		summary := m.pendingDelete.Summary
+		if summary == "" {
+			summary = m.pendingDelete.SessionID
 		}
-		info += " • q: quit"
+		warnStyle := m.newStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true)
+		return warnStyle.Render(fmt.Sprintf("Move session %q to the trash? y: delete • any other key: cancel", summary))
+	}
+	if m.labelTarget != nil {
+		return m.labelInput.View() + m.newStyle().Foreground(lipgloss.Color("241")).Render(" • enter: save • esc: cancel")
+	}
+	
+	keys := m.keyContext()
+	pageRange := ""
+	if keys == projectKeys || keys == sessionKeys {
+		pageRange = m.pageRange()
+	}
+	info := footerHints(keys, pageRange != "")
+	if pageRange != "" {
+		info = pageRange + " • " + info
+	}
+	if keys == conversationKeys {
+		info = fmt.Sprintf("%3.f%% • %s", m.viewport.ScrollPercent()*100, info)
 	}
 	
-	style := lipgloss.NewStyle().
+	if m.statusMessage != "" {
+		info = m.statusMessage + " • " + info
+	}
+	if m.refreshing > 0 {
+		info = m.loadingIndicator.spinner.View() + " Refreshing… • " + info
+	}
+	
+	style := m.newStyle().
 		Foreground(lipgloss.Color("241"))
 	
 	return style.Render(info)
@@ -821,19 +1530,55 @@ func (m model) renderFooter() string {
 
 
 // ShowTUI displays the TUI and returns the selected session
-func ShowTUI(projects []models.Project) (*models.Session, error) {
+func ShowTUI(projects []models.Project, opts Options) (*models.Session, error) {
 	m := initialModel(projects)
+	m.opts = opts
+	m.theme = themeByName(opts.Theme)
+	m.renderer = newRenderer(!opts.NoColor)
+	m.loadingIndicator.SetRenderer(m.renderer)
 	
 	// If projects is nil, we need to load them async
-	if projects == nil {
+	if opts.Recent {
+		m.currentMode = recentView
+		m.loadingState = sessions.StateLoadingSessions
+		m.loadingIndicator.SetMessage("Loading recent sessions...")
+		m.initialCmd = tea.Batch(
+			loadRecentSessionsCmd(m.ctx),
+			tickCmd(),
+		)
+	} else if projects == nil {
 		m.projects = []models.Project{} // Initialize empty
 		m.loadingState = sessions.StateLoadingProjects
 		m.loadingIndicator.SetMessage("Loading projects...")
 		m.initialCmd = tea.Batch(
-			loadProjectsCmd(m.ctx),
+			loadProjectsCmd(m.ctx, 0),
 			tickCmd(),
 		)
 	}
+	if opts.Watch {
+		m.initialCmd = tea.Batch(m.initialCmd, checkFilesCmd())
+	}
+	if opts.IdleTimeout > 0 {
+		m.lastKey = time.Now()
+		m.initialCmd = tea.Batch(m.initialCmd, idleTimeoutCmd(opts.IdleTimeout))
+	}
+	if !opts.NoCache {
+		store, err := openDiskCache()
+		if err != nil {
+			m.stderrLines = append(m.stderrLines, fmt.Sprintf("Warning: %v", err))
+		}
+		m.diskCache = store
+		m.warmFromDiskCache()
+	}
+	selectionFile, err := selectionPath()
+	if err == nil && !opts.NoResumePosition {
+		var selection LastSelection
+		selection, err = loadSelection(selectionFile)
+		m.restore = &selection
+	}
+	if err != nil {
+		m.stderrLines = append(m.stderrLines, fmt.Sprintf("Warning: %v", err))
+	}
 	
 	p := tea.NewProgram(
 		m,
@@ -846,5 +1591,23 @@ func ShowTUI(projects []models.Project) (*models.Session, error) {
 	}
 
 	model := finalModel.(model)
+	if model.diskCache != nil {
+		if err := model.diskCache.Save(); err != nil {
+			model.stderrLines = append(model.stderrLines, fmt.Sprintf("Warning: %v", err))
+		}
+	}
+	if selection, ok := model.currentSelection(); ok && selectionFile != "" {
+		if err := saveSelection(selectionFile, selection); err != nil {
+			model.stderrLines = append(model.stderrLines, fmt.Sprintf("Warning: %v", err))
+		}
+	}
+	for _, line := range model.stderrLines {
+		fmt.Fprintln(os.Stderr, line)
+	}
+	if model.selectedSession != nil && model.resumeDir != "" {
+		session := *model.selectedSession
+		session.ProjectPath = model.resumeDir
+		return &session, nil
+	}
 	return model.selectedSession, nil
 }
\ No newline at end of file
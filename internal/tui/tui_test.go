@@ -2,9 +2,19 @@ package tui
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+	"github.com/strrl/claude-resume/internal/cache"
 	"github.com/strrl/claude-resume/internal/sessions"
 	"github.com/strrl/claude-resume/pkg/models"
 )
@@ -40,7 +50,7 @@ func TestMessageCaching(t *testing.T) {
 	
 	// Simulate caching messages
 	sessionID := "test-session-123"
-	testMessages := []string{"Message 1", "Message 2", "Message 3"}
+	testMessages := previewLines("Message 1", "Message 2", "Message 3")
 	
 	m.messageCache[sessionID] = testMessages
 	
@@ -110,7 +120,7 @@ func TestMessageLoadedHandling(t *testing.T) {
 	// Simulate message loaded event
 	msg := MessagesLoadedMsg{
 		SessionID: "session-1",
-		Messages:  []string{"Test message 1", "Test message 2"},
+		Messages:  previewLines("Test message 1", "Test message 2"),
 		Error:     nil,
 	}
 
@@ -274,7 +284,7 @@ func TestCacheLookupBeforeLoad(t *testing.T) {
 	m.currentMode = sessionView
 	
 	// Pre-cache some messages
-	cachedMessages := []string{"Cached message 1", "Cached message 2"}
+	cachedMessages := previewLines("Cached message 1", "Cached message 2")
 	m.messageCache["cached-session"] = cachedMessages
 	
 	// Navigate to the session (which should use cache)
@@ -301,7 +311,7 @@ func TestCacheLookupBeforeLoad(t *testing.T) {
 // BenchmarkMessageCaching benchmarks message cache operations
 func BenchmarkMessageCaching(b *testing.B) {
 	m := initialModel([]models.Project{})
-	messages := []string{"Message 1", "Message 2", "Message 3"}
+	messages := previewLines("Message 1", "Message 2", "Message 3")
 	
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -373,7 +383,7 @@ func TestProgressBar(t *testing.T) {
 	}
 	
 	for _, tt := range tests {
-		bar := renderProgressBar(tt.progress, tt.width)
+		bar := renderProgressBar(lipgloss.DefaultRenderer(), tt.progress, tt.width)
 		if len(bar) == 0 {
 			t.Errorf("Progress bar should not be empty for progress %.0f", tt.progress)
 		}
@@ -402,4 +412,1779 @@ func TestWrapText(t *testing.T) {
 	if len(wrapped) != 1 || wrapped[0] != "" {
 		t.Error("Empty text should return single empty line")
 	}
-}
\ No newline at end of file
+}
+
+// TestResumeConfirmation tests the confirmation step before resuming
+func TestResumeConfirmation(t *testing.T) {
+	dir := t.TempDir()
+	projects := []models.Project{
+		{
+			Name: "test",
+			Path: dir,
+			Sessions: []models.Session{
+				{SessionID: "s1", ProjectPath: dir},
+			},
+		},
+	}
+
+	m := initialModel(projects)
+	m.leftViewport.Width = 80
+	m.selectedProject = &projects[0]
+	m.currentMode = sessionView
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.currentMode != confirmView {
+		t.Fatal("Enter should open the confirmation screen")
+	}
+
+	// Cancelling returns to the session list without selecting
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updatedModel.(model)
+	if m.currentMode != sessionView || m.selectedSession != nil {
+		t.Error("Esc should return to the session view and clear the selection")
+	}
+
+	// Confirming selects the session and quits
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updatedModel.(model)
+	if m.selectedSession == nil || m.selectedSession.SessionID != "s1" {
+		t.Error("Confirming should keep the selected session")
+	}
+	if cmd == nil {
+		t.Error("Confirming should quit the program")
+	}
+}
+
+// TestResumeWithoutConfirmation tests that NoConfirm skips the confirmation screen
+func TestResumeWithoutConfirmation(t *testing.T) {
+	projects := []models.Project{
+		{
+			Name:     "test",
+			Path:     "/test",
+			Sessions: []models.Session{{SessionID: "s1"}},
+		},
+	}
+
+	m := initialModel(projects)
+	m.opts.NoConfirm = true
+	m.selectedProject = &projects[0]
+	m.currentMode = sessionView
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.currentMode == confirmView {
+		t.Error("NoConfirm should skip the confirmation screen")
+	}
+	if m.selectedSession == nil || cmd == nil {
+		t.Error("Enter should select the session and quit")
+	}
+}
+
+// TestResumeMissingDirectory tests the alternate directory prompt for moved projects
+func TestResumeMissingDirectory(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "moved")
+	projects := []models.Project{
+		{
+			Name:     "test",
+			Path:     missing,
+			Sessions: []models.Session{{SessionID: "s1", ProjectPath: missing}},
+		},
+	}
+
+	m := initialModel(projects)
+	m.opts.NoConfirm = true
+	m.selectedProject = &projects[0]
+	m.currentMode = sessionView
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.currentMode != confirmView || !m.dirPrompt {
+		t.Fatal("A missing project directory should prompt for an alternate one even with NoConfirm")
+	}
+
+	// A directory that doesn't exist is rejected
+	m.dirInput.SetValue(missing)
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if cmd != nil || m.dirError == "" || m.resumeDir != "" {
+		t.Error("A missing alternate directory should be rejected")
+	}
+
+	alternate := t.TempDir()
+	m.dirInput.SetValue(alternate)
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if cmd == nil || m.resumeDir != alternate || m.selectedSession == nil {
+		t.Error("An existing alternate directory should be accepted and quit")
+	}
+
+	// An explicit directory from --cwd skips the prompt
+	m = initialModel(projects)
+	m.opts.ResumeDir = alternate
+	m.selectedProject = &projects[0]
+	m.currentMode = sessionView
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.dirPrompt || m.resumeDir != alternate {
+		t.Error("ResumeDir should be used without prompting")
+	}
+}
+
+// TestProjectPaging tests that PgDn/PgUp request the adjacent page of projects
+func TestProjectPaging(t *testing.T) {
+	limit := sessions.PageLimit()
+	m := initialModel([]models.Project{{Name: "p1", Path: "/p1"}})
+	m.projectTotal = limit + 5
+
+	if got := m.pageRange(); got != sessions.FormatPageRange(0, 1, limit+5) {
+		t.Errorf("unexpected page range %q", got)
+	}
+
+	// PgUp on the first page does nothing
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	m = updatedModel.(model)
+	if cmd != nil || m.loadingState != sessions.StateIdle {
+		t.Error("PgUp on the first page should not load anything")
+	}
+
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	m = updatedModel.(model)
+	if cmd == nil || m.loadingState != sessions.StateLoadingProjects {
+		t.Fatal("PgDn should load the next page of projects")
+	}
+
+	updatedModel, _ = m.Update(ProjectsLoadedMsg{
+		Projects: []models.Project{{Name: "p2", Path: "/p2"}},
+		Offset:   limit,
+		Total:    limit + 5,
+	})
+	m = updatedModel.(model)
+	if m.projectOffset != limit || m.projects[0].Name != "p2" {
+		t.Error("The loaded page should replace the project list")
+	}
+
+	// The last page has no successor
+	m.loadingState = sessions.StateIdle
+	if _, ok := pageOffset(m.projectOffset, m.projectTotal, 1); ok {
+		t.Error("There should be no page after the last one")
+	}
+}
+
+// TestHeaderCounts tests that the header shows the total number of projects and sessions
+func TestHeaderCounts(t *testing.T) {
+	m := initialModel([]models.Project{{Name: "p1", Path: "/p1"}})
+	m.projectTotal = 237
+
+	if header := m.renderHeader(); !strings.Contains(header, "237 projects") {
+		t.Errorf("expected the project total in the header, got %q", header)
+	}
+
+	m.selectedProject = &models.Project{Name: "p1", Path: "/p1", Sessions: []models.Session{{SessionID: "s1"}}}
+	m.currentMode = sessionView
+	m.sessionTotal = 1
+	if header := m.renderHeader(); !strings.Contains(header, "1 session)") {
+		t.Errorf("expected the session total in the header, got %q", header)
+	}
+}
+
+// TestWatchDebounce tests that a refresh waits for session file writes to settle
+func TestWatchDebounce(t *testing.T) {
+	m := initialModel([]models.Project{{Name: "p1", Path: "/p1"}})
+	start := time.Now()
+	m.watchModTime = start
+
+	// A newer modification time is only noted at first
+	changed := start.Add(time.Second)
+	updatedModel, _ := m.Update(FilesCheckedMsg{ModTime: changed, CheckedAt: start})
+	m = updatedModel.(model)
+	if !m.watchModTime.Equal(start) || !m.watchPending.Equal(changed) {
+		t.Fatal("The first change should be held back until writes settle")
+	}
+
+	// Further writes keep postponing the refresh
+	changedAgain := changed.Add(time.Second)
+	updatedModel, _ = m.Update(FilesCheckedMsg{ModTime: changedAgain, CheckedAt: start.Add(watchInterval)})
+	m = updatedModel.(model)
+	if !m.watchModTime.Equal(start) {
+		t.Fatal("Ongoing writes should postpone the refresh")
+	}
+
+	// Once the modification time is stable the listings refresh
+	updatedModel, cmd := m.Update(FilesCheckedMsg{ModTime: changedAgain, CheckedAt: start.Add(2 * watchInterval)})
+	m = updatedModel.(model)
+	if !m.watchModTime.Equal(changedAgain) || cmd == nil {
+		t.Error("A settled change should trigger a refresh")
+	}
+
+	// Continuous writes still refresh after watchMaxDelay
+	m.watchPending = time.Time{}
+	base := m.watchModTime
+	updatedModel, _ = m.Update(FilesCheckedMsg{ModTime: base.Add(time.Second), CheckedAt: start})
+	m = updatedModel.(model)
+	updatedModel, _ = m.Update(FilesCheckedMsg{ModTime: base.Add(2 * time.Second), CheckedAt: start.Add(watchMaxDelay)})
+	m = updatedModel.(model)
+	if !m.watchModTime.Equal(base.Add(2 * time.Second)) {
+		t.Error("Continuous writes should refresh after watchMaxDelay")
+	}
+}
+
+// TestSessionsRefreshKeepsCursor tests that a background refresh keeps the cursor on the same session
+func TestSessionsRefreshKeepsCursor(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	project := models.Project{
+		Name: "p1",
+		Path: "/p1",
+		Sessions: []models.Session{
+			{SessionID: "a", LastActivity: old, Summary: "A"},
+			{SessionID: "b", LastActivity: old, Summary: "B"},
+		},
+	}
+	m := initialModel([]models.Project{project})
+	m.leftViewport.Width = 80
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.sessionCursor = 1
+	m.messageCache["a"] = previewLines("stale")
+	m.messageCache["b"] = previewLines("current")
+
+	// Session a had new activity and moves to the top
+	updatedModel, _ := m.Update(SessionsLoadedMsg{
+		ProjectPath: "/p1",
+		Sessions: []models.Session{
+			{SessionID: "a", LastActivity: time.Now()},
+			{SessionID: "b", LastActivity: old},
+		},
+		Total:   2,
+		Refresh: true,
+	})
+	m = updatedModel.(model)
+
+	if m.selectedProject.Sessions[m.sessionCursor].SessionID != "b" {
+		t.Error("The cursor should stay on the same session")
+	}
+	if _, ok := m.messageCache["a"]; ok {
+		t.Error("The preview of the updated session should be invalidated")
+	}
+	if _, ok := m.messageCache["b"]; !ok {
+		t.Error("The preview of an unchanged session should stay cached")
+	}
+	if m.selectedProject.Sessions[1].Summary != "B" {
+		t.Error("Summaries should be kept until they reload")
+	}
+}
+
+// TestManualRefresh tests that ctrl+r reloads the listings in the background,
+// showing the spinner until every reload arrives
+func TestManualRefresh(t *testing.T) {
+	project := models.Project{Name: "p1", Path: "/p1", Sessions: []models.Session{{SessionID: "a"}, {SessionID: "b"}}}
+	m := initialModel([]models.Project{project})
+	m.leftViewport.Width = 80
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.sessionCursor = 1
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updatedModel.(model)
+	if m.refreshing != 2 || cmd == nil {
+		t.Fatalf("refreshing = %d, want the projects and sessions reloading", m.refreshing)
+	}
+	if !strings.Contains(m.renderFooter(), "Refreshing") {
+		t.Error("The footer should show the refresh in progress")
+	}
+
+	// A second ctrl+r waits for the first refresh
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updatedModel.(model)
+	if m.refreshing != 2 || cmd != nil {
+		t.Error("A refresh in progress shouldn't be started again")
+	}
+
+	updatedModel, _ = m.Update(ProjectsLoadedMsg{Projects: []models.Project{project}, Total: 1, Refresh: true})
+	m = updatedModel.(model)
+	updatedModel, _ = m.Update(SessionsLoadedMsg{
+		ProjectPath: "/p1",
+		Sessions:    []models.Session{{SessionID: "c"}, {SessionID: "b"}, {SessionID: "a"}},
+		Total:       3,
+		Refresh:     true,
+	})
+	m = updatedModel.(model)
+	if m.refreshing != 0 || strings.Contains(m.renderFooter(), "Refreshing") {
+		t.Error("The refresh should end once the listings arrive")
+	}
+	if m.selectedProject.Sessions[m.sessionCursor].SessionID != "b" {
+		t.Error("The cursor should stay on the same session")
+	}
+
+	// A failed refresh is reported
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updatedModel.(model)
+	updatedModel, _ = m.Update(ProjectsLoadedMsg{Error: fmt.Errorf("disk gone"), Refresh: true})
+	m = updatedModel.(model)
+	if !strings.Contains(m.statusMessage, "disk gone") {
+		t.Errorf("status = %q, want the refresh error", m.statusMessage)
+	}
+}
+
+// TestUndoDelete tests that a deleted session can be restored with u for the rest of the run
+func TestUndoDelete(t *testing.T) {
+	project := models.Project{Name: "p1", Path: "/p1", SessionCount: 2, Sessions: []models.Session{{SessionID: "a"}, {SessionID: "b"}}}
+	m := initialModel([]models.Project{project})
+	m.leftViewport.Width = 80
+	m.selectedProject = &project
+	m.currentMode = sessionView
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	m = updatedModel.(model)
+	if m.statusMessage != "Nothing to undo" {
+		t.Errorf("status = %q, want nothing to undo before a delete", m.statusMessage)
+	}
+
+	updatedModel, _ = m.Update(SessionDeletedMsg{SessionID: "a"})
+	m = updatedModel.(model)
+	if len(m.selectedProject.Sessions) != 1 || !strings.Contains(m.statusMessage, "press u to undo") {
+		t.Fatalf("sessions = %+v, status = %q, want a removed with an undo hint", m.selectedProject.Sessions, m.statusMessage)
+	}
+
+	// The undo still works once the hint is gone
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updatedModel.(model)
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	m = updatedModel.(model)
+	if cmd == nil || m.statusMessage != "Restoring session..." {
+		t.Fatalf("status = %q, want the session restoring", m.statusMessage)
+	}
+
+	updatedModel, cmd = m.Update(SessionRestoredMsg{SessionID: "a"})
+	m = updatedModel.(model)
+	if m.lastDeleted != "" || m.statusMessage != "Session restored" || cmd == nil {
+		t.Errorf("status = %q, want the session restored and the listings reloading", m.statusMessage)
+	}
+}
+
+// TestDeleteConfirmation tests that d asks before deleting a session, y
+// confirming the delete and any other key cancelling it
+func TestDeleteConfirmation(t *testing.T) {
+	project := models.Project{Name: "p1", Path: "/p1", SessionCount: 1, Sessions: []models.Session{{SessionID: "a", Summary: "Fix login"}}}
+	m := initialModel([]models.Project{project})
+	m.leftViewport.Width = 80
+	m.selectedProject = &project
+	m.currentMode = sessionView
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m = updatedModel.(model)
+	if m.pendingDelete == nil || m.pendingDelete.SessionID != "a" {
+		t.Fatal("d should ask to confirm the delete of the selected session")
+	}
+	if !strings.Contains(m.renderFooter(), "Fix login") {
+		t.Errorf("footer should name the session to delete: %q", m.renderFooter())
+	}
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m = updatedModel.(model)
+	if m.pendingDelete != nil || cmd != nil || m.statusMessage != "Delete cancelled" {
+		t.Errorf("status = %q, want any other key to cancel the delete", m.statusMessage)
+	}
+	if m.sessionCursor != 0 {
+		t.Error("the cancelling key should not also move the cursor")
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m = updatedModel.(model)
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updatedModel.(model)
+	if m.pendingDelete != nil || cmd == nil || m.statusMessage != "Deleting session..." {
+		t.Errorf("status = %q, want y to delete the session", m.statusMessage)
+	}
+}
+
+// TestSessionLabel tests labeling a session with n, the label taking every
+// typed key until it is saved with enter
+func TestSessionLabel(t *testing.T) {
+	sessions.SetLabelsPath(filepath.Join(t.TempDir(), "labels.json"))
+	t.Cleanup(func() { sessions.SetLabelsPath("") })
+
+	project := models.Project{Name: "p1", Path: "/p1", SessionCount: 1, Sessions: []models.Session{{SessionID: "a", Summary: "Fix login"}}}
+	m := initialModel([]models.Project{project})
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.leftViewport.Width = 60
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updatedModel.(model)
+	if m.labelTarget == nil {
+		t.Fatal("n should ask for a label")
+	}
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("quick fix")})
+	m = updatedModel.(model)
+	if m.labelTarget == nil || m.currentMode != sessionView {
+		t.Fatal("typed keys should go to the label")
+	}
+	if !strings.Contains(m.renderFooter(), "quick fix") {
+		t.Errorf("footer should show the label being typed: %q", m.renderFooter())
+	}
+
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.labelTarget != nil || cmd == nil {
+		t.Fatal("enter should save the label")
+	}
+	updatedModel, _ = m.Update(cmd())
+	m = updatedModel.(model)
+	if m.selectedProject.Sessions[0].Label != "quick fix" || m.statusMessage != "Labeled quick fix" {
+		t.Fatalf("label = %q, status = %q", m.selectedProject.Sessions[0].Label, m.statusMessage)
+	}
+	if !strings.Contains(m.renderSessionsList(), "[quick fix] Fix login") {
+		t.Errorf("session list should show the label in brackets:\n%s", m.renderSessionsList())
+	}
+	if labels, _ := sessions.LoadLabels(); labels["a"] != "quick fix" {
+		t.Errorf("labels file = %v, want a labeled", labels)
+	}
+}
+
+// TestConversationView tests opening and closing the full conversation view
+func TestConversationView(t *testing.T) {
+	project := models.Project{
+		Name:     "p1",
+		Path:     "/p1",
+		Sessions: []models.Session{{SessionID: "s1"}},
+	}
+	m := initialModel([]models.Project{project})
+	m.leftViewport.Width = 80
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.viewport.Width = 80
+	m.viewport.Height = 20
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	m = updatedModel.(model)
+	if m.currentMode != messageView || cmd == nil {
+		t.Fatal("v should open the conversation view and load it")
+	}
+
+	long := strings.Repeat("word ", 100)
+	updatedModel, _ = m.Update(ConversationLoadedMsg{
+		SessionID: "s1",
+		Messages: []models.Message{
+			{Role: "user", Content: "first line\n\nsecond paragraph"},
+			{Role: "assistant", Content: long, ToolCalls: []models.ToolCall{{Name: "Bash", Input: `{"command": "ls"}`}}},
+		},
+	})
+	m = updatedModel.(model)
+	if m.loadingState != sessions.StateIdle {
+		t.Error("Loading should finish once the conversation arrives")
+	}
+
+	content := m.renderConversation()
+	for _, want := range []string{"User", "second paragraph", "Assistant", "Bash", `"command": "ls"`} {
+		if !strings.Contains(content, want) {
+			t.Errorf("conversation should contain %q", want)
+		}
+	}
+	if strings.Count(content, "word") != 100 {
+		t.Error("Message bodies should not be truncated")
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updatedModel.(model)
+	if m.currentMode != sessionView || m.conversation != nil {
+		t.Error("Esc should return to the split view")
+	}
+}
+
+// TestNewestFirst tests that O reverses the conversation without reloading it,
+// with the pauses between messages described from the newer one
+func TestNewestFirst(t *testing.T) {
+	m := initialModel(nil)
+	m.currentMode = messageView
+	m.opts.NoMarkdown = true
+	m.viewport.Width = 80
+	m.viewport.Height = 20
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	m.conversation = []models.Message{
+		{Role: "user", Content: "oldest question", Timestamp: start},
+		{Role: "assistant", Content: "newest answer", Timestamp: start.Add(3 * time.Hour)},
+	}
+
+	content := m.renderConversation()
+	if strings.Index(content, "oldest question") > strings.Index(content, "newest answer") || !strings.Contains(content, "3h later") {
+		t.Errorf("expected the oldest message first by default:\n%s", content)
+	}
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}})
+	m = updatedModel.(model)
+	if !m.opts.NewestFirst {
+		t.Fatal("O should show the newest messages first")
+	}
+	if cmd == nil {
+		t.Error("O should flash the new order")
+	}
+	content = m.renderConversation()
+	if strings.Index(content, "newest answer") > strings.Index(content, "oldest question") || !strings.Contains(content, "3h earlier") {
+		t.Errorf("expected the newest message first:\n%s", content)
+	}
+	if m.conversation[0].Content != "oldest question" {
+		t.Error("the loaded conversation should be left in order")
+	}
+}
+
+// TestLongMessageCap tests that long messages are cut to MessageLines in the
+// conversation view until expanded with e
+func TestLongMessageCap(t *testing.T) {
+	m := initialModel(nil)
+	m.currentMode = messageView
+	m.opts.NoMarkdown = true
+	m.opts.MessageLines = 3
+	m.viewport.Width = 80
+	m.viewport.Height = 20
+	var lines []string
+	for i := 1; i <= 50000; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	m.conversation = []models.Message{
+		{Role: "assistant", Content: strings.Join(lines, "\n")},
+		{Role: "user", ToolResults: []string{strings.Repeat("x", 1000000)}},
+	}
+
+	content := m.renderConversation()
+	if !strings.Contains(content, "line 3\n") || strings.Contains(content, "line 4\n") || !strings.Contains(content, "… 49997 more lines (e to expand)") {
+		t.Errorf("expected the message cut to 3 lines:\n%s", content)
+	}
+	if strings.Count(content, "x") > 3*78 || !strings.Contains(content, "… 1 more line ") {
+		t.Errorf("expected the single-line tool result cut too:\n%s", content)
+	}
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	m = updatedModel.(model)
+	if !m.expandMessages || cmd == nil {
+		t.Fatal("e should expand the long messages")
+	}
+	m.conversation = m.conversation[:1]
+	content = m.renderConversation()
+	if !strings.Contains(content, "line 50000") || strings.Contains(content, "more lines") {
+		t.Error("expanded messages should be shown in full")
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updatedModel.(model).expandMessages {
+		t.Error("leaving the conversation should cut long messages again")
+	}
+}
+
+// TestRenderMarkdown tests Markdown rendering in the conversation view
+func TestRenderMarkdown(t *testing.T) {
+	m := initialModel(nil)
+	longLine := "    result := someFunction(" + strings.Repeat("argument, ", 20) + ")"
+	text := strings.Join([]string{
+		"# Heading",
+		"Some **bold** and `code` text.",
+		"- first item",
+		"2. second item",
+		"```go",
+		"func main() {",
+		"\tfmt.Println(\"hi\")",
+		longLine,
+		"}",
+		"```",
+	}, "\n")
+
+	rendered := strings.Join(m.renderMarkdown(text, 60, lipgloss.NewStyle()), "\n")
+
+	for _, want := range []string{"Heading", "bold", "code", "• first item", "2. second item", "go", "func main() {", "│     fmt.Println(\"hi\")"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("rendered Markdown should contain %q:\n%s", want, rendered)
+		}
+	}
+	for _, marker := range []string{"# Heading", "**", "```"} {
+		if strings.Contains(rendered, marker) {
+			t.Errorf("rendered Markdown should not contain the marker %q", marker)
+		}
+	}
+
+	// Code lines are truncated, never wrapped onto a second line
+	if strings.Contains(rendered, "argument, )") {
+		t.Error("long code lines should be truncated")
+	}
+	for _, line := range strings.Split(rendered, "\n") {
+		if lipgloss.Width(line) > 60 {
+			t.Errorf("line exceeds the width: %q", line)
+		}
+	}
+}
+
+// TestRenderMarkdownDisabled tests that NoMarkdown shows the raw text
+func TestRenderMarkdownDisabled(t *testing.T) {
+	m := initialModel(nil)
+	m.opts.NoMarkdown = true
+	m.viewport.Width = 80
+	m.conversation = []models.Message{{Role: "assistant", Content: "Some **bold** text"}}
+
+	if content := m.renderConversation(); !strings.Contains(content, "**bold**") {
+		t.Error("NoMarkdown should keep the Markdown markers")
+	}
+}
+
+// TestToolTimeline tests the tool invocation timeline view
+func TestToolTimeline(t *testing.T) {
+	project := models.Project{
+		Name:     "p1",
+		Path:     "/p1",
+		Sessions: []models.Session{{SessionID: "s1"}},
+	}
+	m := initialModel([]models.Project{project})
+	m.leftViewport.Width = 80
+	m.selectedProject = &project
+	m.currentMode = sessionView
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m = updatedModel.(model)
+	if m.currentMode != toolView || cmd == nil {
+		t.Fatal("t should open the tool timeline and load it")
+	}
+
+	updatedModel, _ = m.Update(ToolUsageLoadedMsg{
+		SessionID: "s1",
+		ToolCalls: []models.ToolCall{
+			{Name: "Read", Input: `{"file_path": "/src/main.go"}`},
+			{Name: "Bash", Input: `{"command": "go test ./..."}`},
+		},
+	})
+	m = updatedModel.(model)
+
+	timeline := m.renderToolTimeline()
+	read, bash := strings.Index(timeline, "/src/main.go"), strings.Index(timeline, "go test ./...")
+	if read < 0 || bash < 0 || read > bash {
+		t.Errorf("timeline should list the calls in order:\n%s", timeline)
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updatedModel.(model)
+	if m.currentMode != sessionView || m.toolCalls != nil {
+		t.Error("Esc should return to the split view")
+	}
+}
+
+// TestDiskCache tests that loaded previews are persisted and warm the next run
+func TestDiskCache(t *testing.T) {
+	store, err := cache.Load(filepath.Join(t.TempDir(), "messages.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModel(nil)
+	m.diskCache = store
+	modTime := time.Now().Truncate(time.Second)
+	updatedModel, _ := m.Update(MessagesLoadedMsg{SessionID: "s1", Messages: previewLines("User: hi"), MessageCount: 1, ModTime: modTime})
+	m = updatedModel.(model)
+	// Without a known modification time the preview can't be validated later
+	updatedModel, _ = m.Update(MessagesLoadedMsg{SessionID: "s2", Messages: previewLines("User: yo"), MessageCount: 1})
+	m = updatedModel.(model)
+
+	if entry, ok := store.Entries()["s1"]; !ok || !entry.ModTime.Equal(modTime) {
+		t.Fatalf("expected s1 to be stored with its modification time, got %+v", entry)
+	}
+	if _, ok := store.Entries()["s2"]; ok {
+		t.Error("expected s2 not to be stored")
+	}
+
+	next := initialModel(nil)
+	next.diskCache = store
+	next.warmFromDiskCache()
+	if cached := next.messageCache["s1"]; len(cached) != 1 || cached[0].Text != "User: hi" || next.messageCounts["s1"] != 1 {
+		t.Errorf("expected the next run to be warmed from disk, got %v", cached)
+	}
+
+	next.forgetMessages("s1")
+	if _, ok := store.Entries()["s1"]; ok {
+		t.Error("expected forgetting a session to drop it from disk")
+	}
+}
+
+// TestResumedFromIndicator tests that resume links are shown in the session list
+func TestResumedFromIndicator(t *testing.T) {
+	m := initialModel(nil)
+	project := models.Project{Name: "p", Path: "/p", Sessions: []models.Session{
+		{SessionID: "child-session-id", IsResumed: true},
+		{SessionID: "parent-session-id"},
+	}}
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.leftViewport.Width = 60
+
+	updatedModel, _ := m.Update(SummariesLoadedMsg{
+		ProjectPath: "/p",
+		Summaries:   map[string]string{},
+		ResumedFrom: map[string]string{"child-session-id": "parent-session-id"},
+	})
+	m = updatedModel.(model)
+
+	if got := m.selectedProject.Sessions[0].ResumedFrom; got != "parent-session-id" {
+		t.Fatalf("expected the parent to be recorded, got %q", got)
+	}
+	list := m.renderSessionsList()
+	if !strings.Contains(list, "↳ resumed from parent-session...") {
+		t.Errorf("expected a resume indicator in:\n%s", list)
+	}
+	if strings.Count(list, "↳ resumed from") != 1 {
+		t.Errorf("expected a single resume indicator in:\n%s", list)
+	}
+}
+
+// TestSummariesArriveInChunks tests that each chunk of summaries is shown as it
+// arrives and that the next chunk is only awaited while its project is shown
+func TestSummariesArriveInChunks(t *testing.T) {
+	m := initialModel(nil)
+	m.leftViewport.Width = 80
+	project := models.Project{Name: "p", Path: "/p", Sessions: []models.Session{
+		{SessionID: "first"},
+		{SessionID: "second"},
+	}}
+	m.selectedProject = &project
+	m.currentMode = sessionView
+
+	next := func() tea.Msg { return nil }
+	updatedModel, cmd := m.Update(SummariesLoadedMsg{
+		ProjectPath: "/p",
+		Summaries:   map[string]string{"first": "First summary"},
+		Sources:     map[string]models.SummarySource{"first": models.FirstReply},
+		Next:        next,
+	})
+	m = updatedModel.(model)
+
+	if got := m.selectedProject.Sessions[0].Summary; got != "First summary" {
+		t.Errorf("expected the first chunk to be shown, got %q", got)
+	}
+	if got := m.selectedProject.Sessions[0].SummarySource; got != models.FirstReply {
+		t.Errorf("expected the source of the summary to be kept, got %v", got)
+	}
+	if m.selectedProject.Sessions[1].Summary != "" {
+		t.Errorf("expected the second session to wait for its chunk")
+	}
+	if cmd == nil {
+		t.Error("expected the next chunk to be awaited")
+	}
+
+	other := models.Project{Name: "q", Path: "/q"}
+	m.selectedProject = &other
+	if _, cmd := m.Update(SummariesLoadedMsg{ProjectPath: "/p", Next: next}); cmd != nil {
+		t.Error("expected chunks of a project no longer shown to be dropped")
+	}
+}
+
+// TestNoColor tests that disabling color renders every style as plain text
+func TestNoColor(t *testing.T) {
+	m := initialModel([]models.Project{{Name: "alpha", Path: "/alpha", SessionCount: 2, LastActivity: time.Now()}})
+	m.renderer = newRenderer(false)
+	m.loadingIndicator.SetRenderer(m.renderer)
+	m.width, m.height = 80, 24
+
+	for name, out := range map[string]string{
+		"header":   m.renderHeader(),
+		"projects": m.renderProjects(),
+		"footer":   m.renderFooter(),
+		"loading":  LoadingOverlay(40, 5, m.loadingIndicator),
+	} {
+		if strings.Contains(out, "\x1b[") {
+			t.Errorf("%s contains ANSI escapes with color disabled: %q", name, out)
+		}
+	}
+	if !strings.Contains(m.renderProjects(), "alpha") {
+		t.Error("expected the project to still be listed")
+	}
+
+	// Sanity check that the same views are styled when color is forced
+	m.renderer = lipgloss.NewRenderer(os.Stdout)
+	m.renderer.SetColorProfile(termenv.ANSI256)
+	if !strings.Contains(m.renderHeader(), "\x1b[") {
+		t.Error("expected ANSI escapes with color enabled")
+	}
+}
+
+// TestRecentView tests the flat list of recent sessions across projects
+func TestRecentView(t *testing.T) {
+	dir := t.TempDir()
+	m := initialModel([]models.Project{{Name: "p1", Path: dir}})
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updatedModel.(model)
+	if m.currentMode != recentView || cmd == nil {
+		t.Fatal("r should open the recent sessions view and load it")
+	}
+
+	updatedModel, _ = m.Update(RecentSessionsLoadedMsg{Sessions: []models.Session{
+		{SessionID: "s2", ProjectPath: dir, Summary: "Newest"},
+		{SessionID: "s1", ProjectPath: "/elsewhere/other", Summary: "Older"},
+	}})
+	m = updatedModel.(model)
+	if m.loadingState != sessions.StateIdle || len(m.recentSessions) != 2 {
+		t.Fatal("Loaded recent sessions should be shown")
+	}
+	if content := m.renderRecentSessions(); !strings.Contains(content, "["+filepath.Base(dir)+"]") || !strings.Contains(content, "[other]") {
+		t.Errorf("Recent sessions should be annotated with their project names, got %q", content)
+	}
+
+	// Selecting a session goes straight to the confirmation screen, and cancelling returns here
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if m.currentMode != confirmView || m.selectedSession == nil || m.selectedSession.SessionID != "s2" {
+		t.Fatal("Enter should select the recent session for resuming")
+	}
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updatedModel.(model)
+	if m.currentMode != recentView || m.selectedSession != nil {
+		t.Error("Cancelling should return to the recent sessions view")
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updatedModel.(model)
+	if m.currentMode != projectView {
+		t.Error("Esc should return to the project list")
+	}
+}
+
+// TestToggleFavorite tests that starring a session marks it and reloads the
+// page, which lists it first with the cursor still on it
+func TestToggleFavorite(t *testing.T) {
+	now := time.Now()
+	project := models.Project{
+		Name: "test",
+		Path: "/test",
+		Sessions: []models.Session{
+			{SessionID: "s1", LastActivity: now},
+			{SessionID: "s2", LastActivity: now.Add(-time.Hour)},
+		},
+	}
+
+	m := initialModel([]models.Project{project})
+	m.leftViewport.Width = 80
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.sessionCursor = 1
+
+	updatedModel, cmd := m.Update(FavoriteToggledMsg{SessionID: "s2", Favorite: true})
+	m = updatedModel.(model)
+	if !m.selectedProject.Sessions[1].Favorite || cmd == nil {
+		t.Fatal("A starred session should be marked and the page reloaded")
+	}
+	if !strings.Contains(m.renderSessionsList(), "★") {
+		t.Error("Starred sessions should show a star")
+	}
+
+	// The reloaded page lists favorites first
+	updatedModel, _ = m.Update(SessionsLoadedMsg{
+		ProjectPath: "/test",
+		Refresh:     true,
+		Total:       2,
+		Sessions: []models.Session{
+			{SessionID: "s2", LastActivity: now.Add(-time.Hour), Favorite: true},
+			{SessionID: "s1", LastActivity: now},
+		},
+	})
+	m = updatedModel.(model)
+	if m.selectedProject.Sessions[0].SessionID != "s2" || m.sessionCursor != 0 {
+		t.Error("The cursor should follow the starred session to its new place")
+	}
+}
+
+// TestJumpNavigation tests the jump and half-page keys and that the viewport follows the cursor
+func TestJumpNavigation(t *testing.T) {
+	var projects []models.Project
+	for i := 0; i < 50; i++ {
+		projects = append(projects, models.Project{Name: fmt.Sprintf("p%d", i), Path: fmt.Sprintf("/p%d", i)})
+	}
+	m := initialModel(projects)
+	updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 13})
+	m = updatedModel.(model)
+
+	press := func(key tea.KeyMsg) {
+		updatedModel, _ := m.Update(key)
+		m = updatedModel.(model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	if m.projectCursor != 49 {
+		t.Fatalf("G should jump to the last project, got %d", m.projectCursor)
+	}
+	if m.viewport.YOffset != 50-m.viewport.Height {
+		t.Errorf("The last project should be scrolled into view, offset %d", m.viewport.YOffset)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlU})
+	if m.projectCursor != 49-m.viewport.Height/2 {
+		t.Errorf("ctrl+u should move up half a page, got %d", m.projectCursor)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyHome})
+	if m.projectCursor != 0 || m.viewport.YOffset != 0 {
+		t.Errorf("Home should jump to the first project and scroll to the top, got %d at offset %d", m.projectCursor, m.viewport.YOffset)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if m.projectCursor != m.viewport.Height/2 {
+		t.Errorf("ctrl+d should move down half a page, got %d", m.projectCursor)
+	}
+
+	// Sessions take several lines each, so the whole last session must fit in the left viewport
+	project := models.Project{Name: "p", Path: "/p"}
+	for i := 0; i < 10; i++ {
+		session := models.Session{SessionID: fmt.Sprintf("s%d", i)}
+		if i%2 == 0 {
+			session.ResumedFrom = "parent"
+		}
+		project.Sessions = append(project.Sessions, session)
+		m.messageCache[session.SessionID] = previewLines()
+	}
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.sessionCursor = 0
+
+	press(tea.KeyMsg{Type: tea.KeyEnd})
+	if m.sessionCursor != 9 {
+		t.Fatalf("End should jump to the last session, got %d", m.sessionCursor)
+	}
+	first, last := m.sessionLines(9)
+	if first < m.leftViewport.YOffset || last >= m.leftViewport.YOffset+m.leftViewport.Height {
+		t.Errorf("Lines %d-%d of the last session should be visible at offset %d", first, last, m.leftViewport.YOffset)
+	}
+	if lines := strings.Split(m.renderSessionsList(), "\n"); !strings.Contains(lines[last], "s9") {
+		t.Errorf("sessionLines should match the rendering, line %d is %q", last, lines[last])
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	if m.sessionCursor != 0 || m.leftViewport.YOffset != 0 {
+		t.Errorf("g should jump to the first session and show the list header, got %d at offset %d", m.sessionCursor, m.leftViewport.YOffset)
+	}
+}
+
+// TestHelpOverlay tests that ? shows every keybinding and any key dismisses it
+func TestHelpOverlay(t *testing.T) {
+	m := initialModel([]models.Project{{Name: "alpha", Path: "/alpha"}})
+	m.renderer = newRenderer(false)
+	updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+	m = updatedModel.(model)
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = updatedModel.(model)
+	if !m.showHelp {
+		t.Fatal("? should open the help overlay")
+	}
+
+	view := m.View()
+	for _, binding := range keyMap {
+		if !strings.Contains(view, binding.help) {
+			t.Errorf("help overlay is missing %q", binding.help)
+		}
+	}
+	if lines := strings.Split(view, "\n"); len(lines) != m.height {
+		t.Errorf("the overlay should cover the screen, got %d lines", len(lines))
+	}
+
+	// The key closing the overlay is not acted on
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = updatedModel.(model)
+	if m.showHelp || cmd != nil {
+		t.Error("any key should only close the help overlay")
+	}
+}
+
+// TestFooterHints tests that the footer is generated from the keymap for the current view
+func TestFooterHints(t *testing.T) {
+	m := initialModel([]models.Project{{Name: "alpha", Path: "/alpha"}})
+	m.renderer = newRenderer(false)
+
+	if got, want := m.renderFooter(), "↑/↓: navigate • enter: select • r: recent • ?: help • q: quit"; got != want {
+		t.Errorf("project footer = %q, want %q", got, want)
+	}
+
+	m.currentMode = recentView
+	if got := m.renderFooter(); !strings.Contains(got, "s: star") || !strings.Contains(got, "r/esc: projects") {
+		t.Errorf("unexpected recent footer %q", got)
+	}
+
+	m.currentMode = projectView
+	m.loadingState = sessions.StateLoadingProjects
+	if got, want := m.renderFooter(), "esc: cancel • ?: help • q: quit"; got != want {
+		t.Errorf("loading footer = %q, want %q", got, want)
+	}
+}
+
+// TestToggleMessagesOnly tests that m switches the preview mode and reloads the preview
+func TestToggleMessagesOnly(t *testing.T) {
+	t.Cleanup(func() { sessions.SetMessagesOnly(false) })
+
+	project := models.Project{Name: "test", Path: "/test", Sessions: []models.Session{{SessionID: "s1"}}}
+	m := initialModel([]models.Project{project})
+	m.leftViewport.Width = 80
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.messageCache["s1"] = previewLines("[User] hi | ↩ output")
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m = updatedModel.(model)
+	if !sessions.MessagesOnly() {
+		t.Fatal("m should hide tool calls")
+	}
+	if _, ok := m.messageCache["s1"]; ok || !m.loadingMessages["s1"] || cmd == nil {
+		t.Error("the preview should be reloaded in the new mode")
+	}
+
+	// A preview formatted in the old mode is ignored
+	updatedModel, _ = m.Update(MessagesLoadedMsg{SessionID: "s1", Messages: previewLines("[User] hi | ↩ output")})
+	m = updatedModel.(model)
+	if _, ok := m.messageCache["s1"]; ok || !m.loadingMessages["s1"] {
+		t.Error("a stale preview should not be cached")
+	}
+
+	updatedModel, _ = m.Update(MessagesLoadedMsg{SessionID: "s1", Messages: previewLines("[User] hi"), MessagesOnly: true})
+	m = updatedModel.(model)
+	if got := m.messageCache["s1"]; len(got) != 1 || got[0].Text != "[User] hi" {
+		t.Errorf("the new preview should be cached, got %v", got)
+	}
+}
+
+// TestEmptyProjectsGuidance tests that an empty project list shows the guidance
+func TestEmptyProjectsGuidance(t *testing.T) {
+	m := initialModel(nil)
+	m.renderer = newRenderer(false)
+	updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updatedModel.(model)
+
+	updatedModel, _ = m.Update(ProjectsLoadedMsg{EmptyGuidance: "No sessions yet — run `claude` in a project first."})
+	m = updatedModel.(model)
+	if !strings.Contains(m.View(), "No sessions yet") {
+		t.Error("the guidance should be shown in place of the project list")
+	}
+
+	updatedModel, _ = m.Update(ProjectsLoadedMsg{Projects: []models.Project{{Name: "p1", Path: "/p1"}}, Total: 1, Refresh: true})
+	m = updatedModel.(model)
+	if strings.Contains(m.View(), "No sessions yet") {
+		t.Error("the guidance should go away once projects show up")
+	}
+}
+
+// TestPreviewTimestamps tests that preview times and gap dividers are rendered
+func TestPreviewTimestamps(t *testing.T) {
+	project := models.Project{Name: "test", Path: "/test", Sessions: []models.Session{{SessionID: "s1"}}}
+	m := initialModel([]models.Project{project})
+	m.renderer = newRenderer(false)
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.rightViewport.Width = 80
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	m.currentMessages = []sessions.PreviewLine{
+		{Text: "[User] hello", Time: start},
+		{Text: "⏱ 3h later"},
+		{Text: "[Assistant] welcome back", Time: start.Add(3*time.Hour + 5*time.Minute)},
+	}
+
+	view := m.renderMessages()
+	for _, want := range []string{"[10:00] [User] hello", "⏱ 3h later", "[13:05] [Assistant] welcome back"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the preview:\n%s", want, view)
+		}
+	}
+}
+
+// TestPreviewFitsPane tests that preview messages wrap within the width of the pane
+func TestPreviewFitsPane(t *testing.T) {
+	project := models.Project{Name: "test", Path: "/test", Sessions: []models.Session{{SessionID: "s1"}}}
+	m := initialModel([]models.Project{project})
+	m.renderer = newRenderer(false)
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.currentMessages = previewLines("[User] " + strings.Repeat("word ", 40))
+
+	for _, width := range []int{40, 120} {
+		m.rightViewport.Width = width
+		view := m.renderMessages()
+		for _, line := range strings.Split(view, "\n") {
+			if got := lipgloss.Width(line); got > width {
+				t.Errorf("a preview in a %d column pane should fit it, got %d columns: %q", width, got, line)
+			}
+		}
+		if got := strings.Count(view, "word"); got != 40 {
+			t.Errorf("a preview in a %d column pane should wrap the whole message, got %d of 40 words", width, got)
+		}
+	}
+
+	// Continuation lines line up under the text, past the time and the role
+	m.rightViewport.Width = 40
+	m.currentMessages = []sessions.PreviewLine{{Text: "[User] " + strings.Repeat("word ", 20), Time: time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)}}
+	if !strings.Contains(m.renderMessages(), "\n"+strings.Repeat(" ", len("[10:00] [User] "))+"word") {
+		t.Errorf("continuation lines should be indented past the time and the role:\n%s", m.renderMessages())
+	}
+
+	// A tiny pane still shows the start of the message
+	m.rightViewport.Width = 5
+	if !strings.Contains(m.renderMessages(), "[User] word word") {
+		t.Error("a tiny pane should keep a readable minimum")
+	}
+}
+
+// TestTruncateSummaryMultibyte tests that summaries with wide and multibyte characters are cut by width
+func TestTruncateSummaryMultibyte(t *testing.T) {
+	summary := "修复登录页面的错误并添加测试用例 🚀🔥"
+	got := truncateSummary(summary, 20)
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "...") {
+		t.Errorf("expected a valid truncated summary, got %q", got)
+	}
+	if w := runewidth.StringWidth(got); w > 20 {
+		t.Errorf("expected at most 20 columns, got %d: %q", w, got)
+	}
+	if got := truncateSummary("短い", 20); got != "短い" {
+		t.Errorf("a short summary should be unchanged, got %q", got)
+	}
+}
+
+// TestVerboseProjectSummaries tests that verbose mode shows the latest summary under each project
+func TestVerboseProjectSummaries(t *testing.T) {
+	projects := []models.Project{
+		{Name: "alpha", Path: "/alpha", LatestSummary: "Fix the login bug"},
+		{Name: "beta", Path: "/beta"},
+	}
+
+	m := initialModel(projects)
+	m.width = 100
+	if view := m.renderProjects(); strings.Contains(view, "Fix the login bug") {
+		t.Error("summaries should only be shown in verbose mode")
+	}
+
+	m.opts.Verbose = true
+	view := m.renderProjects()
+	if !strings.Contains(view, "Fix the login bug") || !strings.Contains(view, "No Summary") {
+		t.Errorf("expected a summary line under each project:\n%s", view)
+	}
+	if lines := strings.Count(view, "\n"); lines != 4 {
+		t.Errorf("expected two lines per project, got %d lines", lines)
+	}
+
+	// The cursor scrolls by whole projects
+	m.viewport.Height = 2
+	m.projectCursor = 1
+	m.updateViewport()
+	if m.viewport.YOffset != 2 {
+		t.Errorf("expected the second project to be scrolled into view, got offset %d", m.viewport.YOffset)
+	}
+}
+
+// TestSingleTickLoop tests that overlapping spinner tick loops collapse into one
+func TestSingleTickLoop(t *testing.T) {
+	m := initialModel([]models.Project{{Name: "test", Path: "/test"}})
+	m.loadingState = sessions.StateLoadingProjects
+	start := time.Now()
+
+	updatedModel, cmd := m.Update(TickMsg(start))
+	m = updatedModel.(model)
+	if cmd == nil {
+		t.Fatal("a tick while loading should schedule the next frame")
+	}
+
+	// A second loop started by another load ticks in between
+	updatedModel, cmd = m.Update(TickMsg(start.Add(40 * time.Millisecond)))
+	m = updatedModel.(model)
+	if cmd != nil {
+		t.Error("a tick from a redundant loop should end that loop")
+	}
+
+	updatedModel, cmd = m.Update(TickMsg(start.Add(spinnerInterval)))
+	m = updatedModel.(model)
+	if cmd == nil {
+		t.Error("the original loop should keep running")
+	}
+
+	m.loadingState = sessions.StateIdle
+	if _, cmd = m.Update(TickMsg(start.Add(2 * spinnerInterval))); cmd != nil {
+		t.Error("ticking should stop once loading finishes")
+	}
+}
+
+// TestPreviewDebounce tests that previews load only once the cursor rests on a session
+func TestPreviewDebounce(t *testing.T) {
+	project := models.Project{Name: "test", Path: "/test", Sessions: []models.Session{
+		{SessionID: "s1"}, {SessionID: "s2"}, {SessionID: "s3"},
+	}}
+	m := initialModel([]models.Project{project})
+	m.leftViewport.Width = 80
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.messageCache["s1"] = previewLines("[User] one")
+
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	updatedModel, _ := m.Update(down)
+	m = updatedModel.(model)
+	if len(m.activeRequests) != 0 || !m.loadingMessages["s2"] {
+		t.Fatal("moving the cursor should show the spinner without querying yet")
+	}
+	staleSeq := m.previewSeq
+
+	updatedModel, _ = m.Update(down)
+	m = updatedModel.(model)
+	if m.loadingMessages["s2"] || !m.loadingMessages["s3"] {
+		t.Error("the session scrolled past should no longer be loading")
+	}
+
+	// The debounce of the session scrolled past is ignored
+	updatedModel, cmd := m.Update(PreviewDebounceMsg{SessionID: "s2", Seq: staleSeq})
+	m = updatedModel.(model)
+	if cmd != nil || len(m.activeRequests) != 0 {
+		t.Error("a stale debounce should not load anything")
+	}
+
+	updatedModel, cmd = m.Update(PreviewDebounceMsg{SessionID: "s3", Seq: m.previewSeq})
+	m = updatedModel.(model)
+	if cmd == nil || m.activeRequests["messages-s3"] == nil {
+		t.Fatal("the preview should load once the cursor rests")
+	}
+
+	// Moving on cancels the in-flight fetch, whose cancellation is then ignored
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = updatedModel.(model)
+	if _, ok := m.activeRequests["messages-s3"]; ok || m.loadingMessages["s3"] {
+		t.Error("moving on should cancel the in-flight fetch")
+	}
+	updatedModel, _ = m.Update(MessagesLoadedMsg{SessionID: "s3", Error: context.Canceled})
+	m = updatedModel.(model)
+	if len(m.currentMessages) != 0 {
+		t.Errorf("a cancelled fetch should not show an error, got %v", m.currentMessages)
+	}
+}
+
+// TestErrorBanner tests that a failed load shows a dismissible banner over the list
+func TestErrorBanner(t *testing.T) {
+	m := initialModel([]models.Project{{Name: "p1", Path: "/p1"}})
+	m.renderer = newRenderer(false)
+	updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updatedModel.(model)
+
+	updatedModel, _ = m.Update(ProjectsLoadedMsg{Offset: 0, Error: fmt.Errorf("database is locked\nretry later")})
+	m = updatedModel.(model)
+	view := m.View()
+	if !strings.Contains(view, "Error: database is locked") || strings.Contains(view, "retry later") {
+		t.Errorf("the banner should show the first line of the error, got %q", view)
+	}
+	if !strings.Contains(view, "p1") {
+		t.Error("the project list should stay visible under the banner")
+	}
+	if !strings.Contains(m.renderFooter(), "r: retry • esc: dismiss") {
+		t.Errorf("unexpected footer %q", m.renderFooter())
+	}
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updatedModel.(model)
+	if m.err != nil || m.loadingState != sessions.StateLoadingProjects || cmd == nil {
+		t.Error("r should retry loading the projects")
+	}
+	if m.currentMode != projectView {
+		t.Error("r should not open the recent sessions while retrying")
+	}
+
+	// A failed preview no longer shows up as a message
+	project := models.Project{Name: "p1", Path: "/p1", Sessions: []models.Session{{SessionID: "s1"}}}
+	m.loadingState = sessions.StateIdle
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	updatedModel, _ = m.Update(MessagesLoadedMsg{SessionID: "s1", Error: fmt.Errorf("IO Error")})
+	m = updatedModel.(model)
+	if m.err == nil || len(m.currentMessages) != 0 {
+		t.Errorf("the preview error should be shown in the banner, got messages %v", m.currentMessages)
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updatedModel.(model)
+	if m.err != nil || m.currentMode != sessionView {
+		t.Error("esc should dismiss the banner without leaving the sessions")
+	}
+}
+
+// TestSessionDetailHeader tests that the detail of the highlighted session is shown above its preview
+func TestSessionDetailHeader(t *testing.T) {
+	project := models.Project{Name: "test", Path: "/test", Sessions: []models.Session{{SessionID: "s1"}}}
+	m := initialModel([]models.Project{project})
+	m.leftViewport.Width = 80
+	m.renderer = newRenderer(false)
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.rightViewport.Width = 80
+
+	detail := &models.SessionDetail{
+		SessionID:    "s1",
+		ProjectPath:  "/test",
+		MessageCount: 12,
+		ToolCalls:    1,
+		IsResumed:    true,
+		GitBranch:    "feature/login",
+	}
+	updatedModel, _ := m.Update(MessagesLoadedMsg{SessionID: "s1", Messages: previewLines("[User] hi"), Detail: detail})
+	m = updatedModel.(model)
+
+	view := m.renderMessages()
+	for _, want := range []string{"Session  s1", "Project  /test", "Branch   feature/login", "Messages 12 (1 tool call)", "Resumed  yes"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the preview header:\n%s", want, view)
+		}
+	}
+
+	// Without a recorded branch the line is left out
+	detail.GitBranch = ""
+	if strings.Contains(m.renderMessages(), "Branch") {
+		t.Error("an unknown branch should not be shown")
+	}
+}
+
+// TestSessionListBranch tests that the session list shows each session's git branch
+func TestSessionListBranch(t *testing.T) {
+	project := models.Project{Name: "test", Path: "/test", Sessions: []models.Session{
+		{SessionID: "s1", GitBranch: "feature/login"},
+		{SessionID: "s2"},
+	}}
+	m := initialModel([]models.Project{project})
+	m.renderer = newRenderer(false)
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.leftViewport.Width = 80
+
+	list := m.renderSessionsList()
+	if strings.Count(list, "⎇") != 1 || !strings.Contains(list, "⎇ feature/login") {
+		t.Errorf("expected the branch of s1 only:\n%s", list)
+	}
+}
+
+// TestSessionListDuration tests that the session list shows how long each session lasted
+func TestSessionListDuration(t *testing.T) {
+	project := models.Project{Name: "test", Path: "/test", Sessions: []models.Session{
+		{SessionID: "s1", Duration: 83 * time.Minute},
+		{SessionID: "s2"},
+	}}
+	m := initialModel([]models.Project{project})
+	m.renderer = newRenderer(false)
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.leftViewport.Width = 80
+
+	list := m.renderSessionsList()
+	if strings.Count(list, "⏲") != 1 || !strings.Contains(list, "⏲ 1h23m") {
+		t.Errorf("expected the duration of s1 only:\n%s", list)
+	}
+}
+
+// TestSelectionRoundTrip tests that the last selection survives a save and load
+func TestSelectionRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claude-resume", "selection.json")
+
+	selection, err := loadSelection(path)
+	if err != nil || selection != (LastSelection{}) {
+		t.Fatalf("expected an empty selection without a file, got %+v, %v", selection, err)
+	}
+
+	want := LastSelection{ProjectPath: "/work/api", SessionID: "abc"}
+	if err := saveSelection(path, want); err != nil {
+		t.Fatalf("saveSelection failed: %v", err)
+	}
+	if got, err := loadSelection(path); err != nil || got != want {
+		t.Errorf("loadSelection() = %+v, %v, want %+v", got, err, want)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSelection(path); err == nil {
+		t.Error("expected an error for a malformed file")
+	}
+}
+
+// TestRestoreSelection tests that the cursor starts on the project and session
+// selected last time, and that the selection is only restored once
+func TestRestoreSelection(t *testing.T) {
+	m := initialModel(nil)
+	m.leftViewport.Width = 80
+	m.restore = &LastSelection{ProjectPath: "/p2", SessionID: "s2"}
+
+	updatedModel, _ := m.Update(ProjectsLoadedMsg{Projects: []models.Project{
+		{Name: "p1", Path: "/p1"},
+		{Name: "p2", Path: "/p2"},
+	}, Total: 2})
+	m = updatedModel.(model)
+	if m.projectCursor != 1 {
+		t.Fatalf("expected the cursor on the last selected project, got %d", m.projectCursor)
+	}
+
+	project := m.projects[m.projectCursor]
+	m.selectedProject = &project
+	updatedModel, _ = m.Update(SessionsLoadedMsg{ProjectPath: "/p2", Sessions: []models.Session{
+		{SessionID: "s1"},
+		{SessionID: "s2"},
+	}, Total: 2})
+	m = updatedModel.(model)
+	if m.sessionCursor != 1 {
+		t.Errorf("expected the cursor on the last selected session, got %d", m.sessionCursor)
+	}
+	if !m.loadingMessages["s2"] {
+		t.Error("expected the preview of the restored session to load")
+	}
+	if selection, ok := m.currentSelection(); !ok || selection != (LastSelection{ProjectPath: "/p2", SessionID: "s2"}) {
+		t.Errorf("currentSelection() = %+v, %v", selection, ok)
+	}
+
+	updatedModel, _ = m.Update(SessionsLoadedMsg{ProjectPath: "/p2", Sessions: []models.Session{
+		{SessionID: "s1"},
+		{SessionID: "s2"},
+	}, Total: 2})
+	m = updatedModel.(model)
+	if m.sessionCursor != 0 {
+		t.Errorf("expected the selection to be restored only once, got cursor %d", m.sessionCursor)
+	}
+}
+
+// TestQuickJump tests jumping to numbered projects with one and two digits
+func TestQuickJump(t *testing.T) {
+	projects := make([]models.Project, 12)
+	for i := range projects {
+		projects[i] = models.Project{Name: fmt.Sprintf("p%d", i+1), Path: fmt.Sprintf("/p%d", i+1)}
+	}
+	m := initialModel(projects)
+	digit := func(r rune) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}} }
+
+	if !strings.Contains(m.renderProjects(), "> 1. p1 (") {
+		t.Fatalf("expected numbered projects, got:\n%s", m.renderProjects())
+	}
+
+	// No two-digit number starts with 3, so the jump happens right away
+	updatedModel, _ := m.Update(digit('3'))
+	m = updatedModel.(model)
+	if m.projectCursor != 2 || m.jumpDigits != "" {
+		t.Fatalf("expected to jump to the third project, got cursor %d with %q buffered", m.projectCursor, m.jumpDigits)
+	}
+
+	// 1 could start 10, 11 or 12, so it waits for the second digit
+	updatedModel, cmd := m.Update(digit('1'))
+	m = updatedModel.(model)
+	if m.projectCursor != 2 || m.jumpDigits != "1" || cmd == nil {
+		t.Fatalf("expected the digit to be buffered, got cursor %d with %q buffered", m.projectCursor, m.jumpDigits)
+	}
+	updatedModel, _ = m.Update(digit('1'))
+	m = updatedModel.(model)
+	if m.projectCursor != 10 || m.jumpDigits != "" {
+		t.Fatalf("expected to jump to the eleventh project, got %d", m.projectCursor)
+	}
+
+	// A buffered digit resolves on timeout, but not on the timeout of an earlier digit
+	updatedModel, _ = m.Update(digit('1'))
+	m = updatedModel.(model)
+	updatedModel, _ = m.Update(JumpTimeoutMsg{Seq: m.jumpSeq - 1})
+	m = updatedModel.(model)
+	if m.jumpDigits != "1" {
+		t.Fatal("a stale timeout should leave the digit buffered")
+	}
+	updatedModel, _ = m.Update(JumpTimeoutMsg{Seq: m.jumpSeq})
+	m = updatedModel.(model)
+	if m.projectCursor != 0 || m.jumpDigits != "" {
+		t.Fatalf("expected to jump to the first project on timeout, got %d", m.projectCursor)
+	}
+
+	// Another key resolves the buffer first and is then handled as usual
+	updatedModel, _ = m.Update(digit('1'))
+	m = updatedModel.(model)
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updatedModel.(model)
+	if m.projectCursor != 1 || m.jumpDigits != "" {
+		t.Errorf("expected to jump to the first project and move down, got %d", m.projectCursor)
+	}
+}
+
+// TestStyleForAge tests that sessions are colored by how long ago they were
+// active, with configurable thresholds, and plain when color is disabled
+func TestStyleForAge(t *testing.T) {
+	m := initialModel(nil)
+	m.renderer = newRenderer(false)
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		age   time.Duration
+		color lipgloss.Color
+	}{
+		{"today", time.Hour, freshColor},
+		{"this week", 3 * 24 * time.Hour, recentColor},
+		{"older", 30 * 24 * time.Hour, staleColor},
+	}
+	for _, tt := range tests {
+		if got := m.styleForAge(now.Add(-tt.age)).GetForeground(); got != tt.color {
+			t.Errorf("%s: got color %v, want %v", tt.name, got, tt.color)
+		}
+	}
+
+	m.opts.FreshAge = 30 * time.Minute
+	m.opts.RecentAge = 2 * time.Hour
+	if got := m.styleForAge(now.Add(-time.Hour)).GetForeground(); got != recentColor {
+		t.Errorf("an hour old session should be recent with a 30m fresh age, got %v", got)
+	}
+	if got := m.styleForAge(now.Add(-3 * time.Hour)).GetForeground(); got != staleColor {
+		t.Errorf("a 3h old session should be stale with a 2h recent age, got %v", got)
+	}
+
+	if rendered := m.styleForAge(now).Render("Last Active"); rendered != "Last Active" {
+		t.Errorf("expected plain text with color disabled, got %q", rendered)
+	}
+}
+
+// TestOpenDirCommand tests the command the o key opens a project directory with
+func TestOpenDirCommand(t *testing.T) {
+	t.Setenv("EDITOR", "nvim -R")
+	tests := []struct {
+		openCmd string
+		want    string
+	}{
+		{"code --new-window", "code --new-window /src/app"},
+		{"tmux new-window -c {dir}", "tmux new-window -c /src/app"},
+		{"", "nvim -R /src/app"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(openDirCommand(tt.openCmd, "/src/app"), " "); got != tt.want {
+			t.Errorf("openDirCommand(%q) = %q, want %q", tt.openCmd, got, tt.want)
+		}
+	}
+
+	t.Setenv("EDITOR", "")
+	if got := openDirCommand("", "/src/app"); len(got) != 2 || got[1] != "/src/app" {
+		t.Errorf("expected the file manager without $EDITOR, got %v", got)
+	}
+}
+
+// TestOpenMissingProjectDir tests that o reports a project directory that no
+// longer exists instead of launching anything
+func TestOpenMissingProjectDir(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "gone")
+	m := initialModel([]models.Project{{Name: "gone", Path: missing}})
+	m.renderer = newRenderer(false)
+	m.opts.OpenCmd = "false"
+	updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updatedModel.(model)
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = updatedModel.(model)
+	if !strings.Contains(m.statusMessage, missing+" no longer exists") {
+		t.Errorf("expected a missing directory status, got %q", m.statusMessage)
+	}
+}
+
+// TestActiveSessionMarker tests that a session found still being written is
+// marked in the list and warned about before resuming
+func TestActiveSessionMarker(t *testing.T) {
+	dir := t.TempDir()
+	project := models.Project{Name: "test", Path: dir, Sessions: []models.Session{
+		{SessionID: "s1", ProjectPath: dir},
+		{SessionID: "s2", ProjectPath: dir, Active: true},
+	}}
+	m := initialModel([]models.Project{project})
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.leftViewport.Width = 80
+
+	updatedModel, _ := m.Update(ActiveSessionsMsg{SessionIDs: []string{"s1", "s2"}, Active: map[string]bool{"s1": true}})
+	m = updatedModel.(model)
+	list := m.renderSessionsList()
+	if strings.Count(list, activeMarker) != 1 {
+		t.Errorf("expected s1 only to be marked active:\n%s", list)
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(model)
+	if !strings.Contains(m.renderConfirm(), "still being written") {
+		t.Errorf("expected a warning on the confirmation screen:\n%s", m.renderConfirm())
+	}
+}
+
+// TestGroupByDay tests that day headers separate the sessions of different days
+// and are accounted for when scrolling to a session
+func TestGroupByDay(t *testing.T) {
+	now := time.Now()
+	if got := dayLabel(now, now); got != "Today" {
+		t.Errorf("got %q for today", got)
+	}
+	if got := dayLabel(now.AddDate(0, 0, -1), now); got != "Yesterday" {
+		t.Errorf("got %q for yesterday", got)
+	}
+	old := time.Date(2024, 1, 5, 12, 0, 0, 0, time.Local)
+	if got := dayLabel(old, now); got != "2024-01-05" {
+		t.Errorf("got %q for an older day", got)
+	}
+
+	project := models.Project{Name: "test", Path: "/test", Sessions: []models.Session{
+		{SessionID: "s1", LastActivity: now},
+		{SessionID: "s2", LastActivity: now},
+		{SessionID: "s3", LastActivity: old},
+	}}
+	m := initialModel([]models.Project{project})
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.leftViewport.Width = 80
+
+	if list := m.renderSessionsList(); strings.Contains(list, "── Today ──") {
+		t.Errorf("expected no day headers without grouping:\n%s", list)
+	}
+	flatFirst, _ := m.sessionLines(2)
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	m = updatedModel.(model)
+	list := m.renderSessionsList()
+	if strings.Count(list, "── Today ──") != 1 || strings.Count(list, "── 2024-01-05 ──") != 1 {
+		t.Errorf("expected a header for each day:\n%s", list)
+	}
+	// The second day's header sits where the third session began, with one more header above it
+	if first, last := m.sessionLines(2); first != flatFirst+1 || last != first+3 {
+		t.Errorf("got lines %d-%d for the third session, want %d-%d", first, last, flatFirst+1, flatFirst+4)
+	}
+	lines := strings.Split(list, "\n")
+	if first, _ := m.sessionLines(2); !strings.Contains(lines[first], "2024-01-05") {
+		t.Errorf("expected line %d to be the day header:\n%s", first, list)
+	}
+}
+
+// TestFullIDs tests that session IDs are shortened in proportion to the list
+// width, and shown whole when asked and they fit
+func TestFullIDs(t *testing.T) {
+	id := "3f2a9c1e-8b7d-4e6f-a5c4-1d2e3f4a5b6c"
+	tests := []struct {
+		width int
+		full  bool
+		want  string
+	}{
+		{30, false, "3f2a9c1e-8b7..."},
+		{90, false, "3f2a9c1e-8b7d-4e6f-a5c4-1d2e3f..."},
+		{200, false, id},
+		{40, true, id},
+		{30, true, "3f2a9c1e-8b7d-4e6f-a5c4-1d2..."},
+		{10, true, "3f2a9c1e-8b7..."},
+	}
+	for _, tt := range tests {
+		if got := shortSessionID(id, tt.width, tt.full); got != tt.want {
+			t.Errorf("shortSessionID(%d, %v) = %q, want %q", tt.width, tt.full, got, tt.want)
+		}
+	}
+
+	project := models.Project{Name: "test", Path: "/test", Sessions: []models.Session{{SessionID: id, LastActivity: time.Now()}}}
+	m := initialModel([]models.Project{project})
+	m.selectedProject = &project
+	m.currentMode = sessionView
+	m.leftViewport.Width = 60
+	if strings.Contains(m.renderSessionsList(), id) {
+		t.Errorf("expected a shortened ID by default")
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+	m = updatedModel.(model)
+	if !strings.Contains(m.renderSessionsList(), id) {
+		t.Errorf("expected the whole ID after I:\n%s", m.renderSessionsList())
+	}
+}
+
+// TestLoadProjectsProgress tests that loading projects reports the enumeration
+// of the session files before the projects arrive, and that the reports drive
+// the loading indicator only while projects are loading
+func TestLoadProjectsProgress(t *testing.T) {
+	dir := t.TempDir()
+	sessions.SetProjectsDir(dir)
+	t.Cleanup(func() { sessions.SetProjectsDir("") })
+	path := filepath.Join(dir, "-tmp-project", "s1.jsonl")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var reports []SQLProgressMsg
+	cmd := loadProjectsCmd(context.Background(), 0)
+	for {
+		msg := cmd()
+		progress, ok := msg.(SQLProgressMsg)
+		if !ok {
+			if _, ok := msg.(ProjectsLoadedMsg); !ok {
+				t.Fatalf("got %T, want the projects after the reports", msg)
+			}
+			break
+		}
+		reports = append(reports, progress)
+		cmd = progress.next
+	}
+	if len(reports) == 0 {
+		t.Fatal("expected progress reports before the projects")
+	}
+	last := reports[len(reports)-1]
+	if last.Progress >= 0 || last.Message != "Reading 1 session files (3 B)..." {
+		t.Errorf("got last report %+v, want the indeterminate read of the files found", last)
+	}
+
+	m := initialModel(nil)
+	m.loadingState = sessions.StateLoadingProjects
+	updatedModel, next := m.Update(SQLProgressMsg{Progress: 50, Message: "Found 3 session files (1 KB)...", next: tickCmd()})
+	m = updatedModel.(model)
+	if next == nil {
+		t.Error("expected the load to be waited for")
+	}
+	if view := m.loadingIndicator.View(); !strings.Contains(view, "Found 3 session files") || !strings.Contains(view, "50%") {
+		t.Errorf("expected the report with a progress bar, got %q", view)
+	}
+	updatedModel, _ = m.Update(SQLProgressMsg{Progress: -1, Message: "Reading 3 session files (1 KB)..."})
+	m = updatedModel.(model)
+	if view := m.loadingIndicator.View(); strings.Contains(view, "%") {
+		t.Errorf("expected no progress bar while reading, got %q", view)
+	}
+
+	m.loadingState = sessions.StateIdle
+	updatedModel, _ = m.Update(SQLProgressMsg{Progress: 10, Message: "late"})
+	m = updatedModel.(model)
+	if strings.Contains(m.loadingIndicator.View(), "late") {
+		t.Error("expected a report arriving after the load to be ignored")
+	}
+}
+
+// TestIdleTimeout tests that the TUI quits without resuming once no key was
+// pressed for the idle timeout, and that a keypress postpones it
+func TestIdleTimeout(t *testing.T) {
+	project := models.Project{Name: "test", Path: "/test", Sessions: []models.Session{{SessionID: "s1"}}}
+	m := initialModel([]models.Project{project})
+	m.opts.IdleTimeout = time.Minute
+	selected := project.Sessions[0]
+	m.selectedSession = &selected
+	m.currentMode = confirmView
+
+	start := time.Now().Add(-time.Hour)
+	m.lastKey = start
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updatedModel.(model)
+	if !m.lastKey.After(start) {
+		t.Fatal("expected a keypress to reset the idle timer")
+	}
+
+	// Half the timeout after the last keypress the timer is only rearmed
+	lastKey := m.lastKey
+	updatedModel, cmd := m.Update(IdleTimeoutMsg(lastKey.Add(30 * time.Second)))
+	m = updatedModel.(model)
+	if cmd == nil || m.selectedSession == nil {
+		t.Fatal("expected the idle timer to be rearmed without quitting")
+	}
+
+	updatedModel, cmd = m.Update(IdleTimeoutMsg(lastKey.Add(time.Minute)))
+	m = updatedModel.(model)
+	if cmd == nil {
+		t.Fatal("expected a command quitting the TUI")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected the TUI to quit once idle for the timeout")
+	}
+	if m.selectedSession != nil {
+		t.Error("expected nothing to be resumed after an idle quit")
+	}
+}
+
+// previewLines returns undated preview lines holding texts
+func previewLines(texts ...string) []sessions.PreviewLine {
+	lines := make([]sessions.PreviewLine, len(texts))
+	for i, text := range texts {
+		lines[i] = sessions.PreviewLine{Text: text}
+	}
+	return lines
+}
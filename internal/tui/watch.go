@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/strrl/claude-resume/internal/sessions"
+	"github.com/strrl/claude-resume/pkg/models"
+)
+
+// handleFilesChecked refreshes the listings once session files have changed and
+// writes have settled for one check interval, or after watchMaxDelay at the latest
+func (m model) handleFilesChecked(msg FilesCheckedMsg) (tea.Model, tea.Cmd) {
+	next := watchTickCmd()
+	if msg.Error != nil {
+		return m, next
+	}
+
+	// The first check establishes the baseline
+	if m.watchModTime.IsZero() {
+		m.watchModTime = msg.ModTime
+		return m, next
+	}
+	if !msg.ModTime.After(m.watchModTime) {
+		m.watchPending = time.Time{}
+		return m, next
+	}
+
+	if !msg.ModTime.Equal(m.watchPending) {
+		if m.watchPending.IsZero() {
+			m.watchSince = msg.CheckedAt
+		}
+		m.watchPending = msg.ModTime
+		if msg.CheckedAt.Sub(m.watchSince) < watchMaxDelay {
+			return m, next
+		}
+	}
+
+	// Don't interfere with loads, confirmations, deletes or refreshes in progress; retry on the next check
+	if m.loadingState != sessions.StateIdle || m.currentMode == confirmView || m.pendingDelete != nil || m.refreshing > 0 {
+		return m, next
+	}
+
+	m.watchModTime = msg.ModTime
+	m.watchPending = time.Time{}
+
+	cmds := []tea.Cmd{next, refreshProjectsCmd(m.ctx, m.projectOffset)}
+	if m.currentMode == sessionView && m.selectedProject != nil {
+		cmds = append(cmds, refreshSessionsCmd(m.ctx, m.selectedProject.Path, m.sessionOffset))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// applyProjectsRefresh replaces the project list, keeping the cursor on the same project
+func (m model) applyProjectsRefresh(msg ProjectsLoadedMsg) model {
+	// Errors are not worth interrupting the user for, and a page change supersedes the refresh
+	if msg.Error != nil || msg.Offset != m.projectOffset || m.loadingState == sessions.StateLoadingProjects {
+		return m
+	}
+
+	selectedPath := ""
+	if m.projectCursor < len(m.projects) {
+		selectedPath = m.projects[m.projectCursor].Path
+	}
+
+	m.projects = msg.Projects
+	m.projectTotal = msg.Total
+	m.emptyGuidance = msg.EmptyGuidance
+	m.scanLimit = msg.ScanLimit
+	m.projectCursor = 0
+	for i, project := range m.projects {
+		if project.Path == selectedPath {
+			m.projectCursor = i
+			break
+		}
+	}
+	m.updateViewport()
+	return m
+}
+
+// applySessionsRefresh replaces the session list, keeping the cursor on the same session.
+// Cached previews of sessions with new activity are dropped and reloaded.
+func (m model) applySessionsRefresh(msg SessionsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.Error != nil || m.selectedProject == nil || msg.ProjectPath != m.selectedProject.Path ||
+		msg.Offset != m.sessionOffset || m.loadingState == sessions.StateLoadingSessions {
+		return m, nil
+	}
+
+	previous := make(map[string]models.Session, len(m.selectedProject.Sessions))
+	for _, session := range m.selectedProject.Sessions {
+		previous[session.SessionID] = session
+	}
+	selectedID := ""
+	if m.sessionCursor < len(m.selectedProject.Sessions) {
+		selectedID = m.selectedProject.Sessions[m.sessionCursor].SessionID
+	}
+
+	sessionIDs := make([]string, len(msg.Sessions))
+	for i, session := range msg.Sessions {
+		sessionIDs[i] = session.SessionID
+		old, ok := previous[session.SessionID]
+		if !ok {
+			continue
+		}
+		msg.Sessions[i].Summary = old.Summary // Keep showing the summary until it reloads
+		msg.Sessions[i].SummarySource = old.SummarySource
+		msg.Sessions[i].ResumedFrom = old.ResumedFrom
+		msg.Sessions[i].Active = old.Active // Until probed again
+		if !old.LastActivity.Equal(session.LastActivity) {
+			m.forgetMessages(session.SessionID)
+		}
+	}
+
+	m.selectedProject.Sessions = msg.Sessions
+	m.sessionTotal = msg.Total
+	m.sessionCursor = 0
+	for i, session := range msg.Sessions {
+		if session.SessionID == selectedID {
+			m.sessionCursor = i
+			break
+		}
+	}
+
+	cmds := []tea.Cmd{markActiveCmd(msg.Sessions)}
+	if len(sessionIDs) > 0 {
+		cmds = append(cmds, loadSummariesCmd(m.ctx, msg.ProjectPath, sessionIDs))
+	}
+	if m.sessionCursor < len(msg.Sessions) {
+		current := msg.Sessions[m.sessionCursor]
+		if cached, ok := m.messageCache[current.SessionID]; ok {
+			m.currentMessages = cached
+		} else if !m.loadingMessages[current.SessionID] {
+			// Keep the stale preview on screen while the new one loads
+			cmds = append(cmds, loadMessagesCmd(m.ctx, current.SessionID))
+		}
+	}
+	m.updateViewport()
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,85 @@
+// Package version reports which build of claude-resume is running
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Set at build time with -ldflags, e.g.
+//
+//	-X github.com/strrl/claude-resume/internal/version.Version=v1.2.3
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// duckDBModule is the module of the DuckDB driver, whose bundled engine runs every query
+const duckDBModule = "github.com/marcboeker/go-duckdb"
+
+// Info describes a build
+type Info struct {
+	Version   string
+	Commit    string
+	Date      string
+	GoVersion string
+	DuckDB    string // Version of the DuckDB driver module
+}
+
+// Get returns the build info. The commit and date fall back to the VCS stamp Go
+// records in the binary when they weren't set with -ldflags.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		DuckDB:    "unknown",
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info.withDefaults()
+	}
+	for _, dep := range build.Deps {
+		if dep.Path == duckDBModule {
+			info.DuckDB = dep.Version
+			if dep.Replace != nil {
+				info.DuckDB = dep.Replace.Version
+			}
+		}
+	}
+	for _, setting := range build.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = setting.Value
+		case setting.Key == "vcs.time" && info.Date == "":
+			info.Date = setting.Value
+		}
+	}
+	return info.withDefaults()
+}
+
+func (i Info) withDefaults() Info {
+	if i.Commit == "" {
+		i.Commit = "unknown"
+	}
+	if i.Date == "" {
+		i.Date = "unknown"
+	}
+	return i
+}
+
+// String formats the build info for the version command and --version
+func (i Info) String() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "claude-resume %s\n", i.Version)
+	fmt.Fprintf(&s, "  commit:        %s\n", i.Commit)
+	fmt.Fprintf(&s, "  built:         %s\n", i.Date)
+	fmt.Fprintf(&s, "  go:            %s\n", i.GoVersion)
+	fmt.Fprintf(&s, "  duckdb driver: %s\n", i.DuckDB)
+	return s.String()
+}
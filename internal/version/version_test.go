@@ -0,0 +1,28 @@
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGet tests that values set with -ldflags win and that every field is filled
+func TestGet(t *testing.T) {
+	defer func(version, commit, date string) {
+		Version, Commit, Date = version, commit, date
+	}(Version, Commit, Date)
+	Version, Commit, Date = "v1.2.3", "abc1234", "2025-01-01T00:00:00Z"
+
+	info := Get()
+	if info.Version != "v1.2.3" || info.Commit != "abc1234" || info.Date != "2025-01-01T00:00:00Z" {
+		t.Errorf("expected the -ldflags values, got %+v", info)
+	}
+	if !strings.HasPrefix(info.GoVersion, "go") {
+		t.Errorf("unexpected Go version %q", info.GoVersion)
+	}
+	if info.DuckDB == "" {
+		t.Error("expected a DuckDB driver version")
+	}
+	if out := info.String(); !strings.HasPrefix(out, "claude-resume v1.2.3\n") || !strings.Contains(out, "commit:        abc1234") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
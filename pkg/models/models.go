@@ -7,8 +7,45 @@ type Session struct {
 	SessionID    string
 	ProjectPath  string
 	LastActivity time.Time
-	Summary      string // First user message or brief summary
+	Duration     time.Duration // Time between the first and the last event
+	Summary      string        // Summary written by Claude Code, or a title derived from the messages
 	IsResumed    bool   // Whether this session was resumed/continued
+	ResumedFrom  string // Session this one was resumed from, empty until loaded
+	Favorite     bool   // Starred by the user, see sessions.ToggleFavorite
+	Label        string // Nickname given by the user, see sessions.SetLabel
+	GitBranch    string // Branch most recently recorded in the session, empty when not recorded
+	Active       bool   // Still being written, e.g. by claude in another terminal, see sessions.MarkActive
+	MultipleDirs bool   // Moved to other directories with cd; ProjectPath is where it started
+
+	SummarySource SummarySource // Where Summary comes from
+}
+
+// SummarySource tells where the summary of a session comes from
+type SummarySource int
+
+const (
+	NoSummary     SummarySource = iota // No summary was found
+	ClaudeSummary                      // Summary event written by Claude Code
+	FirstPrompt                        // First text the user typed
+	FirstReply                         // First text the assistant replied
+)
+
+// Generated reports whether the summary was derived from the messages rather
+// than written by Claude Code
+func (s SummarySource) Generated() bool {
+	return s == FirstPrompt || s == FirstReply
+}
+
+// SessionDetail holds the facts about a session shown before resuming it
+type SessionDetail struct {
+	SessionID    string
+	ProjectPath  string
+	CreatedAt    time.Time
+	LastActivity time.Time
+	MessageCount int    // Messages of the selected types, each counted once, without tool results
+	ToolCalls    int    // Tool invocations made by the assistant
+	IsResumed    bool   // Whether this session was resumed/continued
+	GitBranch    string // Branch checked out in the project, empty when not recorded
 }
 
 // Project represents a project with aggregated session information
@@ -16,6 +53,26 @@ type Project struct {
 	Name         string
 	Path         string
 	SessionCount int
+	MessageCount int // Messages across the sessions, counted as in SessionDetail
 	LastActivity time.Time
 	Sessions     []Session // Lazily loaded when needed
-}
\ No newline at end of file
+
+	LatestSessionID string // Most recently active session
+	LatestSummary   string // Summary of LatestSessionID, only loaded with project summaries enabled
+}
+
+// Message represents a single message in a Claude Code conversation
+type Message struct {
+	Role        string // "user" or "assistant"
+	Content     string // Text content with system reminders removed
+	ToolCalls   []ToolCall
+	ToolResults []string
+	Timestamp   time.Time
+}
+
+// ToolCall represents a tool invocation made by the assistant
+type ToolCall struct {
+	Name      string
+	Input     string    // JSON-encoded tool input
+	Timestamp time.Time // When the assistant message containing the call was recorded
+}